@@ -1,15 +1,42 @@
 package main
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	_ "marketplace-api/docs"
 	"marketplace-api/internal/advertisement"
+	v1 "marketplace-api/internal/api/v1"
+	"marketplace-api/internal/apikey"
+	"marketplace-api/internal/auction"
 	"marketplace-api/internal/auth"
+	"marketplace-api/internal/cache"
+	"marketplace-api/internal/config"
 	"marketplace-api/internal/db"
+	"marketplace-api/internal/health"
+	"marketplace-api/internal/idempotency"
+	"marketplace-api/internal/logging"
+	"marketplace-api/internal/metrics"
+	"marketplace-api/internal/middleware"
+	"marketplace-api/internal/migrate"
+	"marketplace-api/internal/notification"
+	"marketplace-api/internal/outbox"
+	"marketplace-api/internal/promotion"
+	"marketplace-api/internal/ratelimit"
+	"marketplace-api/internal/router"
+	"marketplace-api/internal/server"
+	"marketplace-api/internal/stats"
+	"marketplace-api/internal/tracing"
 	"marketplace-api/internal/user"
+	"marketplace-api/internal/webhook"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
@@ -27,62 +54,377 @@ import (
 // @securityDefinitions.apikey AuthToken
 // @in header
 // @name Authorization
-// @description Введите JWT токен с префиксом Bearer
+// @description Введите JWT токен или API-ключ (mk_...) с префиксом Bearer
 
 // @schemes http
 
 func main() {
-	log.Println("marketplace-api is starting...")
+	// marketplace-api migrate up|down|status|redo [флаги конфигурации]
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+
+	logging.Setup(os.Stdout, logging.FormatJSON, slog.LevelInfo)
+	slog.Info("marketplace-api is starting")
+
+	//SIGTERM/SIGINT запускают плавную остановку
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	cfg, err := config.Load(os.Args[1:], os.LookupEnv)
+	if err != nil {
+		fatal("invalid configuration", err)
 	}
+	if cfg.PrintConfig {
+		if err := cfg.Print(os.Stdout); err != nil {
+			fatal("error printing configuration", err)
+		}
+		return
+	}
+	setupLogging(cfg)
 
-	secretKey := os.Getenv("JWT_SECRET")
-	if secretKey == "" {
-		secretKey = "supersecretjwtkey"
+	//Срок размещения объявлений
+	adLifetime := advertisement.Lifetime{
+		Default:    cfg.Advertisement.Lifetime,
+		ByCategory: cfg.Advertisement.LifetimeByCategory,
 	}
 
-	dsn := os.Getenv("DATABASE_DSN")
+	//Трассировка
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		Exporter:     cfg.Tracing.Exporter,
+		OTLPEndpoint: cfg.Tracing.OTLPEndpoint,
+		SampleRatio:  cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		fatal("error configuring tracing", err)
+	}
 
 	//Подключение к базе данных
-	pool := db.Connect(dsn)
+	pool, err := connectDB(cfg)
+	if err != nil {
+		fatal("error connecting to database", err)
+	}
+
+	//Схема базы должна соответствовать версии приложения
+	migrator, err := migrate.New(stdlib.OpenDBFromPool(pool))
+	if err != nil {
+		fatal("error loading migrations", err)
+	}
+	if cfg.Database.MigrateOnStart {
+		if err := migrator.Up(ctx); err != nil {
+			fatal("error applying migrations", err)
+		}
+	}
+	if err := migrator.EnsureCurrent(ctx); err != nil {
+		fatal("refusing to serve", err)
+	}
 
 	//Инциализация jwtManager
-	jwtManager := auth.NewJWTManager(secretKey)
+	jwtManager := auth.NewJWTManager(cfg.Auth.JWTSecret, cfg.Auth.TokenTTL)
+
+	//Транзакции и доменные события
+	txManager := db.NewTxManager(pool, 3)
+	outboxStore := outbox.NewPostgresStore(pool, time.Minute)
+	events := outbox.NewDispatcher()
 
 	//инциализация хэндлеров и сервисов
 	userRepo := user.NewRepository(pool)
-	userService := user.NewUserService(userRepo, jwtManager)
+	userService := user.NewUserService(userRepo, txManager, outboxStore, jwtManager)
 	userHandler := user.NewUserHandler(userService)
 
-	adRepo := advertisement.NewAdRepository(pool)
-	adService := advertisement.NewAdService(adRepo)
-	adHandler := advertisement.NewAdHandler(adService)
+	statsRepo := stats.NewStatsRepository(pool)
+	statsService := stats.NewStatsService(statsRepo)
+	statsHandler := stats.NewStatsHandler(statsService)
 
-	//http
-	mux := http.NewServeMux()
+	//Фоновые задачи, останавливаются после сервера
+	workers := server.NewGroup()
 
-	mux.HandleFunc("/register", userHandler.Register) //POST
-	mux.HandleFunc("/login", userHandler.Login)       //POST
+	var views advertisement.ViewRecorder
+	if cfg.Features.ViewStats {
+		viewRecorder := stats.NewRecorder(statsRepo, 30*time.Minute, []byte(cfg.Stats.ViewerSecret))
+		workers.Go("view-recorder", viewRecorder.Run)
+		views = viewRecorder
+	}
 
-	mux.Handle("/advertisement", auth.AuthMiddleware(jwtManager, http.HandlerFunc(adHandler.CreateAd)))        //POST
-	mux.Handle("/advertisement/", auth.OptionalAuthMiddleware(jwtManager, http.HandlerFunc(adHandler.ListAd))) //GET
+	rawAdRepo := advertisement.NewAdRepository(pool)
+	var adRepo advertisement.RepositoryInterface = rawAdRepo
+	if cfg.FeedCache.Enabled {
+		feed := cache.NewLRU[[]advertisement.AdvertisementList](cfg.FeedCache.Size, cfg.FeedCache.TTL)
+		adRepo = advertisement.NewCachedRepository(adRepo, feed)
+	}
+	adService := advertisement.NewAdService(adRepo, txManager, outboxStore, adLifetime, advertisement.Pagination{
+		DefaultLimit: cfg.Pagination.DefaultLimit,
+		MaxLimit:     cfg.Pagination.MaxLimit,
+	})
+	// Конфигурация уже проверена, ошибки разбора здесь не бывает
+	ips, _ := ratelimit.NewIPResolver(cfg.RateLimit.TrustedProxies)
+	adHandler := advertisement.NewAdHandler(adService, views, ips)
+	adImporter := advertisement.NewImporter(adService, txManager, rawAdRepo, 2*time.Minute)
+	importHandler := advertisement.NewImportHandler(adImporter)
+	adLinks := advertisement.Links{PublicURL: cfg.Server.PublicURL, PageURL: cfg.Advertisement.PageURL}
+	if adLinks.PageURL == "" {
+		adLinks.PageURL = cfg.Server.PublicURL + v1.Prefix + "/advertisement/{id}"
+	}
+	exportHandler := advertisement.NewExportHandler(adService, adLinks)
 
-	// Health check
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte("OK"))
+	auctionRepo := auction.NewAuctionRepository(pool)
+	auctionService := auction.NewAuctionService(auctionRepo, txManager, outboxStore, auction.AntiSniping{
+		Window:    5 * time.Minute,
+		Extension: 5 * time.Minute,
 	})
+	auctionHandler := auction.NewAuctionHandler(auctionService)
+
+	notifier := notification.NewLogNotifier()
+
+	workers.Go("expirer", advertisement.NewExpirer(adService, notifier, time.Minute, cfg.Advertisement.ExpiryReminder).Run)
+	workers.Go("scheduler", advertisement.NewScheduler(adService, 15*time.Second).Run)
+	workers.Go("advertisement-importer", advertisement.NewImportWorker(adImporter, 5*time.Second).Run)
+	workers.Go("auction-closer", auction.NewCloser(auctionService, 30*time.Second).Run)
+
+	events.Subscribe(auction.NotifySold(notifier), auction.EventSold)
+
+	var webhookHandler *webhook.Handler
+	if wh := cfg.Webhooks; wh.Enabled {
+		webhookService := webhook.NewWebhookService(webhook.NewWebhookRepository(pool), webhook.NewHTTPSender(wh.Timeout, wh.AllowInsecure), webhook.Policy{
+			AllowInsecure: wh.AllowInsecure,
+			MaxAttempts:   wh.MaxAttempts,
+			DisableAfter:  wh.DisableAfter,
+			RetryMin:      30 * time.Second,
+			RetryMax:      6 * time.Hour,
+			Lease:         2 * wh.Timeout,
+		})
+		webhookHandler = webhook.NewWebhookHandler(webhookService)
+		events.Subscribe(webhookService.HandleEvent, webhook.EventTypes...)
+		workers.Go("webhook-deliverer", webhook.NewDeliverer(webhookService, 5*time.Second).Run)
+	}
+
+	workers.Go("outbox-relay", outbox.NewRelay(outboxStore, events, time.Second, outbox.Retry{
+		Min:         time.Second,
+		Max:         5 * time.Minute,
+		MaxAttempts: 10,
+	}).Run)
+	workers.Go("outbox-janitor", outbox.NewJanitor(outboxStore, time.Hour, 7*24*time.Hour).Run)
+
+	//http
+	mux := router.New()
+	public := mux.Group()
+
+	apiHandlers := v1.Handlers{
+		User:          userHandler,
+		Advertisement: adHandler,
+		Import:        importHandler,
+		Export:        exportHandler,
+		Stats:         statsHandler,
+		Auction:       auctionHandler,
+		Webhook:       webhookHandler,
+	}
+
+	// Продвижение доступно только с платёжным провайдером; единственный пока провайдер -
+	// локальная замена, которую конфигурация разрешает лишь в dev
+	if cfg.Features.Promotions && !cfg.PaymentsEnabled() {
+		slog.Warn("promotions are disabled: payment provider is not configured")
+	}
+	if cfg.PaymentsEnabled() && cfg.Payments.Provider == config.PaymentProviderFake {
+		paymentProvider := promotion.NewFakeProvider(cfg.Payments.WebhookSecret, cfg.Server.PublicURL, cfg.Server.PublicURL+"/payments/webhook")
+		promotionRepo := promotion.NewPromotionRepository(pool)
+		promotionService := promotion.NewPromotionService(promotionRepo, paymentProvider, promotion.DefaultPricing)
+		apiHandlers.Promotion = promotion.NewPromotionHandler(promotionService, paymentProvider)
+
+		// Адреса для платёжного провайдера не версионируются
+		public.HandleFunc("POST /payments/webhook", apiHandlers.Promotion.PaymentWebhook)
+		public.HandleFunc("GET /payments/fake/pay", paymentProvider.Pay)
+	}
+
+	// API-ключи принимаются наравне с JWT, разрешения ключа проверяются на маршрутах
+	var keyAuthenticator auth.KeyAuthenticator
+	if cfg.Features.APIKeys {
+		apiKeyService := apikey.NewAPIKeyService(apikey.NewAPIKeyRepository(pool))
+		apiHandlers.APIKey = apikey.NewAPIKeyHandler(apiKeyService)
+		keyAuthenticator = apiKeyService
+	}
+
+	apiMiddlewares := v1.Middlewares{
+		RequireAuth:  auth.Required(jwtManager, keyAuthenticator),
+		OptionalAuth: auth.Optional(jwtManager, keyAuthenticator),
+		AdsRead:      auth.RequireScope(auth.ScopeAdsRead),
+		AdsWrite:     auth.RequireScope(auth.ScopeAdsWrite),
+		SessionOnly:  auth.RequireScope(""),
+	}
+	if cfg.RateLimit.Enabled {
+		apiMiddlewares.AuthLimit, apiMiddlewares.WriteLimit, apiMiddlewares.ReadLimit = rateLimiters(cfg.RateLimit, ips, pool, workers)
+	}
+	if cfg.Idempotency.Enabled {
+		apiMiddlewares.Idempotency = idempotencyMiddleware(cfg.Idempotency, pool, workers)
+	}
+	v1.Register(mux.Mount(v1.Prefix), apiHandlers, apiMiddlewares)
+
+	// Карта сайта описывает только адреса внутри своего каталога, поэтому она в корне
+	mux.Group(apiMiddlewares.ReadLimit).HandleFunc("GET /sitemap.xml", exportHandler.Sitemap)
+
+	// Старые адреса без версии - псевдонимы v1 до даты отключения
+	if cfg.API.LegacyRoutes {
+		legacy := mux.Group(middleware.Deprecated(cfg.API.LegacyDeprecatedAt(), cfg.API.LegacySunsetAt(), func(r *http.Request) string {
+			return v1.Prefix + r.URL.Path
+		}))
+		v1.Register(legacy, apiHandlers, apiMiddlewares)
+	}
+
+	// Пробы для оркестратора, /health оставлен для совместимости
+	healthRegistry := health.NewRegistry(health.NewPostgres(pool), cfg.Server.ReadinessTimeout)
+	healthRegistry.Register("workers", workers.Check)
+	public.HandleFunc("GET /livez", healthRegistry.Livez)
+	public.HandleFunc("GET /readyz", healthRegistry.Readyz)
+	public.HandleFunc("GET /health", healthRegistry.Livez)
+
+	//Метрики Prometheus
+	metrics.RegisterPool(pool)
+	public.Handle("GET /metrics", metrics.Handler())
 
 	//Swagger
-	mux.HandleFunc("/swagger/", httpSwagger.Handler(
-		httpSwagger.URL("http://localhost:"+port+"/swagger/doc.json"),
-	))
+	if cfg.Features.Swagger {
+		public.HandleFunc("GET /swagger/", httpSwagger.Handler(
+			httpSwagger.URL(cfg.Server.PublicURL+"/swagger/doc.json"),
+		))
+	}
+
+	// Общая цепочка middleware, первый выполняется первым. Трассировка и логирование
+	// снаружи, чтобы паника и отказ по лимитам попали в спан, лог и метрики запроса
+	chain := []middleware.Middleware{
+		tracing.Middleware,
+		logging.Middleware,
+		metrics.Middleware,
+		middleware.Recover,
+	}
+	if len(cfg.CORS.AllowedOrigins) > 0 {
+		chain = append(chain, middleware.CORS(middleware.CORSConfig{
+			AllowedOrigins:   cfg.CORS.AllowedOrigins,
+			AllowedMethods:   []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete},
+			AllowedHeaders:   cfg.CORS.AllowedHeaders,
+			ExposedHeaders:   cfg.CORS.ExposedHeaders,
+			AllowCredentials: cfg.CORS.AllowCredentials,
+			MaxAge:           cfg.CORS.MaxAge,
+		}))
+	}
+	chain = append(chain, middleware.BodyLimit(cfg.Server.MaxBodyBytes))
+	if cfg.Compression.Enabled {
+		chain = append(chain, middleware.Compress(cfg.Compression.MinSize))
+	}
 
 	// Запуск сервера
-	log.Printf("server is running http://localhost:%s", port)
-	if err := http.ListenAndServe(":"+port, mux); err != nil {
-		log.Fatalf("error starting server: %v", err)
+	srv, err := server.New(server.Config{
+		Addr:              ":" + strconv.Itoa(cfg.Server.Port),
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		MaxHeaderBytes:    cfg.Server.MaxHeaderBytes,
+		TLSCertFile:       cfg.Server.TLSCertFile,
+		TLSKeyFile:        cfg.Server.TLSKeyFile,
+	}, middleware.Chain(chain...)(mux))
+	if err != nil {
+		fatal("error configuring server", err)
+	}
+	if srv.TLS() {
+		workers.Go("tls-reloader", func(ctx context.Context) { srv.CertReloader().Run(ctx, time.Minute) })
+	}
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- srv.ListenAndServe() }()
+	slog.Info("server is listening", "port", cfg.Server.Port, "public_url", cfg.Server.PublicURL)
+
+	select {
+	case err := <-serveErr:
+		if err != nil {
+			fatal("error starting server", err)
+		}
+	case <-ctx.Done():
+	}
+	stop()
+
+	// Остановка: сначала readiness отдаёт 503, чтобы балансировщик снял трафик, затем
+	// новые соединения не принимаются, текущие запросы и фоновые задачи дорабатывают
+	// до общего дедлайна, после чего закрывается пул соединений с БД
+	slog.Info("marketplace-api is shutting down")
+	healthRegistry.SetShuttingDown()
+	time.Sleep(cfg.Server.ShutdownDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("error shutting down server", "error", err)
+	}
+	if err := workers.Stop(shutdownCtx); err != nil {
+		slog.Error("error stopping background workers", "error", err)
+	}
+	pool.Close()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("error flushing traces", "error", err)
 	}
+	slog.Info("marketplace-api stopped")
+}
+
+// connectDB - пул соединений с параметрами из конфигурации
+func connectDB(cfg *config.Config) (*pgxpool.Pool, error) {
+	return db.Connect(db.Config{
+		DSN:               cfg.Database.DSN,
+		MaxConns:          int32(cfg.Database.MaxConns),
+		MinConns:          int32(cfg.Database.MinConns),
+		MaxConnLifetime:   cfg.Database.MaxConnLifetime,
+		MaxConnIdleTime:   cfg.Database.MaxConnIdleTime,
+		HealthCheckPeriod: cfg.Database.HealthCheckPeriod,
+		ConnectTimeout:    cfg.Database.ConnectTimeout,
+		Tracer:            tracing.QueryTracer{},
+	})
+}
+
+// setupLogging - логгер с уровнем и форматом из конфигурации
+func setupLogging(cfg *config.Config) {
+	level, err := logging.ParseLevel(cfg.Log.Level)
+	if err != nil {
+		fatal("invalid log level", err)
+	}
+	if _, err := logging.Setup(os.Stdout, cfg.Log.Format, level); err != nil {
+		fatal("invalid log format", err)
+	}
+}
+
+// fatal - пишет ошибку в лог и завершает процесс
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// rateLimiters - middleware ограничения частоты для групп регистрации и входа, изменений и чтения.
+// Конфигурация уже проверена, поэтому ошибки разбора не возвращаются
+func rateLimiters(cfg config.RateLimit, ips *ratelimit.IPResolver, pool *pgxpool.Pool, workers *server.Group) (login, write, read middleware.Middleware) {
+	var store interface {
+		ratelimit.Store
+		ratelimit.Cleaner
+	} = ratelimit.NewMemoryStore()
+	if cfg.Store == "postgres" {
+		store = ratelimit.NewPostgresStore(pool)
+	}
+	workers.Go("ratelimit-janitor", ratelimit.NewJanitor(store, time.Minute, time.Hour).Run)
+
+	limiter := func(name, spec string) middleware.Middleware {
+		limit, _ := ratelimit.ParseLimit(spec)
+		return ratelimit.Middleware(store, name, limit, ips)
+	}
+	return limiter("auth", cfg.Auth), limiter("write", cfg.Write), limiter("read", cfg.Read)
+}
+
+// idempotencyMiddleware - повтор ответов по Idempotency-Key с периодической очисткой истёкших ключей
+func idempotencyMiddleware(cfg config.Idempotency, pool *pgxpool.Pool, workers *server.Group) middleware.Middleware {
+	var store interface {
+		idempotency.Store
+		idempotency.Cleaner
+	} = idempotency.NewMemoryStore(cfg.TTL, cfg.LockTimeout)
+	if cfg.Store == "postgres" {
+		store = idempotency.NewPostgresStore(pool, cfg.TTL, cfg.LockTimeout)
+	}
+	workers.Go("idempotency-janitor", idempotency.NewJanitor(store, 10*time.Minute).Run)
+
+	return idempotency.Middleware(store, cfg.Wait, v1.Prefix)
 }
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"marketplace-api/internal/config"
+	"marketplace-api/internal/migrate"
+	"os"
+
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// runMigrate - подкоманда migrate: применение встроенных миграций без запуска сервера
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: marketplace-api migrate up|down|status|redo [flags]")
+		os.Exit(2)
+	}
+	command := args[0]
+
+	cfg, err := config.Load(args[1:], os.LookupEnv)
+	if err != nil {
+		fatal("invalid configuration", err)
+	}
+	setupLogging(cfg)
+
+	pool, err := connectDB(cfg)
+	if err != nil {
+		fatal("error connecting to database", err)
+	}
+	defer pool.Close()
+
+	migrator, err := migrate.New(stdlib.OpenDBFromPool(pool))
+	if err != nil {
+		fatal("error loading migrations", err)
+	}
+	if err := migrator.Run(context.Background(), command, os.Stdout); err != nil {
+		fatal("migrate "+command+" failed", err)
+	}
+}
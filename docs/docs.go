@@ -19,7 +19,7 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/advertisement": {
+        "/api/v1/advertisement": {
             "post": {
                 "security": [
                     {
@@ -46,6 +46,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/advertisement.CreateAdvertisementInput"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -72,11 +78,17 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "409": {
+                        "description": "Объявление с таким external_sku уже есть",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/advertisement/": {
+        "/api/v1/advertisement/": {
             "get": {
                 "security": [
                     {
@@ -163,9 +175,217 @@ const docTemplate = `{
                 }
             }
         },
-        "/login": {
+        "/api/v1/advertisement/feed": {
+            "get": {
+                "description": "Лента опубликованных объявлений для агрегаторов. Параметры страницы, фильтра по цене и сортировки - как у списка объявлений",
+                "produces": [
+                    "application/atom+xml",
+                    "application/rss+xml"
+                ],
+                "tags": [
+                    "advertisement"
+                ],
+                "summary": "Лента объявлений Atom/RSS",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "atom",
+                        "description": "Формат ленты (atom, rss)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Количество элементов в ленте",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "Поле для сортировки (created_at, price)",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Направление сортировки (asc, desc)",
+                        "name": "sort_direction",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Минимальная цена в копейках",
+                        "name": "min_price_kopecks",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Максимальная цена в копейках",
+                        "name": "max_price_kopecks",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Лента",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/advertisement/imports": {
             "post": {
-                "description": "Принимает email и пароль, возвращает JWT-токен",
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Принимает файл CSV (text/csv) или JSON Lines (application/x-ndjson) и ставит импорт в очередь. Формат берётся из параметра format или Content-Type. В CSV первая строка - названия колонок: title, description, image_url, price_kopecks обязательны, category, external_sku, publish_at (RFC 3339) - нет. В JSON Lines каждая строка - объект как при создании объявления. Каждая строка проверяется как при создании объявления, строки с ошибками пропускаются и перечисляются в результате. Строка с external_sku, который уже есть у продавца, обновляет название, описание, изображение, цену и категорию объявления. С dry_run=true строки только проверяются. Не более 5000 объявлений в файле",
+                "consumes": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "advertisement"
+                ],
+                "summary": "Импорт объявлений из файла",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Формат файла (csv, ndjson), по умолчанию по Content-Type",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только проверить файл, ничего не сохраняя",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/advertisement.ImportJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Файл пуст, слишком велик или некорректен",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Слишком большой запрос",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "Неподдерживаемый формат",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/advertisement/imports/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Возвращает статус задания импорта, счётчики созданных, обновлённых и ошибочных строк и ошибки строк с номерами",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "advertisement"
+                ],
+                "summary": "Состояние импорта объявлений",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID задания импорта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/advertisement.ImportJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Задание не найдено",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/advertisement/renew": {
+            "post": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Продлевает срок размещения объявления автора на срок его категории и возвращает снятое объявление в публикацию",
                 "consumes": [
                     "application/json"
                 ],
@@ -173,17 +393,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "advertisement"
                 ],
-                "summary": "Аунтификация пользователя",
+                "summary": "Продлить объявление",
                 "parameters": [
                     {
-                        "description": "Данные для аунтификации",
+                        "description": "ID объявления",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/user.LoginRequest"
+                            "$ref": "#/definitions/advertisement.RenewAdvertisementInput"
                         }
                     }
                 ],
@@ -191,7 +411,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/user.LoginResponse"
+                            "$ref": "#/definitions/advertisement.AdvertisementList"
                         }
                     },
                     "400": {
@@ -201,7 +421,19 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Неавторизован",
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Объявление принадлежит другому пользователю",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Объявление не найдено",
                         "schema": {
                             "type": "string"
                         }
@@ -211,13 +443,24 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "409": {
+                        "description": "Объявление ещё не опубликовано или торги по нему завершены",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/register": {
+        "/api/v1/advertisement/schedule": {
             "post": {
-                "description": "Принимает данные пользователя и создаёт новую учётную запись",
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Назначает новое время публикации для запланированного объявления или черновика",
                 "consumes": [
                     "application/json"
                 ],
@@ -225,25 +468,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "advertisement"
                 ],
-                "summary": "Регистрация нового пользователя",
+                "summary": "Перенести публикацию",
                 "parameters": [
                     {
-                        "description": "Данные для регистрации",
+                        "description": "ID объявления и время публикации",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/user.RegisterRequest"
+                            "$ref": "#/definitions/advertisement.ScheduleAdvertisementInput"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/user.RegisterResponse"
+                            "$ref": "#/definitions/advertisement.AdvertisementList"
                         }
                     },
                     "400": {
@@ -252,48 +495,1536 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Объявление принадлежит другому пользователю",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Объявление не найдено",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "405": {
                         "description": "Метод не разрешён",
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "409": {
+                        "description": "Объявление уже опубликовано",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "advertisement.Advertisement": {
-            "type": "object",
-            "properties": {
-                "author_id": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "image_url": {
-                    "type": "string"
-                },
-                "price_kopecks": {
-                    "description": "В копейках",
-                    "type": "integer"
-                },
-                "title": {
-                    "type": "string"
-                }
+        },
+        "/api/v1/advertisement/schedule/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Отменяет запланированную публикацию, объявление остаётся черновиком",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "advertisement"
+                ],
+                "summary": "Отменить публикацию",
+                "parameters": [
+                    {
+                        "description": "ID объявления",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/advertisement.CancelScheduleInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/advertisement.AdvertisementList"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ввод",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Объявление принадлежит другому пользователю",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Объявление не найдено",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "405": {
+                        "description": "Метод не разрешён",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Объявление уже опубликовано",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/advertisement/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Возвращает объявление по ID и учитывает просмотр (если пользователь авторизован добавляет параметр is_owner к ответу)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "advertisement"
+                ],
+                "summary": "Получить объявление",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID объявления",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/advertisement.AdvertisementList"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Объявление не найдено",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "405": {
+                        "description": "Метод не разрешён",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Изменяет переданные поля объявления автора. С заголовком If-Match изменение применяется, только если объявление не менялось с получения этого ETag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "advertisement"
+                ],
+                "summary": "Изменить объявление",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID объявления",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/advertisement.UpdateAdvertisementInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag из ответа GET /advertisement/{id}",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/advertisement.AdvertisementList"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ввод",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Объявление принадлежит другому пользователю",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Объявление не найдено",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Объявление изменилось, ETag устарел",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Возвращает неотозванные ключи пользователя без самих ключей: название, начало ключа, разрешения, срок действия и время последнего использования",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "API-ключи",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/apikey.Key"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Недоступно для API-ключей",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Создаёт ключ для обращений с сервера: передаётся как Authorization: Bearer \u003cключ\u003e вместо JWT. Ключ показывается только в этом ответе. Разрешения: ads:read - чтение объявлений и статистики, ads:write - создание и изменение объявлений. Управление ключами и остальные действия доступны только после входа по паролю",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Выпустить API-ключ",
+                "parameters": [
+                    {
+                        "description": "Название, разрешения и срок действия",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/apikey.CreateKeyInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/apikey.Key"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ввод",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Недоступно для API-ключей",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Слишком много ключей",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/api-keys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Запросы с отозванным ключом сразу перестают приниматься",
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Отозвать API-ключ",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID ключа",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Недоступно для API-ключей",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Ключ не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/auction/bid": {
+            "post": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Размещает ставку авторизованного пользователя на аукционе",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auction"
+                ],
+                "summary": "Сделать ставку",
+                "parameters": [
+                    {
+                        "description": "Ставка",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auction.PlaceBidInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/auction.BidResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ввод или слишком низкая ставка",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Ставка на собственный аукцион",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Аукцион не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "405": {
+                        "description": "Метод не разрешён",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Аукцион завершён",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/auction/{id}": {
+            "get": {
+                "description": "Возвращает текущую цену, количество ставок, время окончания и победителя аукциона",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auction"
+                ],
+                "summary": "Получить состояние аукциона",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID объявления",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/auction.Auction"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Аукцион не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "405": {
+                        "description": "Метод не разрешён",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/login": {
+            "post": {
+                "description": "Принимает email и пароль, возвращает JWT-токен",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Аунтификация пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные для аунтификации",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ввод",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизован",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "405": {
+                        "description": "Метод не разрешён",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/me/advertisements/export": {
+            "get": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Выгружает все объявления пользователя в любом статусе от новых к старым. CSV содержит колонки id, external_sku, title, description, image_url, price_kopecks, category, listing_type, status, publish_at, expires_at, created_at, updated_at, JSON - массив объявлений. Файл передаётся по мере чтения; при сбое соединение обрывается, а не завершается неполным файлом",
+                "produces": [
+                    "text/csv",
+                    "application/json"
+                ],
+                "tags": [
+                    "advertisement"
+                ],
+                "summary": "Выгрузка объявлений продавца",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Формат (csv, json)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Неизвестный формат",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/me/advertisements/scheduled": {
+            "get": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Возвращает объявления авторизованного пользователя, ожидающие публикации, и черновики с отменённой публикацией",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "advertisement"
+                ],
+                "summary": "Запланированные объявления",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/advertisement.AdvertisementList"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "405": {
+                        "description": "Метод не разрешён",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/me/advertisements/stats": {
+            "get": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Возвращает просмотры каждого объявления авторизованного пользователя с разбивкой по дням (30 дней), неделям (12 недель) или месяцам (12 месяцев). Избранного и показа контактов продавца в приложении нет, поэтому статистика содержит только просмотры",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Статистика по объявлениям продавца",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "day",
+                        "description": "Период группировки (day, week, month)",
+                        "name": "period",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/stats.AdStats"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "405": {
+                        "description": "Метод не разрешён",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/promotion": {
+            "post": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Создаёт платёж за поднятие (bump) или выделение на N дней (highlight) объявления и возвращает ссылку на оплату",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotion"
+                ],
+                "summary": "Купить продвижение объявления",
+                "parameters": [
+                    {
+                        "description": "Параметры продвижения",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/promotion.CreatePromotionInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/promotion.Promotion"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ввод",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Объявление принадлежит другому пользователю",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Объявление не найдено",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "405": {
+                        "description": "Метод не разрешён",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Объявление не опубликовано",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/register": {
+            "post": {
+                "description": "Принимает данные пользователя и создаёт новую учётную запись",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Регистрация нового пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные для регистрации",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/user.RegisterResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ввод",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "405": {
+                        "description": "Метод не разрешён",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Возвращает адреса пользователя без ключей подписи",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Адреса для уведомлений",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/webhook.Endpoint"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Партнёр получает POST-запросы о событиях своих объявлений. Запрос подписан: заголовок Webhook-Signature содержит v1=\u003chex HMAC-SHA256\u003e от строки \"\u003cWebhook-Timestamp\u003e.\u003cтело\u003e\" на ключе secret. Ключ возвращается только в этом ответе. Типы событий: advertisement.created, advertisement.updated, advertisement.published, advertisement.renewed, advertisement.expired, advertisement.sold",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Зарегистрировать адрес для уведомлений",
+                "parameters": [
+                    {
+                        "description": "Адрес и типы событий",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/webhook.CreateEndpointInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.Endpoint"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ввод",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Удаляет адрес вместе с журналом доставок",
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Удалить адрес для уведомлений",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID адреса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Адрес не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Возвращает последние доставки на адрес: статус, число попыток, ответ получателя и время следующей попытки",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Журнал доставок",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID адреса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию 50, максимум 200)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/webhook.Delivery"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ввод",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Адрес не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}/deliveries/{deliveryID}/redeliver": {
+            "post": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Ставит доставку в очередь заново с полным запасом попыток. Тело запроса и ID события не меняются",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Повторить доставку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID адреса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID доставки",
+                        "name": "deliveryID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.Delivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Адрес или доставка не найдены",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}/enable": {
+            "post": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Включает адрес, отключённый после серии неудачных доставок. Накопившиеся доставки будут отправлены",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Включить адрес для уведомлений",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID адреса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.Endpoint"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Пользователь не авторизован",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Адрес не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/livez": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness-проба",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/payments/webhook": {
+            "post": {
+                "description": "Принимает подписанное уведомление о статусе платежа и применяет оплаченное продвижение",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotion"
+                ],
+                "summary": "Уведомление платёжного провайдера",
+                "parameters": [
+                    {
+                        "description": "Статус платежа",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/promotion.PaymentCallback"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ввод",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Неверная подпись",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Платёж не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "405": {
+                        "description": "Метод не разрешён",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Проверяет базу данных, версию миграций, пул соединений и зарегистрированные компоненты. Во время остановки возвращает 503",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness-проба",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    }
+                }
+            }
+        },
+        "/sitemap.xml": {
+            "get": {
+                "description": "Карта сайта (sitemaps.org) со страницами опубликованных объявлений, не более 50000 самых новых.\nОтдаётся из корня сайта: карта описывает только адреса внутри своего каталога",
+                "produces": [
+                    "application/xml"
+                ],
+                "tags": [
+                    "advertisement"
+                ],
+                "summary": "Карта сайта",
+                "responses": {
+                    "200": {
+                        "description": "Карта сайта",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "advertisement.Advertisement": {
+            "type": "object",
+            "properties": {
+                "auction": {
+                    "$ref": "#/definitions/advertisement.AuctionTerms"
+                },
+                "author_id": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "external_sku": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "listing_type": {
+                    "type": "string"
+                },
+                "price_kopecks": {
+                    "description": "В копейках",
+                    "type": "integer"
+                },
+                "publish_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "advertisement.AdvertisementList": {
+            "type": "object",
+            "properties": {
+                "author_login": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "is_highlighted": {
+                    "description": "оплачено выделение объявления",
+                    "type": "boolean"
+                },
+                "is_owner": {
+                    "description": "факт принадлежности объявления авторизованному пользователю",
+                    "type": "boolean"
+                },
+                "listing_type": {
+                    "type": "string"
+                },
+                "price_kopecks": {
+                    "type": "number"
+                },
+                "publish_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "advertisement.AuctionTerms": {
+            "type": "object",
+            "properties": {
+                "buy_now_price_kopecks": {
+                    "description": "цена \"купить сейчас\"",
+                    "type": "integer"
+                },
+                "ends_at": {
+                    "description": "время окончания торгов",
+                    "type": "string"
+                },
+                "min_bid_increment_kopecks": {
+                    "description": "минимальный шаг ставки",
+                    "type": "integer"
+                },
+                "reserve_price_kopecks": {
+                    "description": "резервная (минимальная) цена продажи",
+                    "type": "integer"
+                }
+            }
+        },
+        "advertisement.CancelScheduleInput": {
+            "type": "object",
+            "properties": {
+                "advertisement_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "advertisement.CreateAdvertisementInput": {
+            "type": "object",
+            "properties": {
+                "auction": {
+                    "description": "обязательно для listing_type = \"auction\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/advertisement.AuctionTerms"
+                        }
+                    ]
+                },
+                "category": {
+                    "description": "категория, определяет срок размещения",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "external_sku": {
+                    "description": "артикул продавца, уникален среди его объявлений",
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "listing_type": {
+                    "description": "\"fixed\" (по умолчанию) или \"auction\"",
+                    "type": "string"
+                },
+                "price_kopecks": {
+                    "type": "integer"
+                },
+                "publish_at": {
+                    "description": "отложенная публикация, по умолчанию - сразу",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "advertisement.ImportJob": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "при dry_run - сколько было бы создано",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "dry_run": {
+                    "description": "только проверка: объявления не создаются и не меняются",
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "errors": {
+                    "description": "первые 1000 ошибок строк",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/advertisement.ImportRowError"
+                    }
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "processed": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total_rows": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "description": "обновлено по external_sku, при dry_run - было бы обновлено",
+                    "type": "integer"
+                }
+            }
+        },
+        "advertisement.ImportRowError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "external_sku": {
+                    "type": "string"
+                },
+                "line": {
+                    "description": "номер строки в файле, с единицы",
+                    "type": "integer"
+                }
+            }
+        },
+        "advertisement.RenewAdvertisementInput": {
+            "type": "object",
+            "properties": {
+                "advertisement_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "advertisement.ScheduleAdvertisementInput": {
+            "type": "object",
+            "properties": {
+                "advertisement_id": {
+                    "type": "string"
+                },
+                "publish_at": {
+                    "type": "string"
+                }
             }
         },
-        "advertisement.AdvertisementList": {
+        "advertisement.UpdateAdvertisementInput": {
             "type": "object",
             "properties": {
-                "author_login": {
+                "category": {
                     "type": "string"
                 },
                 "description": {
@@ -302,32 +2033,314 @@ const docTemplate = `{
                 "image_url": {
                     "type": "string"
                 },
-                "is_owner": {
-                    "description": "факт принадлежности объявления авторизованному пользователю",
-                    "type": "boolean"
-                },
                 "price_kopecks": {
-                    "type": "number"
+                    "description": "у аукциона стартовая цена не меняется",
+                    "type": "integer"
                 },
                 "title": {
                     "type": "string"
                 }
             }
         },
-        "advertisement.CreateAdvertisementInput": {
+        "apikey.CreateKeyInput": {
             "type": "object",
             "properties": {
-                "description": {
+                "expires_at": {
+                    "description": "без срока ключ действует до отзыва",
                     "type": "string"
                 },
-                "image_url": {
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "apikey.Key": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "description": "сам ключ, возвращается только при создании",
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "начало ключа, чтобы узнать его в списке",
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "auction.Auction": {
+            "type": "object",
+            "properties": {
+                "advertisement_id": {
+                    "type": "string"
+                },
+                "bids_count": {
+                    "type": "integer"
+                },
+                "buy_now_price_kopecks": {
+                    "type": "integer"
+                },
+                "closed_at": {
+                    "type": "string"
+                },
+                "current_price_kopecks": {
+                    "type": "integer"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "highest_bidder_id": {
+                    "type": "string"
+                },
+                "min_bid_increment_kopecks": {
+                    "type": "integer"
+                },
+                "reserve_met": {
+                    "description": "достигнута ли резервная цена",
+                    "type": "boolean"
+                },
+                "seller_id": {
+                    "type": "string"
+                },
+                "starting_price_kopecks": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "winner_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "auction.Bid": {
+            "type": "object",
+            "properties": {
+                "advertisement_id": {
+                    "type": "string"
+                },
+                "amount_kopecks": {
+                    "type": "integer"
+                },
+                "bidder_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "auction.BidResult": {
+            "type": "object",
+            "properties": {
+                "auction": {
+                    "$ref": "#/definitions/auction.Auction"
+                },
+                "bid": {
+                    "$ref": "#/definitions/auction.Bid"
+                }
+            }
+        },
+        "auction.PlaceBidInput": {
+            "type": "object",
+            "properties": {
+                "advertisement_id": {
+                    "type": "string"
+                },
+                "amount_kopecks": {
+                    "type": "integer"
+                }
+            }
+        },
+        "health.CheckStatus": {
+            "type": "object",
+            "properties": {
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "health.DatabaseStatus": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "migration_version": {
+                    "type": "integer"
+                },
+                "pool": {
+                    "$ref": "#/definitions/health.PoolStats"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "health.PoolStats": {
+            "type": "object",
+            "properties": {
+                "acquired_conns": {
+                    "type": "integer"
+                },
+                "idle_conns": {
+                    "type": "integer"
+                },
+                "max_conns": {
+                    "type": "integer"
+                },
+                "total_conns": {
+                    "type": "integer"
+                }
+            }
+        },
+        "health.Report": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/health.CheckStatus"
+                    }
+                },
+                "database": {
+                    "$ref": "#/definitions/health.DatabaseStatus"
+                },
+                "status": {
+                    "description": "ready, not_ready или shutting_down",
+                    "type": "string"
+                }
+            }
+        },
+        "promotion.CreatePromotionInput": {
+            "type": "object",
+            "properties": {
+                "advertisement_id": {
+                    "type": "string"
+                },
+                "days": {
+                    "description": "количество дней выделения, только для highlight",
+                    "type": "integer"
+                },
+                "kind": {
+                    "description": "\"bump\" или \"highlight\"",
+                    "type": "string"
+                }
+            }
+        },
+        "promotion.PaymentCallback": {
+            "type": "object",
+            "properties": {
+                "payment_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "promotion.Promotion": {
+            "type": "object",
+            "properties": {
+                "advertisement_id": {
+                    "type": "string"
+                },
+                "confirmation_url": {
+                    "description": "страница оплаты у платёжного провайдера",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "payment_id": {
                     "type": "string"
                 },
                 "price_kopecks": {
                     "type": "integer"
                 },
+                "status": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "stats.AdStats": {
+            "type": "object",
+            "properties": {
+                "advertisement_id": {
+                    "type": "string"
+                },
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/stats.Bucket"
+                    }
+                },
                 "title": {
                     "type": "string"
+                },
+                "views": {
+                    "type": "integer"
+                }
+            }
+        },
+        "stats.Bucket": {
+            "type": "object",
+            "properties": {
+                "start": {
+                    "type": "string"
+                },
+                "views": {
+                    "type": "integer"
                 }
             }
         },
@@ -371,11 +2384,100 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "webhook.CreateEndpointInput": {
+            "type": "object",
+            "properties": {
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "webhook.Delivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "endpoint_id": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "description": "тело запроса к получателю",
+                    "type": "object"
+                },
+                "response_status": {
+                    "description": "HTTP-статус последней попытки",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "webhook.Endpoint": {
+            "type": "object",
+            "properties": {
+                "consecutive_failures": {
+                    "description": "неудачных доставок подряд",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "disabled_at": {
+                    "description": "отключён после серии неудачных доставок",
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "ключ подписи, возвращается только при создании",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
         "AuthToken": {
-            "description": "Введите JWT токен с префиксом Bearer",
+            "description": "Введите JWT токен или API-ключ (mk_...) с префиксом Bearer",
             "type": "apiKey",
             "name": "Authorization",
             "in": "header"
@@ -0,0 +1,150 @@
+package advertisement
+
+import (
+	"context"
+	"fmt"
+	"marketplace-api/internal/cache"
+	"marketplace-api/internal/db"
+	"marketplace-api/internal/metrics"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// feedCacheName - имя кэша ленты в метриках
+const feedCacheName = "advertisement_feed"
+
+// CachedRepository - репозиторий с кэшем публичной ленты. Кэшируются только страницы
+// для анонимных запросов: у авторизованных в ответе is_owner. Любое изменение объявлений
+// через репозиторий сбрасывает кэш целиком, поскольку сдвигает страницы. Изменения в обход
+// репозитория (продвижение, окончание срока выделения) и в других экземплярах с локальным
+// кэшем видны не позже чем через TTL кэша
+type CachedRepository struct {
+	repo RepositoryInterface
+	feed cache.Backend[[]AdvertisementList]
+	// generation растёт при каждом сбросе, чтобы прочитанная до сброса страница
+	// не попала в кэш после него
+	generation atomic.Uint64
+}
+
+var _ RepositoryInterface = (*CachedRepository)(nil)
+
+// NewCachedRepository - оборачивает repo кэшем ленты feed
+func NewCachedRepository(repo RepositoryInterface, feed cache.Backend[[]AdvertisementList]) *CachedRepository {
+	return &CachedRepository{repo: repo, feed: feed}
+}
+
+func (c *CachedRepository) GetAdvertisementsList(ctx context.Context, params *AdvertisementListParams) ([]AdvertisementList, error) {
+	if params.UserID != nil {
+		return c.repo.GetAdvertisementsList(ctx, params)
+	}
+
+	key := feedKey(params)
+	if ads, ok := c.feed.Get(ctx, key); ok {
+		metrics.CacheLookup(feedCacheName, true)
+		return slices.Clone(ads), nil
+	}
+	metrics.CacheLookup(feedCacheName, false)
+
+	generation := c.generation.Load()
+	ads, err := c.repo.GetAdvertisementsList(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if c.generation.Load() == generation {
+		c.feed.Set(ctx, key, slices.Clone(ads))
+	}
+	return ads, nil
+}
+
+// feedKey - ключ страницы ленты. Параметры приводятся к виду, в котором их понимает
+// запрос, чтобы равнозначные запросы попадали в одну запись
+func feedKey(params *AdvertisementListParams) string {
+	sortBy := "created_at"
+	if params.SortBy == "price" {
+		sortBy = "price"
+	}
+	direction := "desc"
+	if strings.EqualFold(params.SortDirection, "asc") {
+		direction = "asc"
+	}
+	return fmt.Sprintf("page=%d&limit=%d&sort=%s:%s&price=%d-%d",
+		params.Page, params.Limit, sortBy, direction, params.MinPriceKopecks, params.MaxPriceKopecks)
+}
+
+func (c *CachedRepository) Create(ctx context.Context, ad *Advertisement) (*Advertisement, error) {
+	created, err := c.repo.Create(ctx, ad)
+	return created, c.purgeOnSuccess(ctx, err)
+}
+
+func (c *CachedRepository) GetByID(ctx context.Context, id uuid.UUID, userID *uuid.UUID) (*AdvertisementList, error) {
+	return c.repo.GetByID(ctx, id, userID)
+}
+
+func (c *CachedRepository) FindBySKU(ctx context.Context, authorID uuid.UUID, sku string) (*uuid.UUID, error) {
+	return c.repo.FindBySKU(ctx, authorID, sku)
+}
+
+func (c *CachedRepository) ExportBatch(ctx context.Context, filter *ExportFilter, after *Advertisement, limit int) ([]Advertisement, error) {
+	return c.repo.ExportBatch(ctx, filter, after, limit)
+}
+
+func (c *CachedRepository) Update(ctx context.Context, ad *AdvertisementList, unmodifiedSince *time.Time) error {
+	return c.purgeOnSuccess(ctx, c.repo.Update(ctx, ad, unmodifiedSince))
+}
+
+func (c *CachedRepository) Renew(ctx context.Context, id uuid.UUID, expiresAt time.Time) error {
+	return c.purgeOnSuccess(ctx, c.repo.Renew(ctx, id, expiresAt))
+}
+
+func (c *CachedRepository) ArchiveExpired(ctx context.Context, limit int) ([]Advertisement, error) {
+	ads, err := c.repo.ArchiveExpired(ctx, limit)
+	if len(ads) > 0 {
+		c.purge(ctx)
+	}
+	return ads, err
+}
+
+func (c *CachedRepository) ClaimExpiryReminders(ctx context.Context, expiresBefore time.Time, limit int) ([]ExpiryReminder, error) {
+	return c.repo.ClaimExpiryReminders(ctx, expiresBefore, limit)
+}
+
+func (c *CachedRepository) ListScheduled(ctx context.Context, authorID uuid.UUID) ([]AdvertisementList, error) {
+	return c.repo.ListScheduled(ctx, authorID)
+}
+
+func (c *CachedRepository) Schedule(ctx context.Context, id uuid.UUID, publishAt, expiresAt time.Time) error {
+	return c.purgeOnSuccess(ctx, c.repo.Schedule(ctx, id, publishAt, expiresAt))
+}
+
+func (c *CachedRepository) CancelSchedule(ctx context.Context, id uuid.UUID) error {
+	return c.purgeOnSuccess(ctx, c.repo.CancelSchedule(ctx, id))
+}
+
+func (c *CachedRepository) PublishDue(ctx context.Context, limit int) ([]Advertisement, error) {
+	ads, err := c.repo.PublishDue(ctx, limit)
+	if len(ads) > 0 {
+		c.purge(ctx)
+	}
+	return ads, err
+}
+
+// purgeOnSuccess - сбрасывает кэш ленты, если изменение прошло
+func (c *CachedRepository) purgeOnSuccess(ctx context.Context, err error) error {
+	if err == nil {
+		c.purge(ctx)
+	}
+	return err
+}
+
+// purge - сбрасывает кэш, а внутри транзакции - после её фиксации, когда изменение
+// становится видно другим запросам
+func (c *CachedRepository) purge(ctx context.Context) {
+	db.AfterCommit(ctx, func() {
+		c.generation.Add(1)
+		c.feed.Purge(context.WithoutCancel(ctx))
+	})
+}
@@ -0,0 +1,182 @@
+package advertisement_test
+
+import (
+	"context"
+	"errors"
+	"marketplace-api/internal/advertisement"
+	mockad "marketplace-api/internal/advertisement/mock"
+	"marketplace-api/internal/cache"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func setupCacheTest(t *testing.T) (*mockad.MockRepositoryInterface, *advertisement.CachedRepository) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockRepo := mockad.NewMockRepositoryInterface(ctrl)
+	feed := cache.NewLRU[[]advertisement.AdvertisementList](10, time.Minute)
+	return mockRepo, advertisement.NewCachedRepository(mockRepo, feed)
+}
+
+func TestCachedRepository_GetAdvertisementsList(t *testing.T) {
+	ctx := context.Background()
+	page := []advertisement.AdvertisementList{{ID: uuid.New(), Title: "Test Ad"}}
+
+	t.Run("анонимная страница берётся из кэша", func(t *testing.T) {
+		mockRepo, repo := setupCacheTest(t)
+		mockRepo.EXPECT().GetAdvertisementsList(gomock.Any(), gomock.Any()).Return(page, nil).Times(1)
+
+		params := &advertisement.AdvertisementListParams{Page: 1, Limit: 10}
+		first, err := repo.GetAdvertisementsList(ctx, params)
+		assert.NoError(t, err)
+		second, err := repo.GetAdvertisementsList(ctx, params)
+		assert.NoError(t, err)
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("равнозначные параметры попадают в одну запись", func(t *testing.T) {
+		mockRepo, repo := setupCacheTest(t)
+		mockRepo.EXPECT().GetAdvertisementsList(gomock.Any(), gomock.Any()).Return(page, nil).Times(1)
+
+		repo.GetAdvertisementsList(ctx, &advertisement.AdvertisementListParams{Page: 1, Limit: 10, SortBy: "created_at", SortDirection: "DESC"})
+		repo.GetAdvertisementsList(ctx, &advertisement.AdvertisementListParams{Page: 1, Limit: 10, SortDirection: "desc"})
+	})
+
+	t.Run("разные страницы кэшируются отдельно", func(t *testing.T) {
+		mockRepo, repo := setupCacheTest(t)
+		mockRepo.EXPECT().GetAdvertisementsList(gomock.Any(), gomock.Any()).Return(page, nil).Times(2)
+
+		repo.GetAdvertisementsList(ctx, &advertisement.AdvertisementListParams{Page: 1, Limit: 10})
+		repo.GetAdvertisementsList(ctx, &advertisement.AdvertisementListParams{Page: 2, Limit: 10})
+	})
+
+	t.Run("запросы авторизованных не кэшируются", func(t *testing.T) {
+		mockRepo, repo := setupCacheTest(t)
+		mockRepo.EXPECT().GetAdvertisementsList(gomock.Any(), gomock.Any()).Return(page, nil).Times(2)
+
+		userID := uuid.New()
+		params := &advertisement.AdvertisementListParams{Page: 1, Limit: 10, UserID: &userID}
+		repo.GetAdvertisementsList(ctx, params)
+		repo.GetAdvertisementsList(ctx, params)
+	})
+
+	t.Run("ошибки не кэшируются", func(t *testing.T) {
+		mockRepo, repo := setupCacheTest(t)
+		gomock.InOrder(
+			mockRepo.EXPECT().GetAdvertisementsList(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error")),
+			mockRepo.EXPECT().GetAdvertisementsList(gomock.Any(), gomock.Any()).Return(page, nil),
+		)
+
+		params := &advertisement.AdvertisementListParams{Page: 1, Limit: 10}
+		_, err := repo.GetAdvertisementsList(ctx, params)
+		assert.Error(t, err)
+		ads, err := repo.GetAdvertisementsList(ctx, params)
+		assert.NoError(t, err)
+		assert.Equal(t, page, ads)
+	})
+
+	t.Run("изменение кэшированной страницы не портит кэш", func(t *testing.T) {
+		mockRepo, repo := setupCacheTest(t)
+		mockRepo.EXPECT().GetAdvertisementsList(gomock.Any(), gomock.Any()).
+			Return([]advertisement.AdvertisementList{{Title: "Test Ad"}}, nil)
+
+		params := &advertisement.AdvertisementListParams{Page: 1, Limit: 10}
+		ads, _ := repo.GetAdvertisementsList(ctx, params)
+		ads[0].Title = "changed"
+		ads, _ = repo.GetAdvertisementsList(ctx, params)
+		assert.Equal(t, "Test Ad", ads[0].Title)
+	})
+}
+
+func TestCachedRepository_Invalidation(t *testing.T) {
+	ctx := context.Background()
+	params := &advertisement.AdvertisementListParams{Page: 1, Limit: 10}
+	id := uuid.New()
+
+	mutations := []struct {
+		name   string
+		expect func(m *mockad.MockRepositoryInterface)
+		call   func(r *advertisement.CachedRepository) error
+	}{
+		{
+			name: "создание",
+			expect: func(m *mockad.MockRepositoryInterface) {
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&advertisement.Advertisement{}, nil)
+			},
+			call: func(r *advertisement.CachedRepository) error {
+				_, err := r.Create(ctx, &advertisement.Advertisement{})
+				return err
+			},
+		},
+		{
+			name: "изменение",
+			expect: func(m *mockad.MockRepositoryInterface) {
+				m.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			},
+			call: func(r *advertisement.CachedRepository) error {
+				return r.Update(ctx, &advertisement.AdvertisementList{}, nil)
+			},
+		},
+		{
+			name:   "продление",
+			expect: func(m *mockad.MockRepositoryInterface) { m.EXPECT().Renew(gomock.Any(), id, gomock.Any()).Return(nil) },
+			call:   func(r *advertisement.CachedRepository) error { return r.Renew(ctx, id, time.Now()) },
+		},
+		{
+			name: "снятие просроченных",
+			expect: func(m *mockad.MockRepositoryInterface) {
+				m.EXPECT().ArchiveExpired(gomock.Any(), 100).Return(make([]advertisement.Advertisement, 1), nil)
+			},
+			call: func(r *advertisement.CachedRepository) error {
+				_, err := r.ArchiveExpired(ctx, 100)
+				return err
+			},
+		},
+		{
+			name: "публикация отложенных",
+			expect: func(m *mockad.MockRepositoryInterface) {
+				m.EXPECT().PublishDue(gomock.Any(), 100).Return(make([]advertisement.Advertisement, 2), nil)
+			},
+			call: func(r *advertisement.CachedRepository) error {
+				_, err := r.PublishDue(ctx, 100)
+				return err
+			},
+		},
+	}
+
+	for _, tc := range mutations {
+		t.Run(tc.name+" сбрасывает кэш", func(t *testing.T) {
+			mockRepo, repo := setupCacheTest(t)
+			mockRepo.EXPECT().GetAdvertisementsList(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+			tc.expect(mockRepo)
+
+			repo.GetAdvertisementsList(ctx, params)
+			assert.NoError(t, tc.call(repo))
+			repo.GetAdvertisementsList(ctx, params)
+		})
+	}
+
+	t.Run("неудачное изменение не сбрасывает кэш", func(t *testing.T) {
+		mockRepo, repo := setupCacheTest(t)
+		mockRepo.EXPECT().GetAdvertisementsList(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+		mockRepo.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Return(advertisement.ErrModified)
+
+		repo.GetAdvertisementsList(ctx, params)
+		assert.ErrorIs(t, repo.Update(ctx, &advertisement.AdvertisementList{}, nil), advertisement.ErrModified)
+		repo.GetAdvertisementsList(ctx, params)
+	})
+
+	t.Run("пустой проход фоновой задачи не сбрасывает кэш", func(t *testing.T) {
+		mockRepo, repo := setupCacheTest(t)
+		mockRepo.EXPECT().GetAdvertisementsList(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+		mockRepo.EXPECT().PublishDue(gomock.Any(), 100).Return(nil, nil)
+
+		repo.GetAdvertisementsList(ctx, params)
+		repo.PublishDue(ctx, 100)
+		repo.GetAdvertisementsList(ctx, params)
+	})
+}
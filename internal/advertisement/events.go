@@ -0,0 +1,77 @@
+package advertisement
+
+import (
+	"context"
+	"marketplace-api/internal/outbox"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AggregateType - агрегат событий объявления
+const AggregateType = "advertisement"
+
+// Типы событий объявления
+const (
+	EventCreated   = "advertisement.created"
+	EventUpdated   = "advertisement.updated"
+	EventPublished = "advertisement.published" // наступило время отложенной публикации
+	EventRenewed   = "advertisement.renewed"   // автор продлил срок размещения
+	EventExpired   = "advertisement.expired"   // срок размещения истёк, объявление снято с публикации
+)
+
+// AdvertisementEvent - данные событий объявления: состояние после изменения
+type AdvertisementEvent struct {
+	ID           uuid.UUID  `json:"id"`
+	AuthorID     uuid.UUID  `json:"author_id"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	ImageURL     string     `json:"image_url"`
+	PriceKopecks int        `json:"price_kopecks"`
+	ListingType  string     `json:"listing_type"`
+	Category     string     `json:"category,omitempty"`
+	Status       string     `json:"status"`
+	PublishAt    *time.Time `json:"publish_at,omitempty"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+}
+
+// record - сохраняет событие eventType объявления
+func (s *Service) record(ctx context.Context, eventType string, payload AdvertisementEvent) error {
+	event, err := outbox.New(eventType, AggregateType, payload.ID, payload)
+	if err != nil {
+		return err
+	}
+	return s.events.Record(ctx, event)
+}
+
+func advertisementEvent(ad *Advertisement) AdvertisementEvent {
+	return AdvertisementEvent{
+		ID:           ad.ID,
+		AuthorID:     ad.AuthorID,
+		Title:        ad.Title,
+		Description:  ad.Description,
+		ImageURL:     ad.ImageURL,
+		PriceKopecks: ad.PriceKopecks,
+		ListingType:  ad.ListingType,
+		Category:     ad.Category,
+		Status:       ad.Status,
+		PublishAt:    ad.PublishAt,
+		ExpiresAt:    ad.ExpiresAt,
+	}
+}
+
+func updatedEvent(ad *AdvertisementList, authorID uuid.UUID) AdvertisementEvent {
+	return AdvertisementEvent{
+		ID:           ad.ID,
+		AuthorID:     authorID,
+		Title:        ad.Title,
+		Description:  ad.Description,
+		ImageURL:     ad.ImageURL,
+		PriceKopecks: int(ad.PriceKopecks),
+		ListingType:  ad.ListingType,
+		Category:     ad.Category,
+		Status:       ad.Status,
+		PublishAt:    ad.PublishAt,
+		ExpiresAt:    ad.ExpiresAt,
+	}
+}
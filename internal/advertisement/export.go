@@ -0,0 +1,317 @@
+package advertisement
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"marketplace-api/internal/tracing"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Форматы выгрузки объявлений продавца
+const (
+	ExportFormatCSV  = "csv"  // колонки названы как в импорте
+	ExportFormatJSON = "json" // массив объявлений
+)
+
+// Форматы ленты новых объявлений
+const (
+	FeedFormatAtom = "atom"
+	FeedFormatRSS  = "rss"
+)
+
+const (
+	// exportBatchSize - сколько объявлений выгрузки читается из БД за раз
+	exportBatchSize = 500
+	// SitemapMaxURLs - ограничение протокола sitemaps на число адресов в одном файле
+	SitemapMaxURLs = 50000
+)
+
+// ExportFilter - какие объявления выгружаются
+type ExportFilter struct {
+	AuthorID *uuid.UUID // все объявления автора в любом статусе, nil - опубликованные объявления всех авторов
+}
+
+// Links - внешние адреса в лентах и карте сайта
+type Links struct {
+	PublicURL string // внешний адрес API
+	PageURL   string // страница объявления, {id} заменяется на ID
+}
+
+// Page - адрес страницы объявления
+func (l Links) Page(id uuid.UUID) string {
+	return strings.ReplaceAll(l.PageURL, "{id}", id.String())
+}
+
+// Export - передаёт fn объявления по фильтру от новых к старым, не более limit (0 - все).
+// Объявления читаются из БД пачками, поэтому выгрузка любого размера не держит их в памяти
+func (s *Service) Export(ctx context.Context, filter *ExportFilter, limit int, fn func(*Advertisement) error) error {
+	ctx, span := tracing.Start(ctx, "advertisement.Service.Export")
+	defer span.End()
+
+	var after *Advertisement
+	for sent := 0; ; {
+		size := exportBatchSize
+		if limit > 0 {
+			size = min(size, limit-sent)
+		}
+		if size == 0 {
+			return nil
+		}
+
+		batch, err := s.repo.ExportBatch(ctx, filter, after, size)
+		if err != nil {
+			return err
+		}
+		for i := range batch {
+			if err := fn(&batch[i]); err != nil {
+				return err
+			}
+		}
+		if len(batch) < size {
+			return nil
+		}
+		sent += len(batch)
+		after = &batch[len(batch)-1]
+	}
+}
+
+// exportEncoder - потоковая запись объявлений: Encode на каждое объявление, Close в конце
+type exportEncoder interface {
+	Encode(ad *Advertisement) error
+	Close() error
+}
+
+// exportColumns - колонки выгрузки CSV
+var exportColumns = []string{"id", "external_sku", "title", "description", "image_url", "price_kopecks", "category",
+	"listing_type", "status", "publish_at", "expires_at", "created_at", "updated_at"}
+
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func newCSVEncoder(w io.Writer) (*csvEncoder, error) {
+	e := &csvEncoder{w: csv.NewWriter(w)}
+	return e, e.w.Write(exportColumns)
+}
+
+func (e *csvEncoder) Encode(ad *Advertisement) error {
+	var publishAt string
+	if ad.PublishAt != nil {
+		publishAt = ad.PublishAt.UTC().Format(time.RFC3339)
+	}
+	return e.w.Write([]string{
+		ad.ID.String(), ad.ExternalSKU, ad.Title, ad.Description, ad.ImageURL, strconv.Itoa(ad.PriceKopecks), ad.Category,
+		ad.ListingType, ad.Status, publishAt, ad.ExpiresAt.UTC().Format(time.RFC3339),
+		ad.CreatedAt.UTC().Format(time.RFC3339), ad.UpdatedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+func (e *csvEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// jsonEncoder - массив JSON, элементы пишутся по мере поступления
+type jsonEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonEncoder) Encode(ad *Advertisement) error {
+	data, err := json.Marshal(ad)
+	if err != nil {
+		return err
+	}
+	sep := ",\n"
+	if e.count == 0 {
+		sep = "[\n"
+	}
+	e.count++
+	_, err = io.WriteString(e.w, sep+string(data))
+	return err
+}
+
+func (e *jsonEncoder) Close() error {
+	closing := "\n]\n"
+	if e.count == 0 {
+		closing = "[]\n"
+	}
+	_, err := io.WriteString(e.w, closing)
+	return err
+}
+
+const sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+type sitemapURL struct {
+	XMLName xml.Name `xml:"url"`
+	Loc     string   `xml:"loc"`
+	LastMod string   `xml:"lastmod"`
+}
+
+// sitemapEncoder - карта сайта со страницами объявлений
+type sitemapEncoder struct {
+	enc   *xml.Encoder
+	links Links
+}
+
+func newSitemapEncoder(w io.Writer, links Links) (*sitemapEncoder, error) {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return nil, err
+	}
+	e := &sitemapEncoder{enc: xml.NewEncoder(w), links: links}
+	return e, e.enc.EncodeToken(xml.StartElement{
+		Name: xml.Name{Local: "urlset"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: sitemapNamespace}},
+	})
+}
+
+func (e *sitemapEncoder) Encode(ad *Advertisement) error {
+	return e.enc.Encode(sitemapURL{Loc: e.links.Page(ad.ID), LastMod: ad.UpdatedAt.UTC().Format(time.RFC3339)})
+}
+
+func (e *sitemapEncoder) Close() error {
+	if err := e.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: "urlset"}}); err != nil {
+		return err
+	}
+	return e.enc.Flush()
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID       string        `xml:"id"`
+	Title    string        `xml:"title"`
+	Updated  string        `xml:"updated"`
+	Link     atomLink      `xml:"link"`
+	Summary  string        `xml:"summary"`
+	Author   atomAuthor    `xml:"author"`
+	Category *atomCategory `xml:"category"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Category    string  `xml:"category,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+const feedTitle = "Новые объявления"
+
+// writeFeed - лента страницы объявлений, self - адрес самой ленты
+func writeFeed(w io.Writer, format, self string, ads []AdvertisementList, links Links) error {
+	// Время ленты - самое позднее изменение среди объявлений
+	updated := time.Unix(0, 0)
+	for _, ad := range ads {
+		if ad.UpdatedAt.After(updated) {
+			updated = ad.UpdatedAt
+		}
+	}
+
+	var feed any
+	switch format {
+	case FeedFormatAtom:
+		atom := atomFeed{
+			ID:      self,
+			Title:   feedTitle,
+			Updated: updated.UTC().Format(time.RFC3339),
+			Link:    atomLink{Rel: "self", Href: self},
+			Entries: make([]atomEntry, 0, len(ads)),
+		}
+		for _, ad := range ads {
+			entry := atomEntry{
+				ID:      "urn:uuid:" + ad.ID.String(),
+				Title:   ad.Title,
+				Updated: ad.UpdatedAt.UTC().Format(time.RFC3339),
+				Link:    atomLink{Rel: "alternate", Href: links.Page(ad.ID)},
+				Summary: feedSummary(&ad),
+				Author:  atomAuthor{Name: ad.AuthorLogin},
+			}
+			if ad.Category != "" {
+				entry.Category = &atomCategory{Term: ad.Category}
+			}
+			atom.Entries = append(atom.Entries, entry)
+		}
+		feed = atom
+	case FeedFormatRSS:
+		rss := rssFeed{Version: "2.0", Channel: rssChannel{
+			Title:         feedTitle,
+			Link:          links.PublicURL,
+			Description:   feedTitle,
+			LastBuildDate: updated.UTC().Format(time.RFC1123Z),
+			Items:         make([]rssItem, 0, len(ads)),
+		}}
+		for _, ad := range ads {
+			rss.Channel.Items = append(rss.Channel.Items, rssItem{
+				Title:       ad.Title,
+				Link:        links.Page(ad.ID),
+				Description: feedSummary(&ad),
+				GUID:        rssGUID{Value: "urn:uuid:" + ad.ID.String()},
+				PubDate:     ad.UpdatedAt.UTC().Format(time.RFC1123Z),
+				Category:    ad.Category,
+			})
+		}
+		feed = rss
+	default:
+		return fmt.Errorf("unsupported feed format %q", format)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	return xml.NewEncoder(w).Encode(feed)
+}
+
+// feedSummary - цена и описание объявления
+func feedSummary(ad *AdvertisementList) string {
+	kopecks := int64(ad.PriceKopecks)
+	return fmt.Sprintf("%d.%02d ₽. %s", kopecks/100, kopecks%100, ad.Description)
+}
@@ -0,0 +1,324 @@
+package advertisement_test
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"marketplace-api/internal/advertisement"
+	mockad "marketplace-api/internal/advertisement/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var exportLinks = advertisement.Links{PublicURL: "https://api.example.com", PageURL: "https://example.com/ads/{id}"}
+
+// batch - n объявлений, созданных по очереди
+func batch(n int) []advertisement.Advertisement {
+	ads := make([]advertisement.Advertisement, n)
+	for i := range ads {
+		ads[i] = advertisement.Advertisement{ID: uuid.New(), Title: "Phone", CreatedAt: time.Now().Add(-time.Duration(i) * time.Minute)}
+	}
+	return ads
+}
+
+func TestService_Export(t *testing.T) {
+	authorID := uuid.New()
+	filter := &advertisement.ExportFilter{AuthorID: &authorID}
+
+	t.Run("пачки читаются после последнего объявления предыдущей", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		first, second := batch(500), batch(3)
+		gomock.InOrder(
+			mockRepo.EXPECT().ExportBatch(gomock.Any(), filter, nil, 500).Return(first, nil),
+			mockRepo.EXPECT().ExportBatch(gomock.Any(), filter, &first[499], 500).Return(second, nil),
+		)
+
+		var count int
+		err := service.Export(context.Background(), filter, 0, func(*advertisement.Advertisement) error {
+			count++
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 503, count)
+	})
+
+	t.Run("не больше limit", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		first := batch(500)
+		gomock.InOrder(
+			mockRepo.EXPECT().ExportBatch(gomock.Any(), filter, nil, 500).Return(first, nil),
+			mockRepo.EXPECT().ExportBatch(gomock.Any(), filter, &first[499], 100).Return(batch(100), nil),
+		)
+
+		var count int
+		err := service.Export(context.Background(), filter, 600, func(*advertisement.Advertisement) error {
+			count++
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 600, count)
+	})
+
+	t.Run("ошибка записи прерывает выгрузку", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		errWrite := errors.New("connection reset")
+		mockRepo.EXPECT().ExportBatch(gomock.Any(), filter, nil, 500).Return(batch(500), nil)
+
+		err := service.Export(context.Background(), filter, 0, func(*advertisement.Advertisement) error { return errWrite })
+		assert.ErrorIs(t, err, errWrite)
+	})
+}
+
+func setupExportHandlerTest(t *testing.T) (*gomock.Controller, *mockad.MockExporterInterface, *advertisement.ExportHandler) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockService := mockad.NewMockExporterInterface(ctrl)
+	return ctrl, mockService, advertisement.NewExportHandler(mockService, exportLinks)
+}
+
+// exportAds - выгрузка, передающая fn объявления ads
+func exportAds(ads ...advertisement.Advertisement) func(context.Context, *advertisement.ExportFilter, int, func(*advertisement.Advertisement) error) error {
+	return func(_ context.Context, _ *advertisement.ExportFilter, _ int, fn func(*advertisement.Advertisement) error) error {
+		for i := range ads {
+			if err := fn(&ads[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestExportHandler_ExportAds(t *testing.T) {
+	userID := uuid.New()
+	publishAt := time.Date(2026, 11, 1, 9, 0, 0, 0, time.UTC)
+	ad := advertisement.Advertisement{
+		ID: uuid.New(), ExternalSKU: "SKU-1", Title: "Phone", Description: "Line one, \"quoted\"\nline two",
+		ImageURL: "http://example.com/a.jpg", PriceKopecks: 1000, ListingType: advertisement.ListingTypeFixed,
+		Status: advertisement.StatusScheduled, AuthorID: userID, PublishAt: &publishAt,
+	}
+
+	t.Run("CSV по умолчанию", func(t *testing.T) {
+		ctrl, mockService, handler := setupExportHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().Export(gomock.Any(), &advertisement.ExportFilter{AuthorID: &userID}, 0, gomock.Any()).
+			DoAndReturn(exportAds(ad))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/me/advertisements/export", nil)
+		w := httptest.NewRecorder()
+
+		handler.ExportAds(w, withUserContext(req, userID))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+
+		records, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, "external_sku", records[0][1])
+		assert.Equal(t, []string{ad.ID.String(), "SKU-1", "Phone", ad.Description, ad.ImageURL, "1000", ""},
+			records[1][:7])
+		assert.Equal(t, "2026-11-01T09:00:00Z", records[1][9])
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		ctrl, mockService, handler := setupExportHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().Export(gomock.Any(), gomock.Any(), 0, gomock.Any()).DoAndReturn(exportAds(ad, ad))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/me/advertisements/export?format=json", nil)
+		w := httptest.NewRecorder()
+
+		handler.ExportAds(w, withUserContext(req, userID))
+		var ads []advertisement.Advertisement
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &ads))
+		assert.Len(t, ads, 2)
+		assert.Equal(t, "SKU-1", ads[1].ExternalSKU)
+	})
+
+	t.Run("JSON без объявлений", func(t *testing.T) {
+		ctrl, mockService, handler := setupExportHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().Export(gomock.Any(), gomock.Any(), 0, gomock.Any()).DoAndReturn(exportAds())
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/me/advertisements/export?format=json", nil)
+		w := httptest.NewRecorder()
+
+		handler.ExportAds(w, withUserContext(req, userID))
+		assert.JSONEq(t, "[]", w.Body.String())
+	})
+
+	t.Run("ошибка чтения обрывает ответ", func(t *testing.T) {
+		ctrl, mockService, handler := setupExportHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().Export(gomock.Any(), gomock.Any(), 0, gomock.Any()).Return(errors.New("db is down"))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/me/advertisements/export", nil)
+		w := httptest.NewRecorder()
+
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			handler.ExportAds(w, withUserContext(req, userID))
+		})
+	})
+
+	t.Run("ошибка: неизвестный формат", func(t *testing.T) {
+		_, _, handler := setupExportHandlerTest(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/me/advertisements/export?format=xlsx", nil)
+		w := httptest.NewRecorder()
+
+		handler.ExportAds(w, withUserContext(req, userID))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("ошибка: неавторизован", func(t *testing.T) {
+		_, _, handler := setupExportHandlerTest(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/me/advertisements/export", nil)
+		w := httptest.NewRecorder()
+
+		handler.ExportAds(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestExportHandler_Feed(t *testing.T) {
+	updatedAt := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	ads := []advertisement.AdvertisementList{
+		{ID: uuid.New(), Title: "Phone", Description: "Good <phone>", PriceKopecks: 123456, Category: "electronics",
+			AuthorLogin: "seller", UpdatedAt: updatedAt},
+	}
+
+	t.Run("Atom с фильтром списка", func(t *testing.T) {
+		ctrl, mockService, handler := setupExportHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().ListAd(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, params *advertisement.AdvertisementListParams) (*[]advertisement.AdvertisementList, error) {
+				assert.Equal(t, 1000, params.MinPriceKopecks)
+				assert.Equal(t, "price", params.SortBy)
+				assert.Equal(t, 50, params.Limit)
+				assert.Nil(t, params.UserID)
+				return &ads, nil
+			})
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/advertisement/feed?min_price_kopecks=1000&sort_by=price&limit=50", nil)
+		w := httptest.NewRecorder()
+
+		handler.Feed(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/atom+xml; charset=utf-8", w.Header().Get("Content-Type"))
+
+		var feed struct {
+			ID      string `xml:"id"`
+			Updated string `xml:"updated"`
+			Entries []struct {
+				ID      string `xml:"id"`
+				Summary string `xml:"summary"`
+				Link    struct {
+					Href string `xml:"href,attr"`
+				} `xml:"link"`
+			} `xml:"entry"`
+		}
+		require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &feed))
+		assert.Equal(t, "https://api.example.com/api/v1/advertisement/feed?min_price_kopecks=1000&sort_by=price&limit=50", feed.ID)
+		assert.Equal(t, "2026-10-19T12:00:00Z", feed.Updated)
+		require.Len(t, feed.Entries, 1)
+		assert.Equal(t, "urn:uuid:"+ads[0].ID.String(), feed.Entries[0].ID)
+		assert.Equal(t, "https://example.com/ads/"+ads[0].ID.String(), feed.Entries[0].Link.Href)
+		assert.Equal(t, "1234.56 ₽. Good <phone>", feed.Entries[0].Summary)
+	})
+
+	t.Run("RSS", func(t *testing.T) {
+		ctrl, mockService, handler := setupExportHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().ListAd(gomock.Any(), gomock.Any()).Return(&ads, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/advertisement/feed?format=rss", nil)
+		w := httptest.NewRecorder()
+
+		handler.Feed(w, req)
+		assert.Equal(t, "application/rss+xml; charset=utf-8", w.Header().Get("Content-Type"))
+
+		var feed struct {
+			Items []struct {
+				Link     string `xml:"link"`
+				PubDate  string `xml:"pubDate"`
+				Category string `xml:"category"`
+			} `xml:"channel>item"`
+		}
+		require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &feed))
+		require.Len(t, feed.Items, 1)
+		assert.Equal(t, "https://example.com/ads/"+ads[0].ID.String(), feed.Items[0].Link)
+		assert.Equal(t, "Mon, 19 Oct 2026 12:00:00 +0000", feed.Items[0].PubDate)
+		assert.Equal(t, "electronics", feed.Items[0].Category)
+	})
+
+	t.Run("ошибка: неизвестный формат", func(t *testing.T) {
+		_, _, handler := setupExportHandlerTest(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/advertisement/feed?format=json", nil)
+		w := httptest.NewRecorder()
+
+		handler.Feed(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("ошибка: некорректные параметры списка", func(t *testing.T) {
+		_, _, handler := setupExportHandlerTest(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/advertisement/feed?page=first", nil)
+		w := httptest.NewRecorder()
+
+		handler.Feed(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestExportHandler_Sitemap(t *testing.T) {
+	ctrl, mockService, handler := setupExportHandlerTest(t)
+	defer ctrl.Finish()
+
+	ads := batch(2)
+	ads[0].UpdatedAt = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	mockService.EXPECT().Export(gomock.Any(), &advertisement.ExportFilter{}, advertisement.SitemapMaxURLs, gomock.Any()).
+		DoAndReturn(exportAds(ads...))
+
+	req := httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil)
+	w := httptest.NewRecorder()
+
+	handler.Sitemap(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.HasPrefix(w.Body.String(), "<?xml"))
+
+	var sitemap struct {
+		XMLName xml.Name `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+		URLs    []struct {
+			Loc     string `xml:"loc"`
+			LastMod string `xml:"lastmod"`
+		} `xml:"url"`
+	}
+	require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &sitemap))
+	require.Len(t, sitemap.URLs, 2)
+	assert.Equal(t, "https://example.com/ads/"+ads[0].ID.String(), sitemap.URLs[0].Loc)
+	assert.Equal(t, "2026-10-19T12:00:00Z", sitemap.URLs[0].LastMod)
+}
@@ -3,9 +3,18 @@ package advertisement
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"marketplace-api/internal/auth"
+	"marketplace-api/internal/httpcache"
+	"marketplace-api/internal/logging"
+	"marketplace-api/internal/tracing"
+	"mime"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -13,14 +22,33 @@ import (
 type ServiceInterface interface {
 	Create(ctx context.Context, input *CreateAdvertisementInput) (*Advertisement, error)
 	ListAd(ctx context.Context, params *AdvertisementListParams) (*[]AdvertisementList, error)
+	GetAd(ctx context.Context, id uuid.UUID, userID *uuid.UUID) (*AdvertisementList, error)
+	Update(ctx context.Context, input *UpdateAdvertisementInput) (*AdvertisementList, error)
+	Renew(ctx context.Context, input *RenewAdvertisementInput) (*AdvertisementList, error)
+	ListScheduled(ctx context.Context, userID uuid.UUID) ([]AdvertisementList, error)
+	Reschedule(ctx context.Context, input *ScheduleAdvertisementInput) (*AdvertisementList, error)
+	CancelSchedule(ctx context.Context, input *CancelScheduleInput) (*AdvertisementList, error)
+}
+
+// ViewRecorder - учёт просмотров объявлений, не должен блокировать обработку запроса.
+// nil отключает учёт
+type ViewRecorder interface {
+	RecordView(ctx context.Context, advertisementID uuid.UUID, viewer string)
+}
+
+// ClientIPResolver - адрес клиента с учётом доверенных прокси
+type ClientIPResolver interface {
+	ClientIP(r *http.Request) string
 }
 
 type Handler struct {
 	service ServiceInterface
+	views   ViewRecorder
+	ips     ClientIPResolver
 }
 
-func NewAdHandler(service ServiceInterface) *Handler {
-	return &Handler{service: service}
+func NewAdHandler(service ServiceInterface, views ViewRecorder, ips ClientIPResolver) *Handler {
+	return &Handler{service: service, views: views, ips: ips}
 }
 
 // CreateAd godoc
@@ -30,18 +58,15 @@ func NewAdHandler(service ServiceInterface) *Handler {
 // @Accept json
 // @Produce json
 // @Param input body CreateAdvertisementInput true "Данные объявления"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ"
 // @Success 201 {object} Advertisement
 // @Failure 400 {string} string "Неверный ввод или обязательные поля пусты"
 // @Failure 401 {string} string "Пользователь не авторизован"
 // @Failure 405 {string} string "Метод не разрешён"
+// @Failure 409 {string} string "Объявление с таким external_sku уже есть"
 // @Security AuthToken
-// @Router /advertisement [post]
+// @Router /api/v1/advertisement [post]
 func (h *Handler) CreateAd(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	//Получения ID авторизованного пользователя
 	userID, ok := auth.UserIDFromContext(r.Context())
 	if !ok {
@@ -65,7 +90,7 @@ func (h *Handler) CreateAd(w http.ResponseWriter, r *http.Request) {
 	input.AuthorID = userID
 	ad, err := h.service.Create(r.Context(), &input)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
@@ -90,93 +115,655 @@ func (h *Handler) CreateAd(w http.ResponseWriter, r *http.Request) {
 // @Failure 400 {string} string "Некорректные параметры запроса"
 // @Failure 405 {string} string "Метод не разрешён"
 // @Security AuthToken
-// @Router /advertisement/ [get]
+// @Router /api/v1/advertisement/ [get]
 func (h *Handler) ListAd(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	params, err := parseListParams(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	// Получаем userID из контекста, если есть
-	userID, ok := auth.UserIDFromContext(r.Context())
-	var userIDPtr *uuid.UUID
-	if ok {
-		userIDPtr = &userID
-	} else {
-		userIDPtr = nil
+	if userID, ok := auth.UserIDFromContext(r.Context()); ok {
+		params.UserID = &userID
 	}
 
-	// Получаем параметры запроса
-	query := r.URL.Query()
-	var (
-		page, limit, minPrice, maxPrice int
-		err                             error
-	)
+	listAd, err := h.service.ListAd(r.Context(), params)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	_, span := tracing.Start(r.Context(), "advertisement.encodeList")
+	defer span.End()
+
+	// Last-Modified списка - самое позднее изменение среди объявлений страницы
+	var lastModified time.Time
+	for _, ad := range *listAd {
+		if ad.UpdatedAt.After(lastModified) {
+			lastModified = ad.UpdatedAt
+		}
+	}
+	httpcache.WriteJSON(w, r, http.StatusOK, listAd, lastModified)
+}
+
+// parseListParams - параметры ленты из строки запроса: страница, фильтр по цене и сортировка
+func parseListParams(query url.Values) (*AdvertisementListParams, error) {
+	params := AdvertisementListParams{
+		Page:          1, //Параметр по умолчанию
+		SortBy:        query.Get("sort_by"),
+		SortDirection: query.Get("sort_direction"),
+	}
+	var err error
 
 	//Получение page
 	if pageStr := query.Get("page"); pageStr != "" {
-		page, err = strconv.Atoi(pageStr)
-		if err != nil {
-			http.Error(w, "page must be an integer", http.StatusBadRequest)
-			return
+		if params.Page, err = strconv.Atoi(pageStr); err != nil {
+			return nil, errors.New("page must be an integer")
 		}
-	} else {
-		page = 1 //Параметр по умолчанию
 	}
 
 	//Получение limit
 	if limitStr := query.Get("limit"); limitStr != "" {
-		limit, err = strconv.Atoi(limitStr)
-		if err != nil {
-			http.Error(w, "limit must be an integer", http.StatusBadRequest)
-			return
+		if params.Limit, err = strconv.Atoi(limitStr); err != nil {
+			return nil, errors.New("limit must be an integer")
 		}
-	} else {
-		limit = 10 //Параметр по умолчанию
 	}
 
-	//Получение мминимальной цены
+	//Получение минимальной цены
 	if minStr := query.Get("min_price_kopecks"); minStr != "" {
-		minPrice, err = strconv.Atoi(minStr)
-		if err != nil {
-			http.Error(w, "min_price_kopecks must be an integer", http.StatusBadRequest)
-			return
+		if params.MinPriceKopecks, err = strconv.Atoi(minStr); err != nil {
+			return nil, errors.New("min_price_kopecks must be an integer")
 		}
-	} else {
-		minPrice = 0 //Параметр по умолчанию
 	}
 
 	//Получение максимальной цены
 	if maxStr := query.Get("max_price_kopecks"); maxStr != "" {
-		maxPrice, err = strconv.Atoi(maxStr)
+		if params.MaxPriceKopecks, err = strconv.Atoi(maxStr); err != nil {
+			return nil, errors.New("max_price_kopecks must be an integer")
+		}
+	}
+
+	return &params, nil
+}
+
+// GetAd godoc
+// @Summary Получить объявление
+// @Description Возвращает объявление по ID и учитывает просмотр (если пользователь авторизован добавляет параметр is_owner к ответу)
+// @Tags advertisement
+// @Produce json
+// @Param id path string true "ID объявления"
+// @Success 200 {object} AdvertisementList
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Объявление не найдено"
+// @Failure 405 {string} string "Метод не разрешён"
+// @Security AuthToken
+// @Router /api/v1/advertisement/{id} [get]
+func (h *Handler) GetAd(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "id must be a valid UUID", http.StatusBadRequest)
+		return
+	}
+
+	// Получаем userID из контекста, если есть
+	var userIDPtr *uuid.UUID
+	if userID, ok := auth.UserIDFromContext(r.Context()); ok {
+		userIDPtr = &userID
+	}
+
+	ad, err := h.service.GetAd(r.Context(), id, userIDPtr)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Просмотры автора не учитываются
+	if h.views != nil && (ad.IsOwner == nil || !*ad.IsOwner) {
+		h.views.RecordView(r.Context(), ad.ID, viewerKey(h.ips.ClientIP(r), userIDPtr))
+	}
+
+	httpcache.WriteJSON(w, r, http.StatusOK, ad, ad.UpdatedAt)
+}
+
+// UpdateAd godoc
+// @Summary Изменить объявление
+// @Description Изменяет переданные поля объявления автора. С заголовком If-Match изменение применяется, только если объявление не менялось с получения этого ETag
+// @Tags advertisement
+// @Accept json
+// @Produce json
+// @Param id path string true "ID объявления"
+// @Param input body UpdateAdvertisementInput true "Изменяемые поля"
+// @Param If-Match header string false "ETag из ответа GET /advertisement/{id}"
+// @Success 200 {object} AdvertisementList
+// @Failure 400 {string} string "Неверный ввод"
+// @Failure 401 {string} string "Пользователь не авторизован"
+// @Failure 403 {string} string "Объявление принадлежит другому пользователю"
+// @Failure 404 {string} string "Объявление не найдено"
+// @Failure 412 {string} string "Объявление изменилось, ETag устарел"
+// @Security AuthToken
+// @Router /api/v1/advertisement/{id} [patch]
+func (h *Handler) UpdateAd(w http.ResponseWriter, r *http.Request) {
+	//Получения ID авторизованного пользователя
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "id must be a valid UUID", http.StatusBadRequest)
+		return
+	}
+
+	var input UpdateAdvertisementInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "invalid input", http.StatusBadRequest)
+		return
+	}
+	input.AdvertisementID = id
+	input.UserID = userID
+
+	// If-Match сверяется с текущим представлением, а сервис применяет изменение,
+	// только если объявление с тех пор не менялось
+	if r.Header.Get("If-Match") != "" {
+		current, err := h.service.GetAd(r.Context(), id, &userID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		_, etag, err := httpcache.Encode(current)
 		if err != nil {
-			http.Error(w, "max_price_kopecks must be an integer", http.StatusBadRequest)
+			http.Error(w, "error encoding advertisement", http.StatusInternalServerError)
+			return
+		}
+		if _, ok := httpcache.IfMatch(r, etag); !ok {
+			http.Error(w, ErrModified.Error(), http.StatusPreconditionFailed)
 			return
 		}
-	} else {
-		maxPrice = 0 //Параметр по умолчанию
+		input.UnmodifiedSince = &current.UpdatedAt
+	}
+
+	ad, err := h.service.Update(r.Context(), &input)
+	if err != nil {
+		writeError(w, r, err)
+		return
 	}
 
-	sortBy := query.Get("sort_by")
-	sortDirection := query.Get("sort_direction")
+	httpcache.WriteJSON(w, r, http.StatusOK, ad, ad.UpdatedAt)
+}
 
-	params := AdvertisementListParams{
-		Page:            page,
-		Limit:           limit,
-		SortBy:          sortBy,
-		SortDirection:   sortDirection,
-		MinPriceKopecks: minPrice,
-		MaxPriceKopecks: maxPrice,
-		UserID:          userIDPtr,
+// Renew godoc
+// @Summary Продлить объявление
+// @Description Продлевает срок размещения объявления автора на срок его категории и возвращает снятое объявление в публикацию
+// @Tags advertisement
+// @Accept json
+// @Produce json
+// @Param input body RenewAdvertisementInput true "ID объявления"
+// @Success 200 {object} AdvertisementList
+// @Failure 400 {string} string "Неверный ввод"
+// @Failure 401 {string} string "Пользователь не авторизован"
+// @Failure 403 {string} string "Объявление принадлежит другому пользователю"
+// @Failure 404 {string} string "Объявление не найдено"
+// @Failure 405 {string} string "Метод не разрешён"
+// @Failure 409 {string} string "Объявление ещё не опубликовано или торги по нему завершены"
+// @Security AuthToken
+// @Router /api/v1/advertisement/renew [post]
+func (h *Handler) Renew(w http.ResponseWriter, r *http.Request) {
+	//Получения ID авторизованного пользователя
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
 	}
 
-	listAd, err := h.service.ListAd(r.Context(), &params)
+	var input RenewAdvertisementInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "invalid input", http.StatusBadRequest)
+		return
+	}
+	if input.AdvertisementID == uuid.Nil {
+		http.Error(w, "all fields are required", http.StatusBadRequest)
+		return
+	}
+
+	//Вызов сервиса
+	input.UserID = userID
+	ad, err := h.service.Renew(r.Context(), &input)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(ad)
+}
+
+// ListScheduled godoc
+// @Summary Запланированные объявления
+// @Description Возвращает объявления авторизованного пользователя, ожидающие публикации, и черновики с отменённой публикацией
+// @Tags advertisement
+// @Produce json
+// @Success 200 {array} AdvertisementList
+// @Failure 401 {string} string "Пользователь не авторизован"
+// @Failure 405 {string} string "Метод не разрешён"
+// @Security AuthToken
+// @Router /api/v1/me/advertisements/scheduled [get]
+func (h *Handler) ListScheduled(w http.ResponseWriter, r *http.Request) {
+	//Получения ID авторизованного пользователя
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ads, err := h.service.ListScheduled(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(ads)
+}
+
+// Reschedule godoc
+// @Summary Перенести публикацию
+// @Description Назначает новое время публикации для запланированного объявления или черновика
+// @Tags advertisement
+// @Accept json
+// @Produce json
+// @Param input body ScheduleAdvertisementInput true "ID объявления и время публикации"
+// @Success 200 {object} AdvertisementList
+// @Failure 400 {string} string "Неверный ввод"
+// @Failure 401 {string} string "Пользователь не авторизован"
+// @Failure 403 {string} string "Объявление принадлежит другому пользователю"
+// @Failure 404 {string} string "Объявление не найдено"
+// @Failure 405 {string} string "Метод не разрешён"
+// @Failure 409 {string} string "Объявление уже опубликовано"
+// @Security AuthToken
+// @Router /api/v1/advertisement/schedule [post]
+func (h *Handler) Reschedule(w http.ResponseWriter, r *http.Request) {
+	//Получения ID авторизованного пользователя
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var input ScheduleAdvertisementInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "invalid input", http.StatusBadRequest)
+		return
+	}
+	if input.AdvertisementID == uuid.Nil || input.PublishAt.IsZero() {
+		http.Error(w, "all fields are required", http.StatusBadRequest)
+		return
+	}
+
+	//Вызов сервиса
+	input.UserID = userID
+	ad, err := h.service.Reschedule(r.Context(), &input)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(listAd)
+	json.NewEncoder(w).Encode(ad)
+}
+
+// CancelSchedule godoc
+// @Summary Отменить публикацию
+// @Description Отменяет запланированную публикацию, объявление остаётся черновиком
+// @Tags advertisement
+// @Accept json
+// @Produce json
+// @Param input body CancelScheduleInput true "ID объявления"
+// @Success 200 {object} AdvertisementList
+// @Failure 400 {string} string "Неверный ввод"
+// @Failure 401 {string} string "Пользователь не авторизован"
+// @Failure 403 {string} string "Объявление принадлежит другому пользователю"
+// @Failure 404 {string} string "Объявление не найдено"
+// @Failure 405 {string} string "Метод не разрешён"
+// @Failure 409 {string} string "Объявление уже опубликовано"
+// @Security AuthToken
+// @Router /api/v1/advertisement/schedule/cancel [post]
+func (h *Handler) CancelSchedule(w http.ResponseWriter, r *http.Request) {
+	//Получения ID авторизованного пользователя
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var input CancelScheduleInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "invalid input", http.StatusBadRequest)
+		return
+	}
+	if input.AdvertisementID == uuid.Nil {
+		http.Error(w, "all fields are required", http.StatusBadRequest)
+		return
+	}
+
+	//Вызов сервиса
+	input.UserID = userID
+	ad, err := h.service.CancelSchedule(r.Context(), &input)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(ad)
+}
+
+// ImporterInterface - импорт объявлений из файла
+type ImporterInterface interface {
+	Start(ctx context.Context, input *ImportInput) (*ImportJob, error)
+	Get(ctx context.Context, userID, id uuid.UUID) (*ImportJob, error)
+}
+
+type ImportHandler struct {
+	importer ImporterInterface
+}
+
+func NewImportHandler(importer ImporterInterface) *ImportHandler {
+	return &ImportHandler{importer: importer}
+}
+
+// StartImport godoc
+// @Summary Импорт объявлений из файла
+// @Description Принимает файл CSV (text/csv) или JSON Lines (application/x-ndjson) и ставит импорт в очередь. Формат берётся из параметра format или Content-Type. В CSV первая строка - названия колонок: title, description, image_url, price_kopecks обязательны, category, external_sku, publish_at (RFC 3339) - нет. В JSON Lines каждая строка - объект как при создании объявления. Каждая строка проверяется как при создании объявления, строки с ошибками пропускаются и перечисляются в результате. Строка с external_sku, который уже есть у продавца, обновляет название, описание, изображение, цену и категорию объявления. С dry_run=true строки только проверяются. Не более 5000 объявлений в файле
+// @Tags advertisement
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Produce json
+// @Param format query string false "Формат файла (csv, ndjson), по умолчанию по Content-Type"
+// @Param dry_run query bool false "Только проверить файл, ничего не сохраняя"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ"
+// @Success 202 {object} ImportJob
+// @Failure 400 {string} string "Файл пуст, слишком велик или некорректен"
+// @Failure 401 {string} string "Пользователь не авторизован"
+// @Failure 413 {string} string "Слишком большой запрос"
+// @Failure 415 {string} string "Неподдерживаемый формат"
+// @Security AuthToken
+// @Router /api/v1/advertisement/imports [post]
+func (h *ImportHandler) StartImport(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	input := ImportInput{AuthorID: userID, Format: importFormat(r)}
+	if dryRun := r.URL.Query().Get("dry_run"); dryRun != "" {
+		var err error
+		if input.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			http.Error(w, "dry_run must be true or false", http.StatusBadRequest)
+			return
+		}
+	}
+
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "error reading request body", http.StatusBadRequest)
+		return
+	}
+	input.Payload = payload
+
+	job, err := h.importer.Start(r.Context(), &input)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", r.URL.Path+"/"+job.ID.String())
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+}
+
+// GetImport godoc
+// @Summary Состояние импорта объявлений
+// @Description Возвращает статус задания импорта, счётчики созданных, обновлённых и ошибочных строк и ошибки строк с номерами
+// @Tags advertisement
+// @Produce json
+// @Param id path string true "ID задания импорта"
+// @Success 200 {object} ImportJob
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 401 {string} string "Пользователь не авторизован"
+// @Failure 404 {string} string "Задание не найдено"
+// @Security AuthToken
+// @Router /api/v1/advertisement/imports/{id} [get]
+func (h *ImportHandler) GetImport(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "id must be a valid UUID", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.importer.Get(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+// importFormat - формат файла из параметра format или, если он не задан, из Content-Type
+func importFormat(r *http.Request) string {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return strings.ToLower(format)
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "text/csv":
+		return ImportFormatCSV
+	case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/x-jsonlines":
+		return ImportFormatNDJSON
+	default:
+		return ""
+	}
+}
+
+// ExporterInterface - выгрузка объявлений и ленты
+type ExporterInterface interface {
+	ListAd(ctx context.Context, params *AdvertisementListParams) (*[]AdvertisementList, error)
+	Export(ctx context.Context, filter *ExportFilter, limit int, fn func(*Advertisement) error) error
+}
+
+type ExportHandler struct {
+	service ExporterInterface
+	links   Links
+}
+
+func NewExportHandler(service ExporterInterface, links Links) *ExportHandler {
+	return &ExportHandler{service: service, links: links}
+}
+
+// ExportAds godoc
+// @Summary Выгрузка объявлений продавца
+// @Description Выгружает все объявления пользователя в любом статусе от новых к старым. CSV содержит колонки id, external_sku, title, description, image_url, price_kopecks, category, listing_type, status, publish_at, expires_at, created_at, updated_at, JSON - массив объявлений. Файл передаётся по мере чтения; при сбое соединение обрывается, а не завершается неполным файлом
+// @Tags advertisement
+// @Produce text/csv
+// @Produce json
+// @Param format query string false "Формат (csv, json)" default(csv)
+// @Success 200 {file} file
+// @Failure 400 {string} string "Неизвестный формат"
+// @Failure 401 {string} string "Пользователь не авторизован"
+// @Security AuthToken
+// @Router /api/v1/me/advertisements/export [get]
+func (h *ExportHandler) ExportAds(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = ExportFormatCSV
+	}
+	var enc exportEncoder
+	switch format {
+	case ExportFormatCSV:
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="advertisements.csv"`)
+		csvEnc, err := newCSVEncoder(w)
+		if err != nil {
+			return
+		}
+		enc = csvEnc
+	case ExportFormatJSON:
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", `attachment; filename="advertisements.json"`)
+		enc = &jsonEncoder{w: w}
+	default:
+		http.Error(w, "format must be csv or json", http.StatusBadRequest)
+		return
+	}
+
+	h.stream(r, &ExportFilter{AuthorID: &userID}, 0, enc)
+}
+
+// Feed godoc
+// @Summary Лента объявлений Atom/RSS
+// @Description Лента опубликованных объявлений для агрегаторов. Параметры страницы, фильтра по цене и сортировки - как у списка объявлений
+// @Tags advertisement
+// @Produce application/atom+xml
+// @Produce application/rss+xml
+// @Param format query string false "Формат ленты (atom, rss)" default(atom)
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество элементов в ленте" default(10)
+// @Param sort_by query string false "Поле для сортировки (created_at, price)" default(created_at)
+// @Param sort_direction query string false "Направление сортировки (asc, desc)" default(desc)
+// @Param min_price_kopecks query int false "Минимальная цена в копейках" default(0)
+// @Param max_price_kopecks query int false "Максимальная цена в копейках" default(0)
+// @Success 200 {string} string "Лента"
+// @Failure 400 {string} string "Некорректные параметры запроса"
+// @Router /api/v1/advertisement/feed [get]
+func (h *ExportHandler) Feed(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = FeedFormatAtom
+	}
+	if format != FeedFormatAtom && format != FeedFormatRSS {
+		http.Error(w, "format must be atom or rss", http.StatusBadRequest)
+		return
+	}
+
+	// Лента одинакова для всех, признак владельца в ней не нужен
+	params, err := parseListParams(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ads, err := h.service.ListAd(r.Context(), params)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/"+format+"+xml; charset=utf-8")
+	self := h.links.PublicURL + r.URL.RequestURI()
+	if err := writeFeed(w, format, self, *ads, h.links); err != nil {
+		logging.FromContext(r.Context()).Error("error writing advertisement feed", "error", err)
+	}
+}
+
+// Sitemap godoc
+// @Summary Карта сайта
+// @Description Карта сайта (sitemaps.org) со страницами опубликованных объявлений, не более 50000 самых новых.
+// @Description Отдаётся из корня сайта: карта описывает только адреса внутри своего каталога
+// @Tags advertisement
+// @Produce application/xml
+// @Success 200 {string} string "Карта сайта"
+// @Router /sitemap.xml [get]
+func (h *ExportHandler) Sitemap(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	enc, err := newSitemapEncoder(w, h.links)
+	if err != nil {
+		return
+	}
+	h.stream(r, &ExportFilter{}, SitemapMaxURLs, enc)
+}
+
+// stream - передаёт выгрузку в enc. Ответ к этому моменту уже начат, поэтому при ошибке
+// соединение обрывается: клиент не должен принять обрезанный файл за полный
+func (h *ExportHandler) stream(r *http.Request, filter *ExportFilter, limit int, enc exportEncoder) {
+	err := h.service.Export(r.Context(), filter, limit, enc.Encode)
+	if err == nil {
+		err = enc.Close()
+	}
+	if err != nil {
+		if r.Context().Err() == nil {
+			logging.FromContext(r.Context()).Error("error exporting advertisements", "error", err)
+		}
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// errorStatus сопоставляет ошибку сервиса с HTTP-статусом
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrAdNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrNotOwner):
+		return http.StatusForbidden
+	case errors.Is(err, ErrAlreadyPublished), errors.Is(err, ErrNotPublishedYet), errors.Is(err, ErrSKUTaken),
+		errors.Is(err, ErrListingClosed):
+		return http.StatusConflict
+	case errors.Is(err, ErrModified):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, ErrImportNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrUnsupportedFormat):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, ErrInvalidInput), errors.Is(err, ErrEmptyImport), errors.Is(err, ErrTooManyImportRows),
+		errors.Is(err, errInvalidImportFile):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeError - ответ с ошибкой сервиса. Непредвиденная ошибка пишется в лог, а клиент
+// получает общее сообщение без подробностей устройства сервиса
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status := errorStatus(err)
+	if status == http.StatusInternalServerError {
+		logging.FromContext(r.Context()).Error("request failed", "error", err)
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	http.Error(w, err.Error(), status)
+}
+
+// viewerKey - идентификатор зрителя для дедупликации просмотров: пользователь или IP-адрес клиента
+func viewerKey(clientIP string, userID *uuid.UUID) string {
+	if userID != nil {
+		return "user:" + userID.String()
+	}
+	return "ip:" + clientIP
 }
@@ -2,26 +2,41 @@ package advertisement_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"marketplace-api/internal/advertisement"
 	mockad "marketplace-api/internal/advertisement/mock"
 	"marketplace-api/internal/auth"
+	"marketplace-api/internal/httpcache"
+	"marketplace-api/internal/ratelimit"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
 func setupHandlerTest(t *testing.T) (*gomock.Controller, *mockad.MockServiceInterface, *advertisement.Handler) {
+	t.Helper()
+	ctrl, mockService, _, handler := setupViewsHandlerTest(t)
+	return ctrl, mockService, handler
+}
+
+func setupViewsHandlerTest(t *testing.T) (*gomock.Controller, *mockad.MockServiceInterface, *mockad.MockViewRecorder, *advertisement.Handler) {
 	t.Helper()
 	ctrl := gomock.NewController(t)
 	mockService := mockad.NewMockServiceInterface(ctrl)
-	handler := advertisement.NewAdHandler(mockService)
-	return ctrl, mockService, handler
+	mockViews := mockad.NewMockViewRecorder(ctrl)
+	ips, err := ratelimit.NewIPResolver([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+	handler := advertisement.NewAdHandler(mockService, mockViews, ips)
+	return ctrl, mockService, mockViews, handler
 }
 
 func withUserContext(r *http.Request, userID uuid.UUID) *http.Request {
@@ -70,16 +85,6 @@ func TestHandler_CreateAd(t *testing.T) {
 		assert.Equal(t, validInput.Title, result.Title)
 	})
 
-	t.Run("ошибка: не POST", func(t *testing.T) {
-		_, _, handler := setupHandlerTest(t)
-
-		req := httptest.NewRequest(http.MethodGet, "/advertisement", nil)
-		w := httptest.NewRecorder()
-
-		handler.CreateAd(w, req)
-		assert.Equal(t, http.StatusMethodNotAllowed, w.Result().StatusCode)
-	})
-
 	t.Run("ошибка: неавторизован", func(t *testing.T) {
 		_, _, handler := setupHandlerTest(t)
 
@@ -115,7 +120,7 @@ func TestHandler_CreateAd(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
 	})
 
-	t.Run("ошибка: ошибка сервиса", func(t *testing.T) {
+	t.Run("ошибка: данные не прошли проверку", func(t *testing.T) {
 		ctrl, mockService, handler := setupHandlerTest(t)
 		defer ctrl.Finish()
 
@@ -126,10 +131,29 @@ func TestHandler_CreateAd(t *testing.T) {
 
 		mockService.EXPECT().
 			Create(gomock.Any(), gomock.Any()).
-			Return(nil, errors.New("something went wrong"))
+			Return(nil, fmt.Errorf("%w: invalid category", advertisement.ErrInvalidInput))
 
 		handler.CreateAd(w, req)
 		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+		assert.Contains(t, w.Body.String(), "invalid category")
+	})
+
+	t.Run("ошибка: непредвиденная ошибка сервиса", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		body, _ := json.Marshal(validInput)
+		req := httptest.NewRequest(http.MethodPost, "/advertisement", bytes.NewReader(body))
+		req = withUserContext(req, validUserID)
+		w := httptest.NewRecorder()
+
+		mockService.EXPECT().
+			Create(gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("connection refused"))
+
+		handler.CreateAd(w, req)
+		assert.Equal(t, http.StatusInternalServerError, w.Result().StatusCode)
+		assert.NotContains(t, w.Body.String(), "connection refused", "подробности ошибки не раскрываются клиенту")
 	})
 }
 
@@ -176,17 +200,6 @@ func TestHandler_ListAd(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
-	t.Run("ошибка метода", func(t *testing.T) {
-		_, _, handler := setupHandlerTest(t)
-
-		req := httptest.NewRequest(http.MethodPost, "/advertisements/", nil)
-		w := httptest.NewRecorder()
-
-		handler.ListAd(w, req)
-		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
-		assert.Contains(t, w.Body.String(), "method not allowed")
-	})
-
 	t.Run("ошибка: невалидный page", func(t *testing.T) {
 		_, _, handler := setupHandlerTest(t)
 		req := httptest.NewRequest(http.MethodGet, "/advertisement/?page=abc", nil)
@@ -246,7 +259,509 @@ func TestHandler_ListAd(t *testing.T) {
 		mockService.EXPECT().ListAd(gomock.Any(), expectedParams).Return(nil, errors.New("service error"))
 
 		handler.ListAd(w, req)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.NotContains(t, w.Body.String(), "service error")
+	})
+}
+
+func TestHandler_GetAd(t *testing.T) {
+	adID := uuid.New()
+	viewerID := uuid.New()
+	notOwner := false
+	owner := true
+
+	t.Run("успешный запрос с учётом просмотра", func(t *testing.T) {
+		ctrl, mockService, mockViews, handler := setupViewsHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().GetAd(gomock.Any(), adID, &viewerID).
+			Return(&advertisement.AdvertisementList{ID: adID, Title: "Test Ad", IsOwner: &notOwner}, nil)
+		mockViews.EXPECT().RecordView(gomock.Any(), adID, "user:"+viewerID.String())
+
+		req := httptest.NewRequest(http.MethodGet, "/advertisement/"+adID.String(), nil)
+		req.SetPathValue("id", adID.String())
+		req = withUserContext(req, viewerID)
+		w := httptest.NewRecorder()
+
+		handler.GetAd(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Test Ad")
+	})
+
+	t.Run("анонимный просмотр учитывается по IP", func(t *testing.T) {
+		ctrl, mockService, mockViews, handler := setupViewsHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().GetAd(gomock.Any(), adID, nil).
+			Return(&advertisement.AdvertisementList{ID: adID}, nil)
+		mockViews.EXPECT().RecordView(gomock.Any(), adID, "ip:192.0.2.1")
+
+		req := httptest.NewRequest(http.MethodGet, "/advertisement/"+adID.String(), nil)
+		req.SetPathValue("id", adID.String())
+		w := httptest.NewRecorder()
+
+		handler.GetAd(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("за доверенным прокси учитывается IP клиента", func(t *testing.T) {
+		ctrl, mockService, mockViews, handler := setupViewsHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().GetAd(gomock.Any(), adID, nil).
+			Return(&advertisement.AdvertisementList{ID: adID}, nil)
+		mockViews.EXPECT().RecordView(gomock.Any(), adID, "ip:203.0.113.7")
+
+		req := httptest.NewRequest(http.MethodGet, "/advertisement/"+adID.String(), nil)
+		req.SetPathValue("id", adID.String())
+		req.RemoteAddr = "10.0.0.2:4321"
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		w := httptest.NewRecorder()
+
+		handler.GetAd(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("X-Forwarded-For от клиента без прокси не учитывается", func(t *testing.T) {
+		ctrl, mockService, mockViews, handler := setupViewsHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().GetAd(gomock.Any(), adID, nil).
+			Return(&advertisement.AdvertisementList{ID: adID}, nil)
+		mockViews.EXPECT().RecordView(gomock.Any(), adID, "ip:192.0.2.1")
+
+		req := httptest.NewRequest(http.MethodGet, "/advertisement/"+adID.String(), nil)
+		req.SetPathValue("id", adID.String())
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		w := httptest.NewRecorder()
+
+		handler.GetAd(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("просмотр автора не учитывается", func(t *testing.T) {
+		ctrl, mockService, _, handler := setupViewsHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().GetAd(gomock.Any(), adID, &viewerID).
+			Return(&advertisement.AdvertisementList{ID: adID, IsOwner: &owner}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/advertisement/"+adID.String(), nil)
+		req.SetPathValue("id", adID.String())
+		req = withUserContext(req, viewerID)
+		w := httptest.NewRecorder()
+
+		handler.GetAd(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("повторный запрос с If-None-Match получает 304", func(t *testing.T) {
+		ctrl, mockService, mockViews, handler := setupViewsHandlerTest(t)
+		defer ctrl.Finish()
+
+		ad := &advertisement.AdvertisementList{ID: adID, Title: "Test Ad", UpdatedAt: time.Now()}
+		mockService.EXPECT().GetAd(gomock.Any(), adID, nil).Return(ad, nil).Times(2)
+		mockViews.EXPECT().RecordView(gomock.Any(), adID, gomock.Any()).Times(2)
+
+		req := httptest.NewRequest(http.MethodGet, "/advertisement/"+adID.String(), nil)
+		req.SetPathValue("id", adID.String())
+		w := httptest.NewRecorder()
+		handler.GetAd(w, req)
+		etag := w.Header().Get("ETag")
+		assert.NotEmpty(t, etag)
+		assert.NotEmpty(t, w.Header().Get("Last-Modified"))
+
+		req = httptest.NewRequest(http.MethodGet, "/advertisement/"+adID.String(), nil)
+		req.SetPathValue("id", adID.String())
+		req.Header.Set("If-None-Match", etag)
+		w = httptest.NewRecorder()
+		handler.GetAd(w, req)
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("ошибка: объявление не найдено", func(t *testing.T) {
+		ctrl, mockService, _, handler := setupViewsHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().GetAd(gomock.Any(), adID, nil).Return(nil, advertisement.ErrAdNotFound)
+
+		req := httptest.NewRequest(http.MethodGet, "/advertisement/"+adID.String(), nil)
+		req.SetPathValue("id", adID.String())
+		w := httptest.NewRecorder()
+
+		handler.GetAd(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("ошибка: невалидный id", func(t *testing.T) {
+		_, _, handler := setupHandlerTest(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/advertisement/abc", nil)
+		req.SetPathValue("id", "abc")
+		w := httptest.NewRecorder()
+
+		handler.GetAd(w, req)
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		assert.Contains(t, w.Body.String(), "service error")
+	})
+}
+
+func TestHandler_Renew(t *testing.T) {
+	userID := uuid.New()
+	input := advertisement.RenewAdvertisementInput{AdvertisementID: uuid.New()}
+
+	t.Run("успешное продление", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().Renew(gomock.Any(), &advertisement.RenewAdvertisementInput{
+			AdvertisementID: input.AdvertisementID, UserID: userID,
+		}).Return(&advertisement.AdvertisementList{ID: input.AdvertisementID, Status: advertisement.StatusActive}, nil)
+
+		body, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/advertisement/renew", bytes.NewReader(body))
+		req = withUserContext(req, userID)
+		w := httptest.NewRecorder()
+
+		handler.Renew(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("ошибка: чужое объявление", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().Renew(gomock.Any(), gomock.Any()).Return(nil, advertisement.ErrNotOwner)
+
+		body, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/advertisement/renew", bytes.NewReader(body))
+		req = withUserContext(req, userID)
+		w := httptest.NewRecorder()
+
+		handler.Renew(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("ошибка: неавторизован", func(t *testing.T) {
+		_, _, handler := setupHandlerTest(t)
+
+		body, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/advertisement/renew", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		handler.Renew(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestHandler_Reschedule(t *testing.T) {
+	userID := uuid.New()
+	input := advertisement.ScheduleAdvertisementInput{AdvertisementID: uuid.New(), PublishAt: time.Now().Add(time.Hour)}
+
+	t.Run("успешный перенос", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().Reschedule(gomock.Any(), gomock.Any()).
+			Return(&advertisement.AdvertisementList{ID: input.AdvertisementID, Status: advertisement.StatusScheduled}, nil)
+
+		body, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/advertisement/schedule", bytes.NewReader(body))
+		req = withUserContext(req, userID)
+		w := httptest.NewRecorder()
+
+		handler.Reschedule(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("ошибка: уже опубликовано", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().Reschedule(gomock.Any(), gomock.Any()).Return(nil, advertisement.ErrAlreadyPublished)
+
+		body, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/advertisement/schedule", bytes.NewReader(body))
+		req = withUserContext(req, userID)
+		w := httptest.NewRecorder()
+
+		handler.Reschedule(w, req)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("ошибка: пустые поля", func(t *testing.T) {
+		_, _, handler := setupHandlerTest(t)
+
+		req := httptest.NewRequest(http.MethodPost, "/advertisement/schedule", bytes.NewReader([]byte(`{}`)))
+		req = withUserContext(req, userID)
+		w := httptest.NewRecorder()
+
+		handler.Reschedule(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestHandler_CancelSchedule(t *testing.T) {
+	userID := uuid.New()
+	input := advertisement.CancelScheduleInput{AdvertisementID: uuid.New()}
+
+	t.Run("успешная отмена", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().CancelSchedule(gomock.Any(), &advertisement.CancelScheduleInput{
+			AdvertisementID: input.AdvertisementID, UserID: userID,
+		}).Return(&advertisement.AdvertisementList{Status: advertisement.StatusDraft}, nil)
+
+		body, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/advertisement/schedule/cancel", bytes.NewReader(body))
+		req = withUserContext(req, userID)
+		w := httptest.NewRecorder()
+
+		handler.CancelSchedule(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestHandler_ListScheduled(t *testing.T) {
+	t.Run("успешный запрос", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		userID := uuid.New()
+		mockService.EXPECT().ListScheduled(gomock.Any(), userID).Return([]advertisement.AdvertisementList{}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/me/advertisements/scheduled", nil)
+		req = withUserContext(req, userID)
+		w := httptest.NewRecorder()
+
+		handler.ListScheduled(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("ошибка: неавторизован", func(t *testing.T) {
+		_, _, handler := setupHandlerTest(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/me/advertisements/scheduled", nil)
+		w := httptest.NewRecorder()
+
+		handler.ListScheduled(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestHandler_UpdateAd(t *testing.T) {
+	adID, userID := uuid.New(), uuid.New()
+	owner := true
+	current := &advertisement.AdvertisementList{ID: adID, Title: "Old title", IsOwner: &owner, UpdatedAt: time.Now()}
+	body := `{"title":"New title"}`
+
+	newRequest := func(ifMatch string) *http.Request {
+		req := httptest.NewRequest(http.MethodPatch, "/advertisement/"+adID.String(), bytes.NewBufferString(body))
+		req.SetPathValue("id", adID.String())
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		return withUserContext(req, userID)
+	}
+
+	t.Run("успешное изменение", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().Update(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *advertisement.UpdateAdvertisementInput) (*advertisement.AdvertisementList, error) {
+				assert.Equal(t, adID, input.AdvertisementID)
+				assert.Equal(t, userID, input.UserID)
+				assert.Equal(t, "New title", *input.Title)
+				assert.Nil(t, input.UnmodifiedSince)
+				return &advertisement.AdvertisementList{ID: adID, Title: "New title"}, nil
+			})
+
+		w := httptest.NewRecorder()
+		handler.UpdateAd(w, newRequest(""))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotEmpty(t, w.Header().Get("ETag"))
+		assert.Contains(t, w.Body.String(), "New title")
+	})
+
+	t.Run("успешное изменение с актуальным If-Match", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		_, etag, err := httpcache.Encode(current)
+		assert.NoError(t, err)
+		mockService.EXPECT().GetAd(gomock.Any(), adID, &userID).Return(current, nil)
+		mockService.EXPECT().Update(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *advertisement.UpdateAdvertisementInput) (*advertisement.AdvertisementList, error) {
+				assert.Equal(t, current.UpdatedAt, *input.UnmodifiedSince)
+				return &advertisement.AdvertisementList{ID: adID, Title: "New title"}, nil
+			})
+
+		w := httptest.NewRecorder()
+		handler.UpdateAd(w, newRequest(etag))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("ошибка: устаревший If-Match", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().GetAd(gomock.Any(), adID, &userID).Return(current, nil)
+
+		w := httptest.NewRecorder()
+		handler.UpdateAd(w, newRequest(`"stale"`))
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	})
+
+	t.Run("ошибка: объявление изменилось во время записи", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil, advertisement.ErrModified)
+
+		w := httptest.NewRecorder()
+		handler.UpdateAd(w, newRequest(""))
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	})
+
+	t.Run("ошибка: чужое объявление", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil, advertisement.ErrNotOwner)
+
+		w := httptest.NewRecorder()
+		handler.UpdateAd(w, newRequest(""))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("ошибка: неавторизован", func(t *testing.T) {
+		_, _, handler := setupHandlerTest(t)
+
+		req := httptest.NewRequest(http.MethodPatch, "/advertisement/"+adID.String(), bytes.NewBufferString(body))
+		req.SetPathValue("id", adID.String())
+		w := httptest.NewRecorder()
+
+		handler.UpdateAd(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestImportHandler(t *testing.T) {
+	userID := uuid.New()
+	jobID := uuid.New()
+
+	setup := func(t *testing.T) (*gomock.Controller, *mockad.MockImporterInterface, *advertisement.ImportHandler) {
+		t.Helper()
+		ctrl := gomock.NewController(t)
+		mockImporter := mockad.NewMockImporterInterface(ctrl)
+		return ctrl, mockImporter, advertisement.NewImportHandler(mockImporter)
+	}
+
+	t.Run("формат по Content-Type", func(t *testing.T) {
+		ctrl, mockImporter, handler := setup(t)
+		defer ctrl.Finish()
+
+		mockImporter.EXPECT().Start(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *advertisement.ImportInput) (*advertisement.ImportJob, error) {
+				assert.Equal(t, userID, input.AuthorID)
+				assert.Equal(t, advertisement.ImportFormatNDJSON, input.Format)
+				assert.True(t, input.DryRun)
+				assert.Equal(t, "{}\n", string(input.Payload))
+				return &advertisement.ImportJob{ID: jobID, Status: advertisement.ImportPending}, nil
+			})
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/advertisement/imports?dry_run=true", bytes.NewBufferString("{}\n"))
+		req.Header.Set("Content-Type", "application/x-ndjson; charset=utf-8")
+		w := httptest.NewRecorder()
+
+		handler.StartImport(w, withUserContext(req, userID))
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, "/api/v1/advertisement/imports/"+jobID.String(), w.Header().Get("Location"))
+	})
+
+	t.Run("формат из параметра", func(t *testing.T) {
+		ctrl, mockImporter, handler := setup(t)
+		defer ctrl.Finish()
+
+		mockImporter.EXPECT().Start(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *advertisement.ImportInput) (*advertisement.ImportJob, error) {
+				assert.Equal(t, advertisement.ImportFormatCSV, input.Format)
+				assert.False(t, input.DryRun)
+				return &advertisement.ImportJob{ID: jobID}, nil
+			})
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/advertisement/imports?format=CSV", bytes.NewBufferString("title\n"))
+		w := httptest.NewRecorder()
+
+		handler.StartImport(w, withUserContext(req, userID))
+		assert.Equal(t, http.StatusAccepted, w.Code)
+	})
+
+	t.Run("ошибка: неподдерживаемый формат", func(t *testing.T) {
+		ctrl, mockImporter, handler := setup(t)
+		defer ctrl.Finish()
+
+		mockImporter.EXPECT().Start(gomock.Any(), gomock.Any()).Return(nil, advertisement.ErrUnsupportedFormat)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/advertisement/imports", bytes.NewBufferString("x"))
+		req.Header.Set("Content-Type", "application/pdf")
+		w := httptest.NewRecorder()
+
+		handler.StartImport(w, withUserContext(req, userID))
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	})
+
+	t.Run("ошибка: некорректный dry_run", func(t *testing.T) {
+		_, _, handler := setup(t)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/advertisement/imports?dry_run=maybe", bytes.NewBufferString("x"))
+		w := httptest.NewRecorder()
+
+		handler.StartImport(w, withUserContext(req, userID))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("ошибка: неавторизован", func(t *testing.T) {
+		_, _, handler := setup(t)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/advertisement/imports", bytes.NewBufferString("x"))
+		w := httptest.NewRecorder()
+
+		handler.StartImport(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("состояние задания", func(t *testing.T) {
+		ctrl, mockImporter, handler := setup(t)
+		defer ctrl.Finish()
+
+		mockImporter.EXPECT().Get(gomock.Any(), userID, jobID).Return(&advertisement.ImportJob{
+			ID: jobID, Status: advertisement.ImportCompleted, Failed: 1,
+			Errors: []advertisement.ImportRowError{{Line: 3, Error: "invalid price"}},
+		}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/advertisement/imports/"+jobID.String(), nil)
+		req.SetPathValue("id", jobID.String())
+		w := httptest.NewRecorder()
+
+		handler.GetImport(w, withUserContext(req, userID))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"line":3`)
+	})
+
+	t.Run("ошибка: задание не найдено", func(t *testing.T) {
+		ctrl, mockImporter, handler := setup(t)
+		defer ctrl.Finish()
+
+		mockImporter.EXPECT().Get(gomock.Any(), userID, jobID).Return(nil, advertisement.ErrImportNotFound)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/advertisement/imports/"+jobID.String(), nil)
+		req.SetPathValue("id", jobID.String())
+		w := httptest.NewRecorder()
+
+		handler.GetImport(w, withUserContext(req, userID))
+		assert.Equal(t, http.StatusNotFound, w.Code)
 	})
 }
@@ -0,0 +1,400 @@
+package advertisement
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"marketplace-api/internal/logging"
+	"marketplace-api/internal/tracing"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Форматы файла импорта
+const (
+	ImportFormatCSV    = "csv"    // заголовок с названиями колонок, далее строка на объявление
+	ImportFormatNDJSON = "ndjson" // JSON-объект CreateAdvertisementInput на строку
+)
+
+// Статусы задания импорта
+const (
+	ImportPending   = "pending"   // ожидает обработки
+	ImportRunning   = "running"   // обрабатывается
+	ImportCompleted = "completed" // все строки обработаны, ошибки строк - в errors
+	ImportFailed    = "failed"    // файл не удалось обработать
+)
+
+const (
+	// MaxImportRows - сколько объявлений можно загрузить одним файлом
+	MaxImportRows = 5000
+	// maxImportErrors - сколько ошибок строк сохраняется, счётчик failed учитывает все
+	maxImportErrors = 1000
+	// importProgressEvery - через сколько строк сохраняется прогресс проверки без сохранения
+	// и строк с ошибками; прогресс сохранённой строки записывается вместе с ней
+	importProgressEvery = 50
+	maxNDJSONLine       = 1 << 20
+)
+
+var (
+	ErrImportNotFound    = errors.New("import not found")
+	ErrUnsupportedFormat = errors.New("unsupported import format: must be csv or ndjson")
+	ErrEmptyImport       = errors.New("import file contains no advertisements")
+	ErrTooManyImportRows = fmt.Errorf("import file must contain at most %d advertisements", MaxImportRows)
+	errInvalidImportFile = errors.New("invalid import file")
+)
+
+// Колонки CSV, порядок произвольный
+var (
+	requiredImportColumns = []string{"title", "description", "image_url", "price_kopecks"}
+	optionalImportColumns = []string{"category", "external_sku", "publish_at"}
+)
+
+// ImportJob - задание импорта объявлений и его результат
+type ImportJob struct {
+	ID         uuid.UUID        `json:"id"`
+	AuthorID   uuid.UUID        `json:"-"`
+	Format     string           `json:"format"`
+	DryRun     bool             `json:"dry_run"` // только проверка: объявления не создаются и не меняются
+	Status     string           `json:"status"`
+	TotalRows  int              `json:"total_rows"`
+	Processed  int              `json:"processed"`
+	Created    int              `json:"created"` // при dry_run - сколько было бы создано
+	Updated    int              `json:"updated"` // обновлено по external_sku, при dry_run - было бы обновлено
+	Failed     int              `json:"failed"`
+	Errors     []ImportRowError `json:"errors"` // первые 1000 ошибок строк
+	Error      string           `json:"error,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+	StartedAt  *time.Time       `json:"started_at,omitempty"`
+	FinishedAt *time.Time       `json:"finished_at,omitempty"`
+}
+
+// ImportRowError - ошибка строки файла импорта
+type ImportRowError struct {
+	Line        int    `json:"line"` // номер строки в файле, с единицы
+	ExternalSKU string `json:"external_sku,omitempty"`
+	Error       string `json:"error"`
+}
+
+// ImportInput - загруженный файл импорта
+type ImportInput struct {
+	AuthorID uuid.UUID
+	Format   string
+	DryRun   bool
+	Payload  []byte
+}
+
+// importRow - разобранная строка файла: объявление или ошибка разбора
+type importRow struct {
+	line  int
+	input CreateAdvertisementInput
+	err   error
+}
+
+// Importer - асинхронный импорт объявлений: файл сохраняется заданием, строки обрабатываются
+// фоновой задачей. Каждая строка проверяется как при создании объявления и сохраняется
+// отдельно, поэтому ошибка строки не отменяет остальные. Строка с external_sku, уже
+// встречавшимся у автора, обновляет содержимое объявления, а не создаёт новое
+type Importer struct {
+	service *Service
+	tx      Transactor
+	store   ImportStore
+	lease   time.Duration
+}
+
+// NewImporter - lease - через сколько без сохранения прогресса задание считается брошенным
+// и продолжается другим экземпляром
+func NewImporter(service *Service, tx Transactor, store ImportStore, lease time.Duration) *Importer {
+	return &Importer{service: service, tx: tx, store: store, lease: lease}
+}
+
+// Start - проверяет структуру файла и ставит задание в очередь
+func (i *Importer) Start(ctx context.Context, input *ImportInput) (*ImportJob, error) {
+	ctx, span := tracing.Start(ctx, "advertisement.Importer.Start")
+	defer span.End()
+
+	rows, err := parseImport(input.Format, input.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrEmptyImport
+	}
+
+	return i.store.CreateImport(ctx, &ImportJob{
+		AuthorID:  input.AuthorID,
+		Format:    input.Format,
+		DryRun:    input.DryRun,
+		Status:    ImportPending,
+		TotalRows: len(rows),
+		Errors:    []ImportRowError{},
+	}, input.Payload)
+}
+
+// Get - задание импорта автора
+func (i *Importer) Get(ctx context.Context, userID, id uuid.UUID) (*ImportJob, error) {
+	ctx, span := tracing.Start(ctx, "advertisement.Importer.Get")
+	defer span.End()
+
+	job, err := i.store.GetImport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil || job.AuthorID != userID {
+		return nil, ErrImportNotFound
+	}
+	return job, nil
+}
+
+// ProcessNext - обрабатывает следующее задание. Возвращает false, если заданий нет.
+// Брошенное задание продолжается со строки после последнего сохранённого прогресса
+func (i *Importer) ProcessNext(ctx context.Context) (bool, error) {
+	job, payload, err := i.store.ClaimImport(ctx, i.lease)
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	ctx, span := tracing.Start(ctx, "advertisement.Importer.ProcessNext")
+	defer span.End()
+	log := logging.FromContext(ctx).With("import_id", job.ID, "dry_run", job.DryRun)
+
+	rows, err := parseImport(job.Format, payload)
+	if err != nil {
+		job.Status, job.Error = ImportFailed, err.Error()
+		return true, i.finish(ctx, job)
+	}
+
+	// При проверке без сохранения повторный артикул в файле - обновление первого
+	seen := make(map[string]bool)
+	for _, row := range rows[:job.Processed] {
+		seen[row.input.ExternalSKU] = true
+	}
+
+	for n, row := range rows[job.Processed:] {
+		err := row.err
+		if err == nil && job.DryRun {
+			var isUpdate bool
+			if isUpdate, err = i.importRow(ctx, job, &row.input, seen); err == nil {
+				countImported(job, isUpdate)
+			}
+		} else if err == nil {
+			// Объявление и прогресс сохраняются в одной транзакции: после сбоя строка
+			// без артикула не создаст объявление повторно
+			var next ImportJob
+			var saveErr error
+			err = i.tx.WithinTx(ctx, func(ctx context.Context) error {
+				next = *job
+				isUpdate, err := i.importRow(ctx, &next, &row.input, seen)
+				if err != nil {
+					return err
+				}
+				countImported(&next, isUpdate)
+				next.Processed++
+				saveErr = i.store.SaveImportProgress(ctx, &next)
+				return saveErr
+			})
+			if saveErr != nil {
+				return true, saveErr
+			}
+			if err == nil {
+				*job = next
+				continue
+			}
+		}
+		if ctx.Err() != nil {
+			// Остановка приложения: строка будет обработана заново после истечения аренды
+			return true, ctx.Err()
+		}
+		if err != nil && row.err == nil && !isRowError(err) {
+			// Сбой, а не ошибка строки: строка будет обработана заново после истечения аренды
+			return true, err
+		}
+
+		// Строки с ошибкой ничего не меняют, их прогресс сохраняется реже
+		if err != nil {
+			job.Failed++
+			if len(job.Errors) < maxImportErrors {
+				job.Errors = append(job.Errors, ImportRowError{Line: row.line, ExternalSKU: row.input.ExternalSKU, Error: err.Error()})
+			}
+		}
+		job.Processed++
+
+		if (n+1)%importProgressEvery == 0 {
+			if err := i.store.SaveImportProgress(ctx, job); err != nil {
+				return true, err
+			}
+		}
+	}
+
+	job.Status = ImportCompleted
+	log.Info("advertisement import completed", "created", job.Created, "updated", job.Updated, "failed", job.Failed)
+	return true, i.finish(ctx, job)
+}
+
+// isRowError - ошибка в данных строки: повтор её не исправит, она сообщается автору
+func isRowError(err error) bool {
+	return errors.Is(err, ErrInvalidInput) || errors.Is(err, ErrSKUTaken) ||
+		errors.Is(err, ErrAdNotFound) || errors.Is(err, ErrNotOwner)
+}
+
+func countImported(job *ImportJob, isUpdate bool) {
+	if isUpdate {
+		job.Updated++
+	} else {
+		job.Created++
+	}
+}
+
+func (i *Importer) finish(ctx context.Context, job *ImportJob) error {
+	now := time.Now()
+	job.FinishedAt = &now
+	return i.store.SaveImportProgress(ctx, job)
+}
+
+// importRow - проверяет и сохраняет строку, возвращает true, если объявление обновлено
+func (i *Importer) importRow(ctx context.Context, job *ImportJob, input *CreateAdvertisementInput, seen map[string]bool) (bool, error) {
+	input.AuthorID = job.AuthorID
+	isUpdate, err := i.service.UpsertBySKU(ctx, input, job.DryRun)
+	if err != nil || !job.DryRun {
+		return isUpdate, err
+	}
+	isUpdate = isUpdate || (input.ExternalSKU != "" && seen[input.ExternalSKU])
+	seen[input.ExternalSKU] = true
+	return isUpdate, nil
+}
+
+// parseImport - разбирает файл импорта. Ошибка возвращается, если файл нельзя обработать
+// целиком, ошибки отдельных строк - в importRow.err
+func parseImport(format string, payload []byte) ([]importRow, error) {
+	payload = bytes.TrimPrefix(payload, []byte("\xef\xbb\xbf"))
+	var rows []importRow
+	var err error
+	switch format {
+	case ImportFormatCSV:
+		rows, err = parseCSV(payload)
+	case ImportFormatNDJSON:
+		rows, err = parseNDJSON(payload)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > MaxImportRows {
+		return nil, ErrTooManyImportRows
+	}
+	return rows, nil
+}
+
+func parseCSV(payload []byte) ([]importRow, error) {
+	reader := csv.NewReader(bytes.NewReader(payload))
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidImportFile, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for n, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(requiredImportColumns, name) && !slices.Contains(optionalImportColumns, name) {
+			return nil, fmt.Errorf("%w: unknown column %q", errInvalidImportFile, name)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("%w: duplicate column %q", errInvalidImportFile, name)
+		}
+		columns[name] = n
+	}
+	for _, name := range requiredImportColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", errInvalidImportFile, name)
+		}
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rows = append(rows, importRow{line: parseErr.StartLine, err: parseErr.Err})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errInvalidImportFile, err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := importRow{line: line}
+		row.input, row.err = csvInput(record, columns)
+		rows = append(rows, row)
+	}
+}
+
+// csvInput - объявление из строки CSV, columns - номера колонок по названию
+func csvInput(record []string, columns map[string]int) (CreateAdvertisementInput, error) {
+	field := func(name string) string {
+		if n, ok := columns[name]; ok {
+			return strings.TrimSpace(record[n])
+		}
+		return ""
+	}
+
+	input := CreateAdvertisementInput{
+		Title:       field("title"),
+		Description: field("description"),
+		ImageURL:    field("image_url"),
+		Category:    field("category"),
+		ExternalSKU: field("external_sku"),
+	}
+	price, err := strconv.Atoi(field("price_kopecks"))
+	if err != nil {
+		return input, errors.New("invalid price_kopecks: must be an integer")
+	}
+	input.PriceKopecks = price
+	if publishAt := field("publish_at"); publishAt != "" {
+		t, err := time.Parse(time.RFC3339, publishAt)
+		if err != nil {
+			return input, errors.New("invalid publish_at: must be RFC 3339 time")
+		}
+		input.PublishAt = &t
+	}
+	return input, nil
+}
+
+func parseNDJSON(payload []byte) ([]importRow, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(payload))
+	scanner.Buffer(make([]byte, 0, 64<<10), maxNDJSONLine)
+
+	var rows []importRow
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		row := importRow{line: line}
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&row.input); err != nil {
+			row.err = fmt.Errorf("invalid json: %v", err)
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: line too long or unreadable: %v", errInvalidImportFile, err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,274 @@
+package advertisement_test
+
+import (
+	"context"
+	"errors"
+	"marketplace-api/internal/advertisement"
+	mockad "marketplace-api/internal/advertisement/mock"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const importCSV = `title,description,image_url,price_kopecks,external_sku
+New phone,Brand new,http://example.com/a.jpg,1000,SKU-1
+Old phone,Updated text,http://example.com/b.jpg,2000,SKU-2
+Bad phone,Broken,http://example.com/c.jpg,abc,SKU-3
+`
+
+func setupImportTest(t *testing.T) (*gomock.Controller, *mockad.MockRepositoryInterface, *mockad.MockImportStore, *advertisement.Importer) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockRepo := mockad.NewMockRepositoryInterface(ctrl)
+	mockStore := mockad.NewMockImportStore(ctrl)
+	service := advertisement.NewAdService(mockRepo, noTx{}, &eventLog{}, lifetime, pagination)
+	return ctrl, mockRepo, mockStore, advertisement.NewImporter(service, noTx{}, mockStore, time.Minute)
+}
+
+func TestImporter_Start(t *testing.T) {
+	authorID := uuid.New()
+
+	t.Run("задание ставится в очередь", func(t *testing.T) {
+		ctrl, _, mockStore, importer := setupImportTest(t)
+		defer ctrl.Finish()
+
+		mockStore.EXPECT().CreateImport(gomock.Any(), gomock.Any(), []byte(importCSV)).
+			DoAndReturn(func(_ context.Context, job *advertisement.ImportJob, _ []byte) (*advertisement.ImportJob, error) {
+				assert.Equal(t, authorID, job.AuthorID)
+				assert.Equal(t, advertisement.ImportPending, job.Status)
+				assert.Equal(t, 3, job.TotalRows)
+				assert.True(t, job.DryRun)
+				job.ID = uuid.New()
+				return job, nil
+			})
+
+		job, err := importer.Start(context.Background(), &advertisement.ImportInput{
+			AuthorID: authorID, Format: advertisement.ImportFormatCSV, DryRun: true, Payload: []byte(importCSV),
+		})
+		assert.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, job.ID)
+	})
+
+	t.Run("ошибка: неизвестный формат", func(t *testing.T) {
+		ctrl, _, _, importer := setupImportTest(t)
+		defer ctrl.Finish()
+
+		_, err := importer.Start(context.Background(), &advertisement.ImportInput{AuthorID: authorID, Format: "xlsx", Payload: []byte("x")})
+		assert.ErrorIs(t, err, advertisement.ErrUnsupportedFormat)
+	})
+
+	t.Run("ошибка: нет обязательной колонки", func(t *testing.T) {
+		ctrl, _, _, importer := setupImportTest(t)
+		defer ctrl.Finish()
+
+		_, err := importer.Start(context.Background(), &advertisement.ImportInput{
+			AuthorID: authorID, Format: advertisement.ImportFormatCSV, Payload: []byte("title,description,image_url\na,b,c\n"),
+		})
+		assert.ErrorContains(t, err, `missing column "price_kopecks"`)
+	})
+
+	t.Run("ошибка: пустой файл", func(t *testing.T) {
+		ctrl, _, _, importer := setupImportTest(t)
+		defer ctrl.Finish()
+
+		_, err := importer.Start(context.Background(), &advertisement.ImportInput{
+			AuthorID: authorID, Format: advertisement.ImportFormatNDJSON, Payload: []byte("\n\n"),
+		})
+		assert.ErrorIs(t, err, advertisement.ErrEmptyImport)
+	})
+
+	t.Run("ошибка: слишком много строк", func(t *testing.T) {
+		ctrl, _, _, importer := setupImportTest(t)
+		defer ctrl.Finish()
+
+		payload := strings.Repeat("{}\n", advertisement.MaxImportRows+1)
+		_, err := importer.Start(context.Background(), &advertisement.ImportInput{
+			AuthorID: authorID, Format: advertisement.ImportFormatNDJSON, Payload: []byte(payload),
+		})
+		assert.ErrorIs(t, err, advertisement.ErrTooManyImportRows)
+	})
+}
+
+func TestImporter_ProcessNext(t *testing.T) {
+	authorID := uuid.New()
+	existingID := uuid.New()
+	owner := true
+	claimed := func(format string, dryRun bool) *advertisement.ImportJob {
+		return &advertisement.ImportJob{
+			ID: uuid.New(), AuthorID: authorID, Format: format, DryRun: dryRun,
+			Status: advertisement.ImportRunning, TotalRows: 3, Errors: []advertisement.ImportRowError{},
+		}
+	}
+
+	t.Run("заданий нет", func(t *testing.T) {
+		ctrl, _, mockStore, importer := setupImportTest(t)
+		defer ctrl.Finish()
+
+		mockStore.EXPECT().ClaimImport(gomock.Any(), time.Minute).Return(nil, nil, nil)
+
+		processed, err := importer.ProcessNext(context.Background())
+		assert.NoError(t, err)
+		assert.False(t, processed)
+	})
+
+	t.Run("создание, обновление по артикулу и ошибка строки", func(t *testing.T) {
+		ctrl, mockRepo, mockStore, importer := setupImportTest(t)
+		defer ctrl.Finish()
+
+		stored := &advertisement.AdvertisementList{
+			ID: existingID, Title: "Old phone", Description: "Old text", ImageURL: "http://example.com/b.jpg",
+			PriceKopecks: 1500, ListingType: advertisement.ListingTypeFixed, IsOwner: &owner,
+		}
+		mockStore.EXPECT().ClaimImport(gomock.Any(), time.Minute).Return(claimed(advertisement.ImportFormatCSV, false), []byte(importCSV), nil)
+		mockRepo.EXPECT().FindBySKU(gomock.Any(), authorID, "SKU-1").Return(nil, nil)
+		mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, ad *advertisement.Advertisement) (*advertisement.Advertisement, error) {
+				assert.Equal(t, "SKU-1", ad.ExternalSKU)
+				assert.Equal(t, authorID, ad.AuthorID)
+				ad.ID = uuid.New()
+				return ad, nil
+			})
+		mockRepo.EXPECT().FindBySKU(gomock.Any(), authorID, "SKU-2").Return(&existingID, nil)
+		mockRepo.EXPECT().GetByID(gomock.Any(), existingID, &authorID).Return(stored, nil).Times(2)
+		mockRepo.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, ad *advertisement.AdvertisementList, _ *time.Time) error {
+				assert.Equal(t, "Updated text", ad.Description)
+				assert.Equal(t, float64(2000), ad.PriceKopecks)
+				return nil
+			})
+		// Прогресс сохраняется вместе с каждым сохранённым объявлением и в конце задания
+		progress := func(processed, created, updated int) *gomock.Call {
+			return mockStore.EXPECT().SaveImportProgress(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, job *advertisement.ImportJob) error {
+					assert.Equal(t, advertisement.ImportRunning, job.Status)
+					assert.Equal(t, processed, job.Processed)
+					assert.Equal(t, created, job.Created)
+					assert.Equal(t, updated, job.Updated)
+					return nil
+				})
+		}
+		first, second := progress(1, 1, 0), progress(2, 1, 1)
+		mockStore.EXPECT().SaveImportProgress(gomock.Any(), gomock.Any()).After(second).
+			DoAndReturn(func(_ context.Context, job *advertisement.ImportJob) error {
+				assert.Equal(t, advertisement.ImportCompleted, job.Status)
+				assert.NotNil(t, job.FinishedAt)
+				assert.Equal(t, 3, job.Processed)
+				assert.Equal(t, 1, job.Created)
+				assert.Equal(t, 1, job.Updated)
+				assert.Equal(t, 1, job.Failed)
+				assert.Equal(t, []advertisement.ImportRowError{
+					{Line: 4, ExternalSKU: "SKU-3", Error: "invalid price_kopecks: must be an integer"},
+				}, job.Errors)
+				return nil
+			})
+
+		second.After(first)
+
+		processed, err := importer.ProcessNext(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, processed)
+	})
+
+	t.Run("ошибка сохранения прогресса откатывает строку и останавливает задание", func(t *testing.T) {
+		ctrl, mockRepo, mockStore, importer := setupImportTest(t)
+		defer ctrl.Finish()
+
+		errSave := errors.New("connection lost")
+		mockStore.EXPECT().ClaimImport(gomock.Any(), time.Minute).Return(claimed(advertisement.ImportFormatCSV, false), []byte(importCSV), nil)
+		mockRepo.EXPECT().FindBySKU(gomock.Any(), authorID, "SKU-1").Return(nil, nil)
+		mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&advertisement.Advertisement{ID: uuid.New()}, nil)
+		mockStore.EXPECT().SaveImportProgress(gomock.Any(), gomock.Any()).Return(errSave)
+
+		processed, err := importer.ProcessNext(context.Background())
+		assert.ErrorIs(t, err, errSave)
+		assert.True(t, processed)
+	})
+
+	t.Run("сбой БД не считается ошибкой строки", func(t *testing.T) {
+		ctrl, mockRepo, mockStore, importer := setupImportTest(t)
+		defer ctrl.Finish()
+
+		errDB := errors.New("conn closed")
+		mockStore.EXPECT().ClaimImport(gomock.Any(), time.Minute).Return(claimed(advertisement.ImportFormatCSV, false), []byte(importCSV), nil)
+		mockRepo.EXPECT().FindBySKU(gomock.Any(), authorID, "SKU-1").Return(nil, errDB)
+		// Прогресс не сохраняется: задание продолжится с этой строки после истечения аренды
+		mockStore.EXPECT().SaveImportProgress(gomock.Any(), gomock.Any()).Times(0)
+
+		processed, err := importer.ProcessNext(context.Background())
+		assert.ErrorIs(t, err, errDB)
+		assert.True(t, processed)
+	})
+
+	t.Run("dry run ничего не сохраняет", func(t *testing.T) {
+		ctrl, mockRepo, mockStore, importer := setupImportTest(t)
+		defer ctrl.Finish()
+
+		payload := `{"title":"New phone","description":"Brand new","image_url":"http://example.com/a.jpg","price_kopecks":1000,"external_sku":"SKU-1"}
+{"title":"New phone","description":"Cheaper","image_url":"http://example.com/a.jpg","price_kopecks":900,"external_sku":"SKU-1"}
+{"title":"x","description":"Too short title","image_url":"http://example.com/a.jpg","price_kopecks":900}
+`
+		mockStore.EXPECT().ClaimImport(gomock.Any(), time.Minute).Return(claimed(advertisement.ImportFormatNDJSON, true), []byte(payload), nil)
+		mockRepo.EXPECT().FindBySKU(gomock.Any(), authorID, "SKU-1").Return(nil, nil).Times(2)
+		mockStore.EXPECT().SaveImportProgress(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, job *advertisement.ImportJob) error {
+				assert.Equal(t, 1, job.Created)
+				assert.Equal(t, 1, job.Updated, "повтор артикула в файле обновляет первое объявление")
+				assert.Equal(t, 1, job.Failed)
+				assert.Equal(t, 3, job.Errors[0].Line)
+				return nil
+			})
+
+		processed, err := importer.ProcessNext(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, processed)
+	})
+
+	t.Run("брошенное задание продолжается с сохранённой строки", func(t *testing.T) {
+		ctrl, mockRepo, mockStore, importer := setupImportTest(t)
+		defer ctrl.Finish()
+
+		job := claimed(advertisement.ImportFormatCSV, true)
+		job.Processed, job.Created, job.Updated = 2, 1, 1
+		mockStore.EXPECT().ClaimImport(gomock.Any(), time.Minute).Return(job, []byte(importCSV), nil)
+		mockRepo.EXPECT().FindBySKU(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockStore.EXPECT().SaveImportProgress(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, job *advertisement.ImportJob) error {
+				assert.Equal(t, 3, job.Processed)
+				assert.Equal(t, 1, job.Failed)
+				return nil
+			})
+
+		_, err := importer.ProcessNext(context.Background())
+		assert.NoError(t, err)
+	})
+}
+
+func TestImporter_Get(t *testing.T) {
+	authorID := uuid.New()
+	id := uuid.New()
+
+	t.Run("задание автора", func(t *testing.T) {
+		ctrl, _, mockStore, importer := setupImportTest(t)
+		defer ctrl.Finish()
+
+		mockStore.EXPECT().GetImport(gomock.Any(), id).Return(&advertisement.ImportJob{ID: id, AuthorID: authorID}, nil)
+
+		job, err := importer.Get(context.Background(), authorID, id)
+		assert.NoError(t, err)
+		assert.Equal(t, id, job.ID)
+	})
+
+	t.Run("ошибка: задание другого пользователя", func(t *testing.T) {
+		ctrl, _, mockStore, importer := setupImportTest(t)
+		defer ctrl.Finish()
+
+		mockStore.EXPECT().GetImport(gomock.Any(), id).Return(&advertisement.ImportJob{ID: id, AuthorID: uuid.New()}, nil)
+
+		_, err := importer.Get(context.Background(), authorID, id)
+		assert.ErrorIs(t, err, advertisement.ErrImportNotFound)
+	})
+}
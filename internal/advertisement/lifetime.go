@@ -0,0 +1,42 @@
+package advertisement
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Lifetime - срок размещения объявлений: общий и переопределённый для отдельных категорий
+type Lifetime struct {
+	Default    time.Duration
+	ByCategory map[string]time.Duration
+}
+
+// For - срок размещения для категории
+func (l Lifetime) For(category string) time.Duration {
+	if d, ok := l.ByCategory[category]; ok {
+		return d
+	}
+	return l.Default
+}
+
+// ParseCategoryLifetimes разбирает сроки по категориям в формате "electronics=1440h,jobs=336h"
+func ParseCategoryLifetimes(raw string) (map[string]time.Duration, error) {
+	result := make(map[string]time.Duration)
+	if strings.TrimSpace(raw) == "" {
+		return result, nil
+	}
+
+	for _, pair := range strings.Split(raw, ",") {
+		category, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || category == "" {
+			return nil, fmt.Errorf("invalid category lifetime %q: expected category=duration", pair)
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid lifetime for category %q: %q", category, value)
+		}
+		result[strings.ToLower(category)] = d
+	}
+	return result, nil
+}
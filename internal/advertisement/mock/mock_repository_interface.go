@@ -12,8 +12,11 @@ package mockad
 import (
 	context "context"
 	advertisement "marketplace-api/internal/advertisement"
+	outbox "marketplace-api/internal/outbox"
 	reflect "reflect"
+	time "time"
 
+	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -21,6 +24,7 @@ import (
 type MockRepositoryInterface struct {
 	ctrl     *gomock.Controller
 	recorder *MockRepositoryInterfaceMockRecorder
+	isgomock struct{}
 }
 
 // MockRepositoryInterfaceMockRecorder is the mock recorder for MockRepositoryInterface.
@@ -40,6 +44,50 @@ func (m *MockRepositoryInterface) EXPECT() *MockRepositoryInterfaceMockRecorder
 	return m.recorder
 }
 
+// ArchiveExpired mocks base method.
+func (m *MockRepositoryInterface) ArchiveExpired(ctx context.Context, limit int) ([]advertisement.Advertisement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveExpired", ctx, limit)
+	ret0, _ := ret[0].([]advertisement.Advertisement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveExpired indicates an expected call of ArchiveExpired.
+func (mr *MockRepositoryInterfaceMockRecorder) ArchiveExpired(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveExpired", reflect.TypeOf((*MockRepositoryInterface)(nil).ArchiveExpired), ctx, limit)
+}
+
+// CancelSchedule mocks base method.
+func (m *MockRepositoryInterface) CancelSchedule(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelSchedule", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelSchedule indicates an expected call of CancelSchedule.
+func (mr *MockRepositoryInterfaceMockRecorder) CancelSchedule(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSchedule", reflect.TypeOf((*MockRepositoryInterface)(nil).CancelSchedule), ctx, id)
+}
+
+// ClaimExpiryReminders mocks base method.
+func (m *MockRepositoryInterface) ClaimExpiryReminders(ctx context.Context, expiresBefore time.Time, limit int) ([]advertisement.ExpiryReminder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimExpiryReminders", ctx, expiresBefore, limit)
+	ret0, _ := ret[0].([]advertisement.ExpiryReminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimExpiryReminders indicates an expected call of ClaimExpiryReminders.
+func (mr *MockRepositoryInterfaceMockRecorder) ClaimExpiryReminders(ctx, expiresBefore, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimExpiryReminders", reflect.TypeOf((*MockRepositoryInterface)(nil).ClaimExpiryReminders), ctx, expiresBefore, limit)
+}
+
 // Create mocks base method.
 func (m *MockRepositoryInterface) Create(ctx context.Context, ad *advertisement.Advertisement) (*advertisement.Advertisement, error) {
 	m.ctrl.T.Helper()
@@ -55,6 +103,36 @@ func (mr *MockRepositoryInterfaceMockRecorder) Create(ctx, ad any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepositoryInterface)(nil).Create), ctx, ad)
 }
 
+// ExportBatch mocks base method.
+func (m *MockRepositoryInterface) ExportBatch(ctx context.Context, filter *advertisement.ExportFilter, after *advertisement.Advertisement, limit int) ([]advertisement.Advertisement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportBatch", ctx, filter, after, limit)
+	ret0, _ := ret[0].([]advertisement.Advertisement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportBatch indicates an expected call of ExportBatch.
+func (mr *MockRepositoryInterfaceMockRecorder) ExportBatch(ctx, filter, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportBatch", reflect.TypeOf((*MockRepositoryInterface)(nil).ExportBatch), ctx, filter, after, limit)
+}
+
+// FindBySKU mocks base method.
+func (m *MockRepositoryInterface) FindBySKU(ctx context.Context, authorID uuid.UUID, sku string) (*uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBySKU", ctx, authorID, sku)
+	ret0, _ := ret[0].(*uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBySKU indicates an expected call of FindBySKU.
+func (mr *MockRepositoryInterfaceMockRecorder) FindBySKU(ctx, authorID, sku any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBySKU", reflect.TypeOf((*MockRepositoryInterface)(nil).FindBySKU), ctx, authorID, sku)
+}
+
 // GetAdvertisementsList mocks base method.
 func (m *MockRepositoryInterface) GetAdvertisementsList(ctx context.Context, params *advertisement.AdvertisementListParams) ([]advertisement.AdvertisementList, error) {
 	m.ctrl.T.Helper()
@@ -69,3 +147,255 @@ func (mr *MockRepositoryInterfaceMockRecorder) GetAdvertisementsList(ctx, params
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdvertisementsList", reflect.TypeOf((*MockRepositoryInterface)(nil).GetAdvertisementsList), ctx, params)
 }
+
+// GetByID mocks base method.
+func (m *MockRepositoryInterface) GetByID(ctx context.Context, id uuid.UUID, userID *uuid.UUID) (*advertisement.AdvertisementList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id, userID)
+	ret0, _ := ret[0].(*advertisement.AdvertisementList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockRepositoryInterfaceMockRecorder) GetByID(ctx, id, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRepositoryInterface)(nil).GetByID), ctx, id, userID)
+}
+
+// ListScheduled mocks base method.
+func (m *MockRepositoryInterface) ListScheduled(ctx context.Context, authorID uuid.UUID) ([]advertisement.AdvertisementList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduled", ctx, authorID)
+	ret0, _ := ret[0].([]advertisement.AdvertisementList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduled indicates an expected call of ListScheduled.
+func (mr *MockRepositoryInterfaceMockRecorder) ListScheduled(ctx, authorID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduled", reflect.TypeOf((*MockRepositoryInterface)(nil).ListScheduled), ctx, authorID)
+}
+
+// PublishDue mocks base method.
+func (m *MockRepositoryInterface) PublishDue(ctx context.Context, limit int) ([]advertisement.Advertisement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishDue", ctx, limit)
+	ret0, _ := ret[0].([]advertisement.Advertisement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishDue indicates an expected call of PublishDue.
+func (mr *MockRepositoryInterfaceMockRecorder) PublishDue(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishDue", reflect.TypeOf((*MockRepositoryInterface)(nil).PublishDue), ctx, limit)
+}
+
+// Renew mocks base method.
+func (m *MockRepositoryInterface) Renew(ctx context.Context, id uuid.UUID, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Renew", ctx, id, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Renew indicates an expected call of Renew.
+func (mr *MockRepositoryInterfaceMockRecorder) Renew(ctx, id, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Renew", reflect.TypeOf((*MockRepositoryInterface)(nil).Renew), ctx, id, expiresAt)
+}
+
+// Schedule mocks base method.
+func (m *MockRepositoryInterface) Schedule(ctx context.Context, id uuid.UUID, publishAt, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Schedule", ctx, id, publishAt, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Schedule indicates an expected call of Schedule.
+func (mr *MockRepositoryInterfaceMockRecorder) Schedule(ctx, id, publishAt, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Schedule", reflect.TypeOf((*MockRepositoryInterface)(nil).Schedule), ctx, id, publishAt, expiresAt)
+}
+
+// Update mocks base method.
+func (m *MockRepositoryInterface) Update(ctx context.Context, ad *advertisement.AdvertisementList, unmodifiedSince *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, ad, unmodifiedSince)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRepositoryInterfaceMockRecorder) Update(ctx, ad, unmodifiedSince any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepositoryInterface)(nil).Update), ctx, ad, unmodifiedSince)
+}
+
+// MockImportStore is a mock of ImportStore interface.
+type MockImportStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockImportStoreMockRecorder is the mock recorder for MockImportStore.
+type MockImportStoreMockRecorder struct {
+	mock *MockImportStore
+}
+
+// NewMockImportStore creates a new mock instance.
+func NewMockImportStore(ctrl *gomock.Controller) *MockImportStore {
+	mock := &MockImportStore{ctrl: ctrl}
+	mock.recorder = &MockImportStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImportStore) EXPECT() *MockImportStoreMockRecorder {
+	return m.recorder
+}
+
+// ClaimImport mocks base method.
+func (m *MockImportStore) ClaimImport(ctx context.Context, lease time.Duration) (*advertisement.ImportJob, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimImport", ctx, lease)
+	ret0, _ := ret[0].(*advertisement.ImportJob)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ClaimImport indicates an expected call of ClaimImport.
+func (mr *MockImportStoreMockRecorder) ClaimImport(ctx, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimImport", reflect.TypeOf((*MockImportStore)(nil).ClaimImport), ctx, lease)
+}
+
+// CreateImport mocks base method.
+func (m *MockImportStore) CreateImport(ctx context.Context, job *advertisement.ImportJob, payload []byte) (*advertisement.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateImport", ctx, job, payload)
+	ret0, _ := ret[0].(*advertisement.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateImport indicates an expected call of CreateImport.
+func (mr *MockImportStoreMockRecorder) CreateImport(ctx, job, payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImport", reflect.TypeOf((*MockImportStore)(nil).CreateImport), ctx, job, payload)
+}
+
+// GetImport mocks base method.
+func (m *MockImportStore) GetImport(ctx context.Context, id uuid.UUID) (*advertisement.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImport", ctx, id)
+	ret0, _ := ret[0].(*advertisement.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImport indicates an expected call of GetImport.
+func (mr *MockImportStoreMockRecorder) GetImport(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImport", reflect.TypeOf((*MockImportStore)(nil).GetImport), ctx, id)
+}
+
+// SaveImportProgress mocks base method.
+func (m *MockImportStore) SaveImportProgress(ctx context.Context, job *advertisement.ImportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveImportProgress", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveImportProgress indicates an expected call of SaveImportProgress.
+func (mr *MockImportStoreMockRecorder) SaveImportProgress(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveImportProgress", reflect.TypeOf((*MockImportStore)(nil).SaveImportProgress), ctx, job)
+}
+
+// MockTransactor is a mock of Transactor interface.
+type MockTransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactorMockRecorder
+	isgomock struct{}
+}
+
+// MockTransactorMockRecorder is the mock recorder for MockTransactor.
+type MockTransactorMockRecorder struct {
+	mock *MockTransactor
+}
+
+// NewMockTransactor creates a new mock instance.
+func NewMockTransactor(ctrl *gomock.Controller) *MockTransactor {
+	mock := &MockTransactor{ctrl: ctrl}
+	mock.recorder = &MockTransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactor) EXPECT() *MockTransactorMockRecorder {
+	return m.recorder
+}
+
+// WithinTx mocks base method.
+func (m *MockTransactor) WithinTx(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTx indicates an expected call of WithinTx.
+func (mr *MockTransactorMockRecorder) WithinTx(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTx", reflect.TypeOf((*MockTransactor)(nil).WithinTx), ctx, fn)
+}
+
+// MockEventRecorder is a mock of EventRecorder interface.
+type MockEventRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventRecorderMockRecorder
+	isgomock struct{}
+}
+
+// MockEventRecorderMockRecorder is the mock recorder for MockEventRecorder.
+type MockEventRecorderMockRecorder struct {
+	mock *MockEventRecorder
+}
+
+// NewMockEventRecorder creates a new mock instance.
+func NewMockEventRecorder(ctrl *gomock.Controller) *MockEventRecorder {
+	mock := &MockEventRecorder{ctrl: ctrl}
+	mock.recorder = &MockEventRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventRecorder) EXPECT() *MockEventRecorderMockRecorder {
+	return m.recorder
+}
+
+// Record mocks base method.
+func (m *MockEventRecorder) Record(ctx context.Context, events ...outbox.Event) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Record", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockEventRecorderMockRecorder) Record(ctx any, events ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockEventRecorder)(nil).Record), varargs...)
+}
@@ -12,8 +12,10 @@ package mockad
 import (
 	context "context"
 	advertisement "marketplace-api/internal/advertisement"
+	http "net/http"
 	reflect "reflect"
 
+	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -21,6 +23,7 @@ import (
 type MockServiceInterface struct {
 	ctrl     *gomock.Controller
 	recorder *MockServiceInterfaceMockRecorder
+	isgomock struct{}
 }
 
 // MockServiceInterfaceMockRecorder is the mock recorder for MockServiceInterface.
@@ -40,6 +43,21 @@ func (m *MockServiceInterface) EXPECT() *MockServiceInterfaceMockRecorder {
 	return m.recorder
 }
 
+// CancelSchedule mocks base method.
+func (m *MockServiceInterface) CancelSchedule(ctx context.Context, input *advertisement.CancelScheduleInput) (*advertisement.AdvertisementList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelSchedule", ctx, input)
+	ret0, _ := ret[0].(*advertisement.AdvertisementList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelSchedule indicates an expected call of CancelSchedule.
+func (mr *MockServiceInterfaceMockRecorder) CancelSchedule(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSchedule", reflect.TypeOf((*MockServiceInterface)(nil).CancelSchedule), ctx, input)
+}
+
 // Create mocks base method.
 func (m *MockServiceInterface) Create(ctx context.Context, input *advertisement.CreateAdvertisementInput) (*advertisement.Advertisement, error) {
 	m.ctrl.T.Helper()
@@ -55,6 +73,21 @@ func (mr *MockServiceInterfaceMockRecorder) Create(ctx, input any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockServiceInterface)(nil).Create), ctx, input)
 }
 
+// GetAd mocks base method.
+func (m *MockServiceInterface) GetAd(ctx context.Context, id uuid.UUID, userID *uuid.UUID) (*advertisement.AdvertisementList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAd", ctx, id, userID)
+	ret0, _ := ret[0].(*advertisement.AdvertisementList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAd indicates an expected call of GetAd.
+func (mr *MockServiceInterfaceMockRecorder) GetAd(ctx, id, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAd", reflect.TypeOf((*MockServiceInterface)(nil).GetAd), ctx, id, userID)
+}
+
 // ListAd mocks base method.
 func (m *MockServiceInterface) ListAd(ctx context.Context, params *advertisement.AdvertisementListParams) (*[]advertisement.AdvertisementList, error) {
 	m.ctrl.T.Helper()
@@ -69,3 +102,244 @@ func (mr *MockServiceInterfaceMockRecorder) ListAd(ctx, params any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAd", reflect.TypeOf((*MockServiceInterface)(nil).ListAd), ctx, params)
 }
+
+// ListScheduled mocks base method.
+func (m *MockServiceInterface) ListScheduled(ctx context.Context, userID uuid.UUID) ([]advertisement.AdvertisementList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduled", ctx, userID)
+	ret0, _ := ret[0].([]advertisement.AdvertisementList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduled indicates an expected call of ListScheduled.
+func (mr *MockServiceInterfaceMockRecorder) ListScheduled(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduled", reflect.TypeOf((*MockServiceInterface)(nil).ListScheduled), ctx, userID)
+}
+
+// Renew mocks base method.
+func (m *MockServiceInterface) Renew(ctx context.Context, input *advertisement.RenewAdvertisementInput) (*advertisement.AdvertisementList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Renew", ctx, input)
+	ret0, _ := ret[0].(*advertisement.AdvertisementList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Renew indicates an expected call of Renew.
+func (mr *MockServiceInterfaceMockRecorder) Renew(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Renew", reflect.TypeOf((*MockServiceInterface)(nil).Renew), ctx, input)
+}
+
+// Reschedule mocks base method.
+func (m *MockServiceInterface) Reschedule(ctx context.Context, input *advertisement.ScheduleAdvertisementInput) (*advertisement.AdvertisementList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reschedule", ctx, input)
+	ret0, _ := ret[0].(*advertisement.AdvertisementList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reschedule indicates an expected call of Reschedule.
+func (mr *MockServiceInterfaceMockRecorder) Reschedule(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reschedule", reflect.TypeOf((*MockServiceInterface)(nil).Reschedule), ctx, input)
+}
+
+// Update mocks base method.
+func (m *MockServiceInterface) Update(ctx context.Context, input *advertisement.UpdateAdvertisementInput) (*advertisement.AdvertisementList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, input)
+	ret0, _ := ret[0].(*advertisement.AdvertisementList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockServiceInterfaceMockRecorder) Update(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockServiceInterface)(nil).Update), ctx, input)
+}
+
+// MockViewRecorder is a mock of ViewRecorder interface.
+type MockViewRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockViewRecorderMockRecorder
+	isgomock struct{}
+}
+
+// MockViewRecorderMockRecorder is the mock recorder for MockViewRecorder.
+type MockViewRecorderMockRecorder struct {
+	mock *MockViewRecorder
+}
+
+// NewMockViewRecorder creates a new mock instance.
+func NewMockViewRecorder(ctrl *gomock.Controller) *MockViewRecorder {
+	mock := &MockViewRecorder{ctrl: ctrl}
+	mock.recorder = &MockViewRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockViewRecorder) EXPECT() *MockViewRecorderMockRecorder {
+	return m.recorder
+}
+
+// RecordView mocks base method.
+func (m *MockViewRecorder) RecordView(ctx context.Context, advertisementID uuid.UUID, viewer string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordView", ctx, advertisementID, viewer)
+}
+
+// RecordView indicates an expected call of RecordView.
+func (mr *MockViewRecorderMockRecorder) RecordView(ctx, advertisementID, viewer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordView", reflect.TypeOf((*MockViewRecorder)(nil).RecordView), ctx, advertisementID, viewer)
+}
+
+// MockClientIPResolver is a mock of ClientIPResolver interface.
+type MockClientIPResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientIPResolverMockRecorder
+	isgomock struct{}
+}
+
+// MockClientIPResolverMockRecorder is the mock recorder for MockClientIPResolver.
+type MockClientIPResolverMockRecorder struct {
+	mock *MockClientIPResolver
+}
+
+// NewMockClientIPResolver creates a new mock instance.
+func NewMockClientIPResolver(ctrl *gomock.Controller) *MockClientIPResolver {
+	mock := &MockClientIPResolver{ctrl: ctrl}
+	mock.recorder = &MockClientIPResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClientIPResolver) EXPECT() *MockClientIPResolverMockRecorder {
+	return m.recorder
+}
+
+// ClientIP mocks base method.
+func (m *MockClientIPResolver) ClientIP(r *http.Request) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientIP", r)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientIP indicates an expected call of ClientIP.
+func (mr *MockClientIPResolverMockRecorder) ClientIP(r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientIP", reflect.TypeOf((*MockClientIPResolver)(nil).ClientIP), r)
+}
+
+// MockImporterInterface is a mock of ImporterInterface interface.
+type MockImporterInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockImporterInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockImporterInterfaceMockRecorder is the mock recorder for MockImporterInterface.
+type MockImporterInterfaceMockRecorder struct {
+	mock *MockImporterInterface
+}
+
+// NewMockImporterInterface creates a new mock instance.
+func NewMockImporterInterface(ctrl *gomock.Controller) *MockImporterInterface {
+	mock := &MockImporterInterface{ctrl: ctrl}
+	mock.recorder = &MockImporterInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImporterInterface) EXPECT() *MockImporterInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockImporterInterface) Get(ctx context.Context, userID, id uuid.UUID) (*advertisement.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID, id)
+	ret0, _ := ret[0].(*advertisement.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockImporterInterfaceMockRecorder) Get(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockImporterInterface)(nil).Get), ctx, userID, id)
+}
+
+// Start mocks base method.
+func (m *MockImporterInterface) Start(ctx context.Context, input *advertisement.ImportInput) (*advertisement.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", ctx, input)
+	ret0, _ := ret[0].(*advertisement.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockImporterInterfaceMockRecorder) Start(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockImporterInterface)(nil).Start), ctx, input)
+}
+
+// MockExporterInterface is a mock of ExporterInterface interface.
+type MockExporterInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockExporterInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockExporterInterfaceMockRecorder is the mock recorder for MockExporterInterface.
+type MockExporterInterfaceMockRecorder struct {
+	mock *MockExporterInterface
+}
+
+// NewMockExporterInterface creates a new mock instance.
+func NewMockExporterInterface(ctrl *gomock.Controller) *MockExporterInterface {
+	mock := &MockExporterInterface{ctrl: ctrl}
+	mock.recorder = &MockExporterInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExporterInterface) EXPECT() *MockExporterInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *MockExporterInterface) Export(ctx context.Context, filter *advertisement.ExportFilter, limit int, fn func(*advertisement.Advertisement) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, filter, limit, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockExporterInterfaceMockRecorder) Export(ctx, filter, limit, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockExporterInterface)(nil).Export), ctx, filter, limit, fn)
+}
+
+// ListAd mocks base method.
+func (m *MockExporterInterface) ListAd(ctx context.Context, params *advertisement.AdvertisementListParams) (*[]advertisement.AdvertisementList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAd", ctx, params)
+	ret0, _ := ret[0].(*[]advertisement.AdvertisementList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAd indicates an expected call of ListAd.
+func (mr *MockExporterInterfaceMockRecorder) ListAd(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAd", reflect.TypeOf((*MockExporterInterface)(nil).ListAd), ctx, params)
+}
@@ -6,22 +6,70 @@ import (
 	"github.com/google/uuid"
 )
 
+// Типы объявлений
+const (
+	ListingTypeFixed   = "fixed"   // продажа по фиксированной цене
+	ListingTypeAuction = "auction" // аукцион, PriceKopecks - стартовая цена
+)
+
+// Статусы объявления
+const (
+	StatusActive    = "active"    // опубликовано
+	StatusArchived  = "archived"  // срок размещения истёк
+	StatusScheduled = "scheduled" // ожидает публикации в publish_at
+	StatusDraft     = "draft"     // запланированная публикация отменена автором
+	StatusClosed    = "closed"    // торги по объявлению-аукциону завершены, продлить нельзя
+)
+
 type Advertisement struct {
-	ID           uuid.UUID `json:"id"`
-	Title        string    `json:"title"`
-	Description  string    `json:"description"`
-	ImageURL     string    `json:"image_url"`
-	PriceKopecks int       `json:"price_kopecks"` //В копейках
-	AuthorID     uuid.UUID `json:"author_id"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID           uuid.UUID     `json:"id"`
+	Title        string        `json:"title"`
+	Description  string        `json:"description"`
+	ImageURL     string        `json:"image_url"`
+	PriceKopecks int           `json:"price_kopecks"` //В копейках
+	ListingType  string        `json:"listing_type"`
+	Auction      *AuctionTerms `json:"auction,omitempty"`
+	Category     string        `json:"category,omitempty"`
+	ExternalSKU  string        `json:"external_sku,omitempty"`
+	Status       string        `json:"status"`
+	AuthorID     uuid.UUID     `json:"author_id"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+	PublishAt    *time.Time    `json:"publish_at,omitempty"`
+	ExpiresAt    time.Time     `json:"expires_at"`
+}
+
+// AuctionTerms - условия аукциона, задаются при создании объявления с типом auction
+type AuctionTerms struct {
+	EndsAt                 time.Time `json:"ends_at"`                         // время окончания торгов
+	MinBidIncrementKopecks int       `json:"min_bid_increment_kopecks"`       // минимальный шаг ставки
+	BuyNowPriceKopecks     *int      `json:"buy_now_price_kopecks,omitempty"` // цена "купить сейчас"
+	ReservePriceKopecks    *int      `json:"reserve_price_kopecks,omitempty"` // резервная (минимальная) цена продажи
 }
 
 type CreateAdvertisementInput struct {
-	AuthorID     uuid.UUID `swaggerignore:"true"`
-	Title        string    `json:"title"`
-	Description  string    `json:"description"`
-	ImageURL     string    `json:"image_url"`
-	PriceKopecks int       `json:"price_kopecks"`
+	AuthorID     uuid.UUID     `swaggerignore:"true"`
+	Title        string        `json:"title"`
+	Description  string        `json:"description"`
+	ImageURL     string        `json:"image_url"`
+	PriceKopecks int           `json:"price_kopecks"`
+	ListingType  string        `json:"listing_type"`           // "fixed" (по умолчанию) или "auction"
+	Auction      *AuctionTerms `json:"auction,omitempty"`      // обязательно для listing_type = "auction"
+	Category     string        `json:"category"`               // категория, определяет срок размещения
+	PublishAt    *time.Time    `json:"publish_at,omitempty"`   // отложенная публикация, по умолчанию - сразу
+	ExternalSKU  string        `json:"external_sku,omitempty"` // артикул продавца, уникален среди его объявлений
+}
+
+// UpdateAdvertisementInput - изменение объявления автором, nil - поле не меняется
+type UpdateAdvertisementInput struct {
+	AdvertisementID uuid.UUID  `swaggerignore:"true"`
+	UserID          uuid.UUID  `swaggerignore:"true"`
+	UnmodifiedSince *time.Time `swaggerignore:"true"` // updated_at версии, которую видел клиент (If-Match)
+	Title           *string    `json:"title,omitempty"`
+	Description     *string    `json:"description,omitempty"`
+	ImageURL        *string    `json:"image_url,omitempty"`
+	PriceKopecks    *int       `json:"price_kopecks,omitempty"` // у аукциона стартовая цена не меняется
+	Category        *string    `json:"category,omitempty"`
 }
 
 type AdvertisementListParams struct {
@@ -35,10 +83,42 @@ type AdvertisementListParams struct {
 }
 
 type AdvertisementList struct {
-	Title        string  `json:"title"`
-	Description  string  `json:"description"`
-	ImageURL     string  `json:"image_url"`
-	PriceKopecks float64 `json:"price_kopecks"`
-	AuthorLogin  string  `json:"author_login"`
-	IsOwner      *bool   `json:"is_owner,omitempty"` // факт принадлежности объявления авторизованному пользователю
+	ID            uuid.UUID  `json:"id"`
+	Title         string     `json:"title"`
+	Description   string     `json:"description"`
+	ImageURL      string     `json:"image_url"`
+	PriceKopecks  float64    `json:"price_kopecks"`
+	ListingType   string     `json:"listing_type"`
+	Category      string     `json:"category,omitempty"`
+	Status        string     `json:"status"`
+	AuthorLogin   string     `json:"author_login"`
+	IsOwner       *bool      `json:"is_owner,omitempty"` // факт принадлежности объявления авторизованному пользователю
+	PublishAt     *time.Time `json:"publish_at,omitempty"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	IsHighlighted bool       `json:"is_highlighted"` // оплачено выделение объявления
+}
+
+type ScheduleAdvertisementInput struct {
+	AdvertisementID uuid.UUID `json:"advertisement_id"`
+	PublishAt       time.Time `json:"publish_at"`
+	UserID          uuid.UUID `swaggerignore:"true"`
+}
+
+type CancelScheduleInput struct {
+	AdvertisementID uuid.UUID `json:"advertisement_id"`
+	UserID          uuid.UUID `swaggerignore:"true"`
+}
+
+type RenewAdvertisementInput struct {
+	AdvertisementID uuid.UUID `json:"advertisement_id"`
+	UserID          uuid.UUID `swaggerignore:"true"`
+}
+
+// ExpiryReminder - данные для напоминания автору о скором окончании срока размещения
+type ExpiryReminder struct {
+	AdvertisementID uuid.UUID
+	Title           string
+	AuthorID        uuid.UUID
+	ExpiresAt       time.Time
 }
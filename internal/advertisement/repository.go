@@ -2,9 +2,16 @@ package advertisement
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"marketplace-api/internal/db"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -16,17 +23,46 @@ func NewAdRepository(pool *pgxpool.Pool) *Repository {
 	return &Repository{pool: pool}
 }
 
-// Create - создаёт объявление
+// Create - создаёт объявление (для аукциона вместе с условиями торгов в одной транзакции)
 func (r *Repository) Create(ctx context.Context, ad *Advertisement) (*Advertisement, error) {
+	tx, err := db.Conn(ctx, r.pool).Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
-		INSERT INTO advertisements (title, description, image_url, price_kopecks, author_id)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, created_at
+		INSERT INTO advertisements (title, description, image_url, price_kopecks, listing_type, category, status,
+			author_id, publish_at, published_at, expires_at, external_sku)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, CASE WHEN $7 = 'active' THEN now() END, $10, NULLIF($11, ''))
+		RETURNING id, created_at, updated_at
 	`
-	err := r.pool.QueryRow(ctx, query, ad.Title, ad.Description, ad.ImageURL, ad.PriceKopecks, ad.AuthorID).Scan(&ad.ID, &ad.CreatedAt)
+	err = tx.QueryRow(ctx, query, ad.Title, ad.Description, ad.ImageURL, ad.PriceKopecks, ad.ListingType, ad.Category,
+		ad.Status, ad.AuthorID, ad.PublishAt, ad.ExpiresAt, ad.ExternalSKU).Scan(&ad.ID, &ad.CreatedAt, &ad.UpdatedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "idx_advertisements_author_external_sku" {
+		return nil, ErrSKUTaken
+	}
 	if err != nil {
 		return nil, err
 	}
+
+	if ad.ListingType == ListingTypeAuction && ad.Auction != nil {
+		auctionQuery := `
+			INSERT INTO auctions (advertisement_id, ends_at, min_bid_increment_kopecks, buy_now_price_kopecks,
+				reserve_price_kopecks, current_price_kopecks)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`
+		_, err = tx.Exec(ctx, auctionQuery, ad.ID, ad.Auction.EndsAt, ad.Auction.MinBidIncrementKopecks,
+			ad.Auction.BuyNowPriceKopecks, ad.Auction.ReservePriceKopecks, ad.PriceKopecks)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
 	return ad, nil
 }
 
@@ -40,9 +76,9 @@ func (r *Repository) GetAdvertisementsList(ctx context.Context, params *Advertis
 	case "price":
 		orderBy = "a.price_kopecks"
 	case "created_at":
-		orderBy = "a.created_at"
+		orderBy = "a.published_at" // отложенные объявления попадают в ленту как новые в момент публикации
 	default:
-		orderBy = "a.created_at" // значение по умолчанию
+		orderBy = "a.published_at" // значение по умолчанию
 	}
 
 	//Направление сортировки
@@ -51,26 +87,42 @@ func (r *Repository) GetAdvertisementsList(ctx context.Context, params *Advertis
 		sortDirection = "ASC"
 	}
 
+	// В сортировке по умолчанию (сначала новые) выделенные объявления идут первыми,
+	// а поднятые считаются опубликованными в момент поднятия. Сортировка по цене не меняется
+	orderClause := orderBy + " " + sortDirection
+	if orderBy == "a.published_at" && sortDirection == "DESC" {
+		orderClause = "(a.highlighted_until > now()) IS TRUE DESC, GREATEST(a.bumped_at, a.published_at) DESC"
+	}
+
 	query := fmt.Sprintf(`
 			SELECT 
+				a.id,
 				a.title,
 				a.description,
 				a.image_url,
 				a.price_kopecks,
+				a.listing_type,
+				a.category,
+				a.status,
 				u.login,
 				CASE
 					WHEN $5::uuid IS NULL THEN NULL
 					WHEN a.author_id = $5 THEN true
 					ELSE false
-				END AS is_owner
+				END AS is_owner,
+				a.publish_at,
+				a.expires_at,
+				a.updated_at,
+				(a.highlighted_until > now()) IS TRUE AS is_highlighted
 			FROM advertisements a
 			JOIN users u ON a.author_id = u.id
-			WHERE ($3 = 0 OR a.price_kopecks >= $3)
+			WHERE a.status = 'active' AND a.expires_at > now()
+			  AND ($3 = 0 OR a.price_kopecks >= $3)
 			  AND ($4 = 0 OR a.price_kopecks <= $4)
-			ORDER BY %s %s
-			LIMIT $1 OFFSET $2`, orderBy, sortDirection)
+			ORDER BY %s
+			LIMIT $1 OFFSET $2`, orderClause)
 
-	rows, err := r.pool.Query(ctx, query, params.Limit, offset, params.MinPriceKopecks, params.MaxPriceKopecks, params.UserID)
+	rows, err := db.Conn(ctx, r.pool).Query(ctx, query, params.Limit, offset, params.MinPriceKopecks, params.MaxPriceKopecks, params.UserID)
 
 	if err != nil {
 		return nil, err
@@ -82,7 +134,8 @@ func (r *Repository) GetAdvertisementsList(ctx context.Context, params *Advertis
 	// Если пользователь авторизован
 	for rows.Next() {
 		var ad AdvertisementList
-		err := rows.Scan(&ad.Title, &ad.Description, &ad.ImageURL, &ad.PriceKopecks, &ad.AuthorLogin, &ad.IsOwner)
+		err := rows.Scan(&ad.ID, &ad.Title, &ad.Description, &ad.ImageURL, &ad.PriceKopecks, &ad.ListingType, &ad.Category,
+			&ad.Status, &ad.AuthorLogin, &ad.IsOwner, &ad.PublishAt, &ad.ExpiresAt, &ad.UpdatedAt, &ad.IsHighlighted)
 		if err != nil {
 			return nil, err
 		}
@@ -91,3 +144,371 @@ func (r *Repository) GetAdvertisementsList(ctx context.Context, params *Advertis
 
 	return ads, nil
 }
+
+// GetByID - получение объявления по ID (или nil, если не найдено)
+func (r *Repository) GetByID(ctx context.Context, id uuid.UUID, userID *uuid.UUID) (*AdvertisementList, error) {
+	query := `
+		SELECT
+			a.id,
+			a.title,
+			a.description,
+			a.image_url,
+			a.price_kopecks,
+			a.listing_type,
+			a.category,
+			a.status,
+			u.login,
+			CASE
+				WHEN $2::uuid IS NULL THEN NULL
+				WHEN a.author_id = $2 THEN true
+				ELSE false
+			END AS is_owner,
+			a.publish_at,
+			a.expires_at,
+			a.updated_at,
+			(a.highlighted_until > now()) IS TRUE AS is_highlighted
+		FROM advertisements a
+		JOIN users u ON a.author_id = u.id
+		WHERE a.id = $1`
+
+	var ad AdvertisementList
+	err := db.Conn(ctx, r.pool).QueryRow(ctx, query, id, userID).
+		Scan(&ad.ID, &ad.Title, &ad.Description, &ad.ImageURL, &ad.PriceKopecks, &ad.ListingType, &ad.Category,
+			&ad.Status, &ad.AuthorLogin, &ad.IsOwner, &ad.PublishAt, &ad.ExpiresAt, &ad.UpdatedAt, &ad.IsHighlighted)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ad, nil
+}
+
+// FindBySKU - ID объявления автора с артикулом sku (или nil, если такого нет)
+func (r *Repository) FindBySKU(ctx context.Context, authorID uuid.UUID, sku string) (*uuid.UUID, error) {
+	var id uuid.UUID
+	err := db.Conn(ctx, r.pool).QueryRow(ctx, `SELECT id FROM advertisements WHERE author_id = $1 AND external_sku = $2`, authorID, sku).
+		Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// Update - сохраняет изменённые автором поля объявления. Если unmodifiedSince задан, изменение
+// применяется, только пока объявление не менялось с этого момента, иначе возвращается ErrModified
+func (r *Repository) Update(ctx context.Context, ad *AdvertisementList, unmodifiedSince *time.Time) error {
+	query := `
+		UPDATE advertisements
+		SET title = $2, description = $3, image_url = $4, price_kopecks = $5, category = $6
+		WHERE id = $1 AND ($7::timestamptz IS NULL OR updated_at = $7)`
+	tag, err := db.Conn(ctx, r.pool).Exec(ctx, query, ad.ID, ad.Title, ad.Description, ad.ImageURL, int(ad.PriceKopecks), ad.Category, unmodifiedSince)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrModified
+	}
+	return nil
+}
+
+// Renew - продлевает срок размещения и возвращает объявление в публикацию.
+// Объявление с завершёнными торгами не продлевается, возвращается ErrListingClosed
+func (r *Repository) Renew(ctx context.Context, id uuid.UUID, expiresAt time.Time) error {
+	query := `
+		UPDATE advertisements
+		SET status = 'active', expires_at = $2, expiry_reminder_sent_at = NULL
+		WHERE id = $1 AND status <> 'closed'`
+	tag, err := db.Conn(ctx, r.pool).Exec(ctx, query, id, expiresAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrListingClosed
+	}
+	return nil
+}
+
+// ArchiveExpired - снимает с публикации до limit объявлений с истёкшим сроком размещения
+// и возвращает их, объявления с незавершёнными торгами пропускаются
+func (r *Repository) ArchiveExpired(ctx context.Context, limit int) ([]Advertisement, error) {
+	query := `
+		UPDATE advertisements
+		SET status = 'archived'
+		WHERE id IN (
+			SELECT a.id
+			FROM advertisements a
+			WHERE a.status = 'active' AND a.expires_at <= now()
+			  AND NOT EXISTS (
+				SELECT 1 FROM auctions au WHERE au.advertisement_id = a.id AND au.status = 'open'
+			  )
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + advertisementColumns
+	rows, err := db.Conn(ctx, r.pool).Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanAdvertisements(rows)
+}
+
+// ClaimExpiryReminders - отмечает отправку напоминания для объявлений, истекающих до expiresBefore,
+// и возвращает их. Отметка ставится до отправки, поэтому напоминание не дублируется между экземплярами
+func (r *Repository) ClaimExpiryReminders(ctx context.Context, expiresBefore time.Time, limit int) ([]ExpiryReminder, error) {
+	query := `
+		UPDATE advertisements
+		SET expiry_reminder_sent_at = now()
+		WHERE id IN (
+			SELECT id
+			FROM advertisements
+			WHERE status = 'active' AND expiry_reminder_sent_at IS NULL
+			  AND expires_at > now() AND expires_at <= $1
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, title, author_id, expires_at`
+
+	rows, err := db.Conn(ctx, r.pool).Query(ctx, query, expiresBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reminders []ExpiryReminder
+	for rows.Next() {
+		var rem ExpiryReminder
+		if err := rows.Scan(&rem.AdvertisementID, &rem.Title, &rem.AuthorID, &rem.ExpiresAt); err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, rem)
+	}
+	return reminders, rows.Err()
+}
+
+// ListScheduled - объявления автора со статусами scheduled и draft в порядке публикации
+func (r *Repository) ListScheduled(ctx context.Context, authorID uuid.UUID) ([]AdvertisementList, error) {
+	query := `
+		SELECT
+			a.id,
+			a.title,
+			a.description,
+			a.image_url,
+			a.price_kopecks,
+			a.listing_type,
+			a.category,
+			a.status,
+			u.login,
+			true AS is_owner,
+			a.publish_at,
+			a.expires_at,
+			a.updated_at
+		FROM advertisements a
+		JOIN users u ON a.author_id = u.id
+		WHERE a.author_id = $1 AND a.status IN ('scheduled', 'draft')
+		ORDER BY a.publish_at NULLS LAST, a.created_at`
+
+	rows, err := db.Conn(ctx, r.pool).Query(ctx, query, authorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ads := []AdvertisementList{}
+	for rows.Next() {
+		var ad AdvertisementList
+		err := rows.Scan(&ad.ID, &ad.Title, &ad.Description, &ad.ImageURL, &ad.PriceKopecks, &ad.ListingType, &ad.Category,
+			&ad.Status, &ad.AuthorLogin, &ad.IsOwner, &ad.PublishAt, &ad.ExpiresAt, &ad.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		ads = append(ads, ad)
+	}
+	return ads, rows.Err()
+}
+
+// Schedule - назначает время публикации неопубликованному объявлению.
+// Если объявление успели опубликовать, возвращается ErrAlreadyPublished
+func (r *Repository) Schedule(ctx context.Context, id uuid.UUID, publishAt, expiresAt time.Time) error {
+	query := `
+		UPDATE advertisements
+		SET status = 'scheduled', publish_at = $2, expires_at = $3, expiry_reminder_sent_at = NULL
+		WHERE id = $1 AND status IN ('scheduled', 'draft')`
+	tag, err := db.Conn(ctx, r.pool).Exec(ctx, query, id, publishAt, expiresAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrAlreadyPublished
+	}
+	return nil
+}
+
+// CancelSchedule - переводит запланированное объявление в черновик
+func (r *Repository) CancelSchedule(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE advertisements
+		SET status = 'draft', publish_at = NULL
+		WHERE id = $1 AND status = 'scheduled'`
+	tag, err := db.Conn(ctx, r.pool).Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrAlreadyPublished
+	}
+	return nil
+}
+
+// PublishDue - публикует до limit объявлений, время публикации которых наступило, и возвращает их.
+// SKIP LOCKED позволяет нескольким экземплярам работать параллельно без двойной публикации
+func (r *Repository) PublishDue(ctx context.Context, limit int) ([]Advertisement, error) {
+	query := `
+		UPDATE advertisements
+		SET status = 'active', published_at = now()
+		WHERE id IN (
+			SELECT id
+			FROM advertisements
+			WHERE status = 'scheduled' AND publish_at <= now()
+			ORDER BY publish_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + advertisementColumns
+	rows, err := db.Conn(ctx, r.pool).Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanAdvertisements(rows)
+}
+
+const importColumns = `id, author_id, format, dry_run, status, total_rows, processed, created, updated, failed,
+	errors, error, created_at, started_at, finished_at`
+
+func scanImport(row pgx.Row, job *ImportJob, extra ...any) error {
+	var rowErrors []byte
+	dest := append([]any{&job.ID, &job.AuthorID, &job.Format, &job.DryRun, &job.Status, &job.TotalRows, &job.Processed,
+		&job.Created, &job.Updated, &job.Failed, &rowErrors, &job.Error, &job.CreatedAt, &job.StartedAt, &job.FinishedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return err
+	}
+	return json.Unmarshal(rowErrors, &job.Errors)
+}
+
+// CreateImport - сохраняет задание импорта вместе с файлом
+func (r *Repository) CreateImport(ctx context.Context, job *ImportJob, payload []byte) (*ImportJob, error) {
+	query := `
+		INSERT INTO advertisement_imports (author_id, format, dry_run, status, total_rows, payload)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`
+	err := db.Conn(ctx, r.pool).QueryRow(ctx, query, job.AuthorID, job.Format, job.DryRun, job.Status, job.TotalRows, payload).
+		Scan(&job.ID, &job.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// GetImport - задание импорта по ID (или nil, если не найдено)
+func (r *Repository) GetImport(ctx context.Context, id uuid.UUID) (*ImportJob, error) {
+	var job ImportJob
+	err := scanImport(db.Conn(ctx, r.pool).QueryRow(ctx, `SELECT `+importColumns+` FROM advertisement_imports WHERE id = $1`, id), &job)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ClaimImport - берёт в работу самое старое ожидающее задание или задание, прогресс которого
+// не сохранялся дольше lease (экземпляр, обрабатывавший его, остановился). nil - заданий нет
+func (r *Repository) ClaimImport(ctx context.Context, lease time.Duration) (*ImportJob, []byte, error) {
+	query := `
+		UPDATE advertisement_imports
+		SET status = 'running', started_at = COALESCE(started_at, now()), heartbeat_at = now()
+		WHERE id = (
+			SELECT id FROM advertisement_imports
+			WHERE status = 'pending'
+			   OR (status = 'running' AND heartbeat_at < now() - $1 * interval '1 millisecond')
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED)
+		RETURNING ` + importColumns + `, payload`
+
+	var job ImportJob
+	var payload []byte
+	err := scanImport(db.Conn(ctx, r.pool).QueryRow(ctx, query, lease.Milliseconds()), &job, &payload)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return &job, payload, nil
+}
+
+// SaveImportProgress - сохраняет счётчики и ошибки строк и продлевает аренду задания.
+// Файл завершённого задания больше не нужен и удаляется
+func (r *Repository) SaveImportProgress(ctx context.Context, job *ImportJob) error {
+	rowErrors, err := json.Marshal(job.Errors)
+	if err != nil {
+		return err
+	}
+	query := `
+		UPDATE advertisement_imports
+		SET status = $2, processed = $3, created = $4, updated = $5, failed = $6, errors = $7, error = $8,
+			finished_at = $9, heartbeat_at = now(),
+			payload = CASE WHEN $9::timestamptz IS NULL THEN payload END
+		WHERE id = $1`
+	_, err = db.Conn(ctx, r.pool).Exec(ctx, query, job.ID, job.Status, job.Processed, job.Created, job.Updated, job.Failed,
+		rowErrors, job.Error, job.FinishedAt)
+	return err
+}
+
+// ExportBatch - следующая пачка объявлений выгрузки от новых к старым, after - последнее
+// объявление предыдущей пачки (nil - с начала)
+func (r *Repository) ExportBatch(ctx context.Context, filter *ExportFilter, after *Advertisement, limit int) ([]Advertisement, error) {
+	var afterCreated *time.Time
+	var afterID *uuid.UUID
+	if after != nil {
+		afterCreated, afterID = &after.CreatedAt, &after.ID
+	}
+
+	query := `
+		SELECT ` + advertisementColumns + `
+		FROM advertisements
+		WHERE CASE WHEN $1::uuid IS NULL THEN status = 'active' AND expires_at > now() ELSE author_id = $1 END
+		  AND ($2::timestamptz IS NULL OR (created_at, id) < ($2, $3::uuid))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4`
+	rows, err := db.Conn(ctx, r.pool).Query(ctx, query, filter.AuthorID, afterCreated, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanAdvertisements(rows)
+}
+
+// advertisementColumns - колонки объявления для scanAdvertisements
+const advertisementColumns = `id, title, description, image_url, price_kopecks, listing_type, category,
+	COALESCE(external_sku, ''), status, author_id, created_at, updated_at, publish_at, expires_at`
+
+func scanAdvertisements(rows pgx.Rows) ([]Advertisement, error) {
+	defer rows.Close()
+
+	var ads []Advertisement
+	for rows.Next() {
+		var ad Advertisement
+		err := rows.Scan(&ad.ID, &ad.Title, &ad.Description, &ad.ImageURL, &ad.PriceKopecks, &ad.ListingType, &ad.Category,
+			&ad.ExternalSKU, &ad.Status, &ad.AuthorID, &ad.CreatedAt, &ad.UpdatedAt, &ad.PublishAt, &ad.ExpiresAt)
+		if err != nil {
+			return nil, err
+		}
+		ads = append(ads, ad)
+	}
+	return ads, rows.Err()
+}
@@ -3,31 +3,100 @@ package advertisement
 import (
 	"context"
 	"errors"
+	"fmt"
+	"marketplace-api/internal/metrics"
+	"marketplace-api/internal/outbox"
+	"marketplace-api/internal/tracing"
 	"net/url"
 	"regexp"
 	"strings"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 var (
 	allowedImageExt        = regexp.MustCompile(`(?i)\.(jpg|jpeg|png)$`)
 	allowedTitleСharacters = regexp.MustCompile(`^[a-zA-Zа-яА-Я0-9 ]+$`)
+	allowedCategory        = regexp.MustCompile(`^[a-z0-9-]{1,50}$`)
+	allowedSKU             = regexp.MustCompile(`^[A-Za-z0-9._/-]{1,64}$`)
+)
+
+var (
+	ErrAdNotFound       = errors.New("advertisement not found")
+	ErrNotOwner         = errors.New("only the author can manage the advertisement")
+	ErrAlreadyPublished = errors.New("advertisement is already published")
+	ErrNotPublishedYet  = errors.New("advertisement is not published yet")
+	ErrListingClosed    = errors.New("auction is closed: advertisement cannot be renewed")
+	ErrModified         = errors.New("advertisement was modified since it was last read")
+	ErrSKUTaken         = errors.New("advertisement with this external_sku already exists")
+	// ErrInvalidInput - входные данные не прошли проверку, подробности в тексте ошибки
+	ErrInvalidInput = errors.New("invalid input")
+)
+
+const (
+	minAuctionDuration = time.Hour
+	maxAuctionDuration = 30 * 24 * time.Hour
+	maxScheduleAhead   = 90 * 24 * time.Hour
 )
 
 type RepositoryInterface interface {
 	Create(ctx context.Context, ad *Advertisement) (*Advertisement, error)
 	GetAdvertisementsList(ctx context.Context, params *AdvertisementListParams) ([]AdvertisementList, error)
+	GetByID(ctx context.Context, id uuid.UUID, userID *uuid.UUID) (*AdvertisementList, error)
+	Update(ctx context.Context, ad *AdvertisementList, unmodifiedSince *time.Time) error
+	Renew(ctx context.Context, id uuid.UUID, expiresAt time.Time) error
+	ArchiveExpired(ctx context.Context, limit int) ([]Advertisement, error)
+	ClaimExpiryReminders(ctx context.Context, expiresBefore time.Time, limit int) ([]ExpiryReminder, error)
+	ListScheduled(ctx context.Context, authorID uuid.UUID) ([]AdvertisementList, error)
+	Schedule(ctx context.Context, id uuid.UUID, publishAt, expiresAt time.Time) error
+	CancelSchedule(ctx context.Context, id uuid.UUID) error
+	PublishDue(ctx context.Context, limit int) ([]Advertisement, error)
+	FindBySKU(ctx context.Context, authorID uuid.UUID, sku string) (*uuid.UUID, error)
+	ExportBatch(ctx context.Context, filter *ExportFilter, after *Advertisement, limit int) ([]Advertisement, error)
+}
+
+// ImportStore - хранение заданий импорта вместе с загруженным файлом
+type ImportStore interface {
+	CreateImport(ctx context.Context, job *ImportJob, payload []byte) (*ImportJob, error)
+	GetImport(ctx context.Context, id uuid.UUID) (*ImportJob, error)
+	ClaimImport(ctx context.Context, lease time.Duration) (*ImportJob, []byte, error)
+	SaveImportProgress(ctx context.Context, job *ImportJob) error
+}
+
+// Transactor - выполнение нескольких обращений к репозиторию в одной транзакции
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// EventRecorder - сохранение событий в одной транзакции с изменением
+type EventRecorder interface {
+	Record(ctx context.Context, events ...outbox.Event) error
+}
+
+// Pagination - ограничения размера страницы списка объявлений
+type Pagination struct {
+	DefaultLimit int // если limit не передан
+	MaxLimit     int // больший limit урезается до этого значения
 }
 
 type Service struct {
-	repo RepositoryInterface
+	repo       RepositoryInterface
+	tx         Transactor
+	events     EventRecorder
+	lifetime   Lifetime
+	pagination Pagination
 }
 
-func NewAdService(repo RepositoryInterface) *Service {
-	return &Service{repo: repo}
+func NewAdService(repo RepositoryInterface, tx Transactor, events EventRecorder, lifetime Lifetime, pagination Pagination) *Service {
+	return &Service{repo: repo, tx: tx, events: events, lifetime: lifetime, pagination: pagination}
 }
 
 // Create - создание объявления
 func (s *Service) Create(ctx context.Context, input *CreateAdvertisementInput) (*Advertisement, error) {
+	ctx, span := tracing.Start(ctx, "advertisement.Service.Create")
+	defer span.End()
+
 	if err := s.validateCreateInput(input); err != nil {
 		return nil, err
 	}
@@ -37,35 +106,172 @@ func (s *Service) Create(ctx context.Context, input *CreateAdvertisementInput) (
 		Description:  input.Description,
 		ImageURL:     input.ImageURL,
 		PriceKopecks: input.PriceKopecks,
+		ListingType:  input.ListingType,
+		Auction:      input.Auction,
+		Category:     input.Category,
+		ExternalSKU:  input.ExternalSKU,
+		Status:       StatusActive,
 		AuthorID:     input.AuthorID,
+		ExpiresAt:    time.Now().Add(s.lifetime.For(input.Category)),
+	}
+	// Отложенная публикация: срок размещения отсчитывается от момента публикации
+	if input.PublishAt != nil {
+		ad.Status = StatusScheduled
+		ad.PublishAt = input.PublishAt
+		ad.ExpiresAt = input.PublishAt.Add(s.lifetime.For(input.Category))
+	}
+	// Объявление с аукционом не снимается с публикации до окончания торгов
+	if ad.Auction != nil && ad.Auction.EndsAt.After(ad.ExpiresAt) {
+		ad.ExpiresAt = ad.Auction.EndsAt
 	}
 
-	ad, err := s.repo.Create(ctx, ad)
+	// Объявление и событие о нём сохраняются вместе
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		created, err := s.repo.Create(ctx, ad)
+		if err != nil {
+			return err
+		}
+		ad = created
+		return s.record(ctx, EventCreated, advertisementEvent(ad))
+	})
 	if err != nil {
 		return nil, err
 	}
-	return ad, err
+	metrics.AdvertisementCreated()
+	return ad, nil
+}
+
+// UpsertBySKU - создаёт объявление или, если у автора уже есть объявление с тем же external_sku,
+// обновляет его содержимое: тип, условия торгов и время публикации задаются только при создании.
+// При dryRun ввод только проверяется. Возвращает true, если объявление обновлено (было бы обновлено)
+func (s *Service) UpsertBySKU(ctx context.Context, input *CreateAdvertisementInput, dryRun bool) (bool, error) {
+	ctx, span := tracing.Start(ctx, "advertisement.Service.UpsertBySKU")
+	defer span.End()
+
+	if err := s.validateCreateInput(input); err != nil {
+		return false, err
+	}
+
+	var existing *uuid.UUID
+	if input.ExternalSKU != "" {
+		var err error
+		if existing, err = s.repo.FindBySKU(ctx, input.AuthorID, input.ExternalSKU); err != nil {
+			return false, err
+		}
+	}
+	if dryRun {
+		return existing != nil, nil
+	}
+
+	if existing == nil {
+		_, err := s.Create(ctx, input)
+		return false, err
+	}
+	_, err := s.Update(ctx, &UpdateAdvertisementInput{
+		AdvertisementID: *existing,
+		UserID:          input.AuthorID,
+		Title:           &input.Title,
+		Description:     &input.Description,
+		ImageURL:        &input.ImageURL,
+		PriceKopecks:    &input.PriceKopecks,
+		Category:        &input.Category,
+	})
+	return true, err
 }
 
 // validateCreateInput проверяет корректность входных данных при создание объявления
 func (s *Service) validateCreateInput(input *CreateAdvertisementInput) error {
+	if err := validateContent(input); err != nil {
+		return err
+	}
+
+	switch input.ListingType {
+	case "", ListingTypeFixed:
+		input.ListingType = ListingTypeFixed
+		if input.Auction != nil {
+			return fmt.Errorf("%w: auction terms are allowed only for listing_type auction", ErrInvalidInput)
+		}
+	case ListingTypeAuction:
+		if input.PublishAt != nil {
+			return fmt.Errorf("%w: scheduled publishing is not available for auctions", ErrInvalidInput)
+		}
+		if err := validateAuctionTerms(input.PriceKopecks, input.Auction); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: invalid listing_type: must be fixed or auction", ErrInvalidInput)
+	}
+
+	if input.PublishAt != nil {
+		if err := validatePublishAt(*input.PublishAt); err != nil {
+			return err
+		}
+	}
+
+	input.ExternalSKU = strings.TrimSpace(input.ExternalSKU)
+	if input.ExternalSKU != "" && !allowedSKU.MatchString(input.ExternalSKU) {
+		return fmt.Errorf("%w: invalid external_sku: must be 1-64 characters (latin letters, numbers, . _ / -)", ErrInvalidInput)
+	}
+
+	return nil
+}
+
+// validateContent проверяет поля, которые автор задаёт при создании и может менять позже
+func validateContent(input *CreateAdvertisementInput) error {
 	title := strings.TrimSpace(input.Title)
 	if len(title) < 3 || len(title) > 100 {
-		return errors.New("title must be 1–100 characters")
+		return fmt.Errorf("%w: title must be 1–100 characters", ErrInvalidInput)
 	}
 	if !allowedTitleСharacters.MatchString(title) {
-		return errors.New("title must contain letters or numbers")
+		return fmt.Errorf("%w: title must contain letters or numbers", ErrInvalidInput)
 	}
 	if len(input.Description) < 1 || len(input.Description) > 1000 {
-		return errors.New("description must contain 1-1000 characters")
+		return fmt.Errorf("%w: description must contain 1-1000 characters", ErrInvalidInput)
 	}
 	if input.PriceKopecks <= 0 {
-		return errors.New("invalid price: must be higher than 0")
+		return fmt.Errorf("%w: invalid price: must be higher than 0", ErrInvalidInput)
 	}
 	if !isValidImageURL(input.ImageURL) {
-		return errors.New("invalid image URL: must start with http(s) and end with .jpg/.jpeg/.png")
+		return fmt.Errorf("%w: invalid image URL: must start with http(s) and end with .jpg/.jpeg/.png", ErrInvalidInput)
 	}
+	input.Category = strings.ToLower(strings.TrimSpace(input.Category))
+	if input.Category != "" && !allowedCategory.MatchString(input.Category) {
+		return fmt.Errorf("%w: invalid category: must be 1-50 characters (latin letters, numbers, hyphen)", ErrInvalidInput)
+	}
+	return nil
+}
 
+// validatePublishAt проверяет время отложенной публикации
+func validatePublishAt(publishAt time.Time) error {
+	untilPublish := time.Until(publishAt)
+	if untilPublish <= 0 || untilPublish > maxScheduleAhead {
+		return fmt.Errorf("%w: invalid publish time: must be in the future, no more than 90 days from now", ErrInvalidInput)
+	}
+	return nil
+}
+
+// validateAuctionTerms проверяет условия аукциона, startPrice - стартовая цена объявления
+func validateAuctionTerms(startPrice int, terms *AuctionTerms) error {
+	if terms == nil {
+		return fmt.Errorf("%w: auction terms are required for listing_type auction", ErrInvalidInput)
+	}
+	duration := time.Until(terms.EndsAt)
+	if duration < minAuctionDuration || duration > maxAuctionDuration {
+		return fmt.Errorf("%w: invalid auction end time: must be from 1 hour to 30 days from now", ErrInvalidInput)
+	}
+	if terms.MinBidIncrementKopecks <= 0 {
+		return fmt.Errorf("%w: invalid min bid increment: must be higher than 0", ErrInvalidInput)
+	}
+	if terms.BuyNowPriceKopecks != nil && *terms.BuyNowPriceKopecks <= startPrice {
+		return fmt.Errorf("%w: invalid buy now price: must be higher than the starting price", ErrInvalidInput)
+	}
+	if terms.ReservePriceKopecks != nil && *terms.ReservePriceKopecks < startPrice {
+		return fmt.Errorf("%w: invalid reserve price: must not be lower than the starting price", ErrInvalidInput)
+	}
+	if terms.BuyNowPriceKopecks != nil && terms.ReservePriceKopecks != nil &&
+		*terms.BuyNowPriceKopecks < *terms.ReservePriceKopecks {
+		return fmt.Errorf("%w: invalid buy now price: must not be lower than the reserve price", ErrInvalidInput)
+	}
 	return nil
 }
 
@@ -82,6 +288,9 @@ func isValidImageURL(rawURL string) bool {
 
 // ListAd - получение списка объявлений по фильтрам
 func (s *Service) ListAd(ctx context.Context, params *AdvertisementListParams) (*[]AdvertisementList, error) {
+	ctx, span := tracing.Start(ctx, "advertisement.Service.ListAd")
+	defer span.End()
+
 	//Валидация параметров
 	params, err := s.validateListAdParams(params)
 	if err != nil {
@@ -96,13 +305,322 @@ func (s *Service) ListAd(ctx context.Context, params *AdvertisementListParams) (
 	return &adList, nil
 }
 
+// GetAd - получение объявления по ID
+func (s *Service) GetAd(ctx context.Context, id uuid.UUID, userID *uuid.UUID) (*AdvertisementList, error) {
+	ctx, span := tracing.Start(ctx, "advertisement.Service.GetAd")
+	defer span.End()
+
+	ad, err := s.repo.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	// Снятые с публикации объявления видны только автору
+	if ad == nil || (!isActive(ad) && !isOwner(ad)) {
+		return nil, ErrAdNotFound
+	}
+	return ad, nil
+}
+
+// Update - изменение объявления автором. Срок размещения и статус не меняются
+func (s *Service) Update(ctx context.Context, input *UpdateAdvertisementInput) (*AdvertisementList, error) {
+	ctx, span := tracing.Start(ctx, "advertisement.Service.Update")
+	defer span.End()
+
+	// Проверка, запись, чтение нового состояния и событие - в одной транзакции
+	var ad *AdvertisementList
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		ad, err = s.update(ctx, input)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ad, nil
+}
+
+func (s *Service) update(ctx context.Context, input *UpdateAdvertisementInput) (*AdvertisementList, error) {
+	ad, err := s.repo.GetByID(ctx, input.AdvertisementID, &input.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if ad == nil {
+		return nil, ErrAdNotFound
+	}
+	if !isOwner(ad) {
+		return nil, ErrNotOwner
+	}
+	if input.UnmodifiedSince != nil && !ad.UpdatedAt.Equal(*input.UnmodifiedSince) {
+		return nil, ErrModified
+	}
+
+	content := CreateAdvertisementInput{
+		Title:        ad.Title,
+		Description:  ad.Description,
+		ImageURL:     ad.ImageURL,
+		PriceKopecks: int(ad.PriceKopecks),
+		Category:     ad.Category,
+	}
+	if input.Title != nil {
+		content.Title = *input.Title
+	}
+	if input.Description != nil {
+		content.Description = *input.Description
+	}
+	if input.ImageURL != nil {
+		content.ImageURL = *input.ImageURL
+	}
+	if input.PriceKopecks != nil {
+		if ad.ListingType == ListingTypeAuction && *input.PriceKopecks != content.PriceKopecks {
+			return nil, fmt.Errorf("%w: starting price of an auction cannot be changed", ErrInvalidInput)
+		}
+		content.PriceKopecks = *input.PriceKopecks
+	}
+	if input.Category != nil {
+		content.Category = *input.Category
+	}
+	if err := validateContent(&content); err != nil {
+		return nil, err
+	}
+
+	ad.Title = content.Title
+	ad.Description = content.Description
+	ad.ImageURL = content.ImageURL
+	ad.PriceKopecks = float64(content.PriceKopecks)
+	ad.Category = content.Category
+	if err := s.repo.Update(ctx, ad, &ad.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	// Перечитываем, чтобы вернуть новый updated_at
+	ad, err = s.repo.GetByID(ctx, ad.ID, &input.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if ad == nil {
+		return nil, ErrAdNotFound
+	}
+	if err := s.record(ctx, EventUpdated, updatedEvent(ad, input.UserID)); err != nil {
+		return nil, err
+	}
+	return ad, nil
+}
+
+// Renew - продление срока размещения объявления автором
+func (s *Service) Renew(ctx context.Context, input *RenewAdvertisementInput) (*AdvertisementList, error) {
+	ctx, span := tracing.Start(ctx, "advertisement.Service.Renew")
+	defer span.End()
+
+	// Продление и событие о нём сохраняются вместе
+	var ad *AdvertisementList
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		ad, err = s.repo.GetByID(ctx, input.AdvertisementID, &input.UserID)
+		if err != nil {
+			return err
+		}
+		if ad == nil {
+			return ErrAdNotFound
+		}
+		if !isOwner(ad) {
+			return ErrNotOwner
+		}
+		if isUnpublished(ad) {
+			return ErrNotPublishedYet
+		}
+		if ad.Status == StatusClosed {
+			return ErrListingClosed
+		}
+
+		expiresAt := time.Now().Add(s.lifetime.For(ad.Category))
+		if err := s.repo.Renew(ctx, ad.ID, expiresAt); err != nil {
+			return err
+		}
+
+		ad.Status = StatusActive
+		ad.ExpiresAt = expiresAt
+		return s.record(ctx, EventRenewed, updatedEvent(ad, input.UserID))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ad, nil
+}
+
+// ArchiveExpired - снятие с публикации объявлений с истёкшим сроком размещения.
+// Снятие и события о нём сохраняются в одной транзакции
+func (s *Service) ArchiveExpired(ctx context.Context, limit int) (int64, error) {
+	ctx, span := tracing.Start(ctx, "advertisement.Service.ArchiveExpired")
+	defer span.End()
+
+	var archived []Advertisement
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		archived, err = s.repo.ArchiveExpired(ctx, limit)
+		if err != nil {
+			return err
+		}
+		for i := range archived {
+			if err := s.record(ctx, EventExpired, advertisementEvent(&archived[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(archived)), nil
+}
+
+// ClaimExpiryReminders - выбор объявлений, срок которых истекает в ближайшие remindBefore,
+// напоминание по каждому объявлению выдаётся один раз
+func (s *Service) ClaimExpiryReminders(ctx context.Context, remindBefore time.Duration, limit int) ([]ExpiryReminder, error) {
+	ctx, span := tracing.Start(ctx, "advertisement.Service.ClaimExpiryReminders")
+	defer span.End()
+
+	return s.repo.ClaimExpiryReminders(ctx, time.Now().Add(remindBefore), limit)
+}
+
+// ListScheduled - объявления автора, ожидающие публикации или с отменённой публикацией
+func (s *Service) ListScheduled(ctx context.Context, userID uuid.UUID) ([]AdvertisementList, error) {
+	ctx, span := tracing.Start(ctx, "advertisement.Service.ListScheduled")
+	defer span.End()
+
+	return s.repo.ListScheduled(ctx, userID)
+}
+
+// Reschedule - перенос (или повторное назначение после отмены) времени публикации
+func (s *Service) Reschedule(ctx context.Context, input *ScheduleAdvertisementInput) (*AdvertisementList, error) {
+	ctx, span := tracing.Start(ctx, "advertisement.Service.Reschedule")
+	defer span.End()
+
+	if err := validatePublishAt(input.PublishAt); err != nil {
+		return nil, err
+	}
+
+	// Перенос и событие о нём сохраняются вместе
+	var ad *AdvertisementList
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		ad, err = s.getOwnUnpublished(ctx, input.AdvertisementID, input.UserID)
+		if err != nil {
+			return err
+		}
+
+		expiresAt := input.PublishAt.Add(s.lifetime.For(ad.Category))
+		if err := s.repo.Schedule(ctx, ad.ID, input.PublishAt, expiresAt); err != nil {
+			return err
+		}
+
+		ad.Status = StatusScheduled
+		ad.PublishAt = &input.PublishAt
+		ad.ExpiresAt = expiresAt
+		return s.record(ctx, EventUpdated, updatedEvent(ad, input.UserID))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ad, nil
+}
+
+// CancelSchedule - отмена запланированной публикации, объявление остаётся черновиком
+func (s *Service) CancelSchedule(ctx context.Context, input *CancelScheduleInput) (*AdvertisementList, error) {
+	ctx, span := tracing.Start(ctx, "advertisement.Service.CancelSchedule")
+	defer span.End()
+
+	// Отмена и событие о ней сохраняются вместе
+	var ad *AdvertisementList
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		ad, err = s.getOwnUnpublished(ctx, input.AdvertisementID, input.UserID)
+		if err != nil {
+			return err
+		}
+		if ad.Status == StatusDraft {
+			return nil
+		}
+
+		if err := s.repo.CancelSchedule(ctx, ad.ID); err != nil {
+			return err
+		}
+
+		ad.Status = StatusDraft
+		ad.PublishAt = nil
+		return s.record(ctx, EventUpdated, updatedEvent(ad, input.UserID))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ad, nil
+}
+
+// PublishDue - публикация объявлений, время публикации которых наступило.
+// Публикация и события о ней сохраняются в одной транзакции
+func (s *Service) PublishDue(ctx context.Context, limit int) (int64, error) {
+	ctx, span := tracing.Start(ctx, "advertisement.Service.PublishDue")
+	defer span.End()
+
+	var published []Advertisement
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		published, err = s.repo.PublishDue(ctx, limit)
+		if err != nil {
+			return err
+		}
+		for i := range published {
+			if err := s.record(ctx, EventPublished, advertisementEvent(&published[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(published)), nil
+}
+
+// getOwnUnpublished возвращает неопубликованное объявление автора
+func (s *Service) getOwnUnpublished(ctx context.Context, id, userID uuid.UUID) (*AdvertisementList, error) {
+	ad, err := s.repo.GetByID(ctx, id, &userID)
+	if err != nil {
+		return nil, err
+	}
+	if ad == nil {
+		return nil, ErrAdNotFound
+	}
+	if !isOwner(ad) {
+		return nil, ErrNotOwner
+	}
+	if !isUnpublished(ad) {
+		return nil, ErrAlreadyPublished
+	}
+	return ad, nil
+}
+
+func isUnpublished(ad *AdvertisementList) bool {
+	return ad.Status == StatusScheduled || ad.Status == StatusDraft
+}
+
+func isActive(ad *AdvertisementList) bool {
+	return ad.Status == StatusActive && ad.ExpiresAt.After(time.Now())
+}
+
+func isOwner(ad *AdvertisementList) bool {
+	return ad.IsOwner != nil && *ad.IsOwner
+}
+
 // validateListAdParams проверяет корректность параметров для получения списка объявлений
 func (s *Service) validateListAdParams(params *AdvertisementListParams) (*AdvertisementListParams, error) {
 	if params.Page < 1 {
 		params.Page = 1
 	}
 	if params.Limit < 1 {
-		params.Limit = 10
+		params.Limit = s.pagination.DefaultLimit
+	}
+	if params.Limit > s.pagination.MaxLimit {
+		params.Limit = s.pagination.MaxLimit
 	}
 	if params.SortDirection == "" || params.SortDirection != "asc" {
 		params.SortDirection = "desc"
@@ -117,7 +635,7 @@ func (s *Service) validateListAdParams(params *AdvertisementListParams) (*Advert
 		params.MinPriceKopecks = 0
 	}
 	if params.MaxPriceKopecks < params.MinPriceKopecks && params.MaxPriceKopecks != 0 && params.MinPriceKopecks != 0 {
-		return nil, errors.New("minimum price cannot be higher than the maximum")
+		return nil, fmt.Errorf("%w: minimum price cannot be higher than the maximum", ErrInvalidInput)
 	}
 	return params, nil
 }
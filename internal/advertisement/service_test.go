@@ -5,22 +5,55 @@ import (
 	"errors"
 	"marketplace-api/internal/advertisement"
 	mockad "marketplace-api/internal/advertisement/mock"
+	"marketplace-api/internal/outbox"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
 
+var lifetime = advertisement.Lifetime{
+	Default:    30 * 24 * time.Hour,
+	ByCategory: map[string]time.Duration{"jobs": 14 * 24 * time.Hour},
+}
+
+var pagination = advertisement.Pagination{DefaultLimit: 10, MaxLimit: 100}
+
+// noTx - выполняет функцию без транзакции
+type noTx struct{}
+
+func (noTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// eventLog - сохранённые сервисом события
+type eventLog struct {
+	events []outbox.Event
+}
+
+func (l *eventLog) Record(_ context.Context, events ...outbox.Event) error {
+	l.events = append(l.events, events...)
+	return nil
+}
+
 func setupTest(t *testing.T) (*gomock.Controller, *mockad.MockRepositoryInterface, *advertisement.Service) {
 	t.Helper()
+	ctrl, mockRepo, _, service := setupEventsTest(t)
+	return ctrl, mockRepo, service
+}
+
+func setupEventsTest(t *testing.T) (*gomock.Controller, *mockad.MockRepositoryInterface, *eventLog, *advertisement.Service) {
+	t.Helper()
 
 	ctrl := gomock.NewController(t)
 	mockRepo := mockad.NewMockRepositoryInterface(ctrl)
-	service := advertisement.NewAdService(mockRepo)
+	events := &eventLog{}
+	service := advertisement.NewAdService(mockRepo, noTx{}, events, lifetime, pagination)
 
-	return ctrl, mockRepo, service
+	return ctrl, mockRepo, events, service
 }
 
 func TestService_Create(t *testing.T) {
@@ -36,11 +69,13 @@ func TestService_Create(t *testing.T) {
 		defer ctrl.Finish()
 
 		mockRepo := mockad.NewMockRepositoryInterface(ctrl)
-		service := advertisement.NewAdService(mockRepo)
+		events := &eventLog{}
+		service := advertisement.NewAdService(mockRepo, noTx{}, events, lifetime, pagination)
 
 		mockRepo.EXPECT().
 			Create(gomock.Any(), gomock.Any()).
 			Return(&advertisement.Advertisement{
+				ID:           uuid.New(),
 				Title:        validInput.Title,
 				Description:  validInput.Description,
 				ImageURL:     validInput.ImageURL,
@@ -51,6 +86,10 @@ func TestService_Create(t *testing.T) {
 		ad, err := service.Create(context.Background(), validInput)
 		assert.NoError(t, err)
 		assert.Equal(t, validInput.Title, ad.Title)
+
+		assert.Len(t, events.events, 1)
+		assert.Equal(t, advertisement.EventCreated, events.events[0].Type)
+		assert.Equal(t, ad.ID, events.events[0].AggregateID)
 	})
 
 	t.Run("валидация: короткий title", func(t *testing.T) {
@@ -119,6 +158,140 @@ func TestService_Create(t *testing.T) {
 		assert.Regexp(t, regexp.MustCompile("invalid price"), err.Error())
 	})
 
+	t.Run("успешное создание аукциона", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		input := *validInput
+		input.ListingType = advertisement.ListingTypeAuction
+		input.Auction = &advertisement.AuctionTerms{
+			EndsAt:                 time.Now().Add(24 * time.Hour),
+			MinBidIncrementKopecks: 100,
+		}
+
+		mockRepo.EXPECT().
+			Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, ad *advertisement.Advertisement) (*advertisement.Advertisement, error) {
+				assert.Equal(t, advertisement.ListingTypeAuction, ad.ListingType)
+				assert.NotNil(t, ad.Auction)
+				return ad, nil
+			})
+
+		_, err := service.Create(context.Background(), &input)
+		assert.NoError(t, err)
+	})
+
+	t.Run("валидация: аукцион без условий", func(t *testing.T) {
+		ctrl, _, service := setupTest(t)
+		defer ctrl.Finish()
+
+		badInput := *validInput
+		badInput.ListingType = advertisement.ListingTypeAuction
+		_, err := service.Create(context.Background(), &badInput)
+		assert.ErrorContains(t, err, "auction terms are required")
+	})
+
+	t.Run("валидация: условия аукциона", func(t *testing.T) {
+		ctrl, _, service := setupTest(t)
+		defer ctrl.Finish()
+
+		lowBuyNow := validInput.PriceKopecks
+		badInput := *validInput
+		badInput.ListingType = advertisement.ListingTypeAuction
+
+		badInput.Auction = &advertisement.AuctionTerms{EndsAt: time.Now().Add(time.Minute), MinBidIncrementKopecks: 100}
+		_, err := service.Create(context.Background(), &badInput)
+		assert.ErrorContains(t, err, "invalid auction end time")
+
+		badInput.Auction = &advertisement.AuctionTerms{EndsAt: time.Now().Add(24 * time.Hour)}
+		_, err = service.Create(context.Background(), &badInput)
+		assert.ErrorContains(t, err, "invalid min bid increment")
+
+		badInput.Auction = &advertisement.AuctionTerms{
+			EndsAt: time.Now().Add(24 * time.Hour), MinBidIncrementKopecks: 100, BuyNowPriceKopecks: &lowBuyNow,
+		}
+		_, err = service.Create(context.Background(), &badInput)
+		assert.ErrorContains(t, err, "invalid buy now price")
+	})
+
+	t.Run("срок размещения зависит от категории", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		input := *validInput
+		input.Category = " Jobs "
+
+		mockRepo.EXPECT().
+			Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, ad *advertisement.Advertisement) (*advertisement.Advertisement, error) {
+				assert.Equal(t, "jobs", ad.Category)
+				assert.Equal(t, advertisement.StatusActive, ad.Status)
+				assert.WithinDuration(t, time.Now().Add(14*24*time.Hour), ad.ExpiresAt, time.Minute)
+				return ad, nil
+			})
+
+		_, err := service.Create(context.Background(), &input)
+		assert.NoError(t, err)
+	})
+
+	t.Run("валидация: недопустимая категория", func(t *testing.T) {
+		ctrl, _, service := setupTest(t)
+		defer ctrl.Finish()
+
+		badInput := *validInput
+		badInput.Category = "бытовая техника"
+		_, err := service.Create(context.Background(), &badInput)
+		assert.ErrorContains(t, err, "invalid category")
+	})
+
+	t.Run("отложенная публикация", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		publishAt := time.Now().Add(48 * time.Hour)
+		input := *validInput
+		input.PublishAt = &publishAt
+
+		mockRepo.EXPECT().
+			Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, ad *advertisement.Advertisement) (*advertisement.Advertisement, error) {
+				assert.Equal(t, advertisement.StatusScheduled, ad.Status)
+				assert.Equal(t, publishAt.Add(lifetime.Default), ad.ExpiresAt)
+				return ad, nil
+			})
+
+		_, err := service.Create(context.Background(), &input)
+		assert.NoError(t, err)
+	})
+
+	t.Run("валидация: время публикации", func(t *testing.T) {
+		ctrl, _, service := setupTest(t)
+		defer ctrl.Finish()
+
+		past := time.Now().Add(-time.Minute)
+		badInput := *validInput
+		badInput.PublishAt = &past
+		_, err := service.Create(context.Background(), &badInput)
+		assert.ErrorContains(t, err, "invalid publish time")
+
+		future := time.Now().Add(time.Hour)
+		badInput.PublishAt = &future
+		badInput.ListingType = advertisement.ListingTypeAuction
+		badInput.Auction = &advertisement.AuctionTerms{EndsAt: time.Now().Add(24 * time.Hour), MinBidIncrementKopecks: 100}
+		_, err = service.Create(context.Background(), &badInput)
+		assert.ErrorContains(t, err, "not available for auctions")
+	})
+
+	t.Run("валидация: неизвестный тип объявления", func(t *testing.T) {
+		ctrl, _, service := setupTest(t)
+		defer ctrl.Finish()
+
+		badInput := *validInput
+		badInput.ListingType = "barter"
+		_, err := service.Create(context.Background(), &badInput)
+		assert.ErrorContains(t, err, "invalid listing_type")
+	})
+
 	//
 	t.Run("тест ошибки из репозитория", func(t *testing.T) {
 		ctrl, mockRepo, service := setupTest(t)
@@ -246,6 +419,24 @@ func TestService_ListAd(t *testing.T) {
 		assert.NotNil(t, list)
 	})
 
+	t.Run("валидация: limit больше максимального", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		badParams := *params
+		badParams.Limit = 1000
+
+		mockRepo.EXPECT().
+			GetAdvertisementsList(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, p *advertisement.AdvertisementListParams) ([]advertisement.AdvertisementList, error) {
+				assert.Equal(t, pagination.MaxLimit, p.Limit)
+				return expectedList, nil
+			})
+
+		_, err := service.ListAd(context.Background(), &badParams)
+		assert.NoError(t, err)
+	})
+
 	// Тест ошибки из репозитория
 	t.Run("тест ошибки из репозитория", func(t *testing.T) {
 		ctrl, mockRepo, service := setupTest(t)
@@ -265,3 +456,503 @@ func TestService_ListAd(t *testing.T) {
 	})
 
 }
+
+func TestService_GetAd(t *testing.T) {
+	owner, notOwner := true, false
+
+	t.Run("ошибка: объявление не найдено", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		_, err := service.GetAd(context.Background(), uuid.New(), nil)
+		assert.ErrorIs(t, err, advertisement.ErrAdNotFound)
+	})
+
+	t.Run("снятое объявление видно только автору", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		archived := advertisement.AdvertisementList{Status: advertisement.StatusArchived, ExpiresAt: time.Now().Add(-time.Hour)}
+
+		forOwner := archived
+		forOwner.IsOwner = &owner
+		mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any(), gomock.Any()).Return(&forOwner, nil)
+		_, err := service.GetAd(context.Background(), uuid.New(), nil)
+		assert.NoError(t, err)
+
+		forOther := archived
+		forOther.IsOwner = &notOwner
+		mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any(), gomock.Any()).Return(&forOther, nil)
+		_, err = service.GetAd(context.Background(), uuid.New(), nil)
+		assert.ErrorIs(t, err, advertisement.ErrAdNotFound)
+	})
+}
+
+func TestService_Renew(t *testing.T) {
+	owner, notOwner := true, false
+	input := &advertisement.RenewAdvertisementInput{AdvertisementID: uuid.New(), UserID: uuid.New()}
+
+	t.Run("успешное продление", func(t *testing.T) {
+		ctrl, mockRepo, events, service := setupEventsTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), input.AdvertisementID, &input.UserID).
+			Return(&advertisement.AdvertisementList{
+				ID: input.AdvertisementID, Category: "jobs", Status: advertisement.StatusArchived, IsOwner: &owner,
+			}, nil)
+		mockRepo.EXPECT().Renew(gomock.Any(), input.AdvertisementID, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ uuid.UUID, expiresAt time.Time) error {
+				assert.WithinDuration(t, time.Now().Add(14*24*time.Hour), expiresAt, time.Minute)
+				return nil
+			})
+
+		ad, err := service.Renew(context.Background(), input)
+		assert.NoError(t, err)
+		assert.Equal(t, advertisement.StatusActive, ad.Status)
+
+		assert.Len(t, events.events, 1)
+		var payload advertisement.AdvertisementEvent
+		assert.NoError(t, events.events[0].Decode(&payload))
+		assert.Equal(t, advertisement.EventRenewed, events.events[0].Type)
+		assert.Equal(t, advertisement.StatusActive, payload.Status)
+		assert.Equal(t, input.UserID, payload.AuthorID)
+	})
+
+	t.Run("продление выполняется в транзакции", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRepo := mockad.NewMockRepositoryInterface(ctrl)
+		mockTx := mockad.NewMockTransactor(ctrl)
+		service := advertisement.NewAdService(mockRepo, mockTx, &eventLog{}, lifetime, pagination)
+
+		errTx := errors.New("commit failed")
+		mockTx.EXPECT().WithinTx(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				assert.NoError(t, fn(ctx))
+				return errTx
+			})
+		mockRepo.EXPECT().GetByID(gomock.Any(), input.AdvertisementID, &input.UserID).
+			Return(&advertisement.AdvertisementList{ID: input.AdvertisementID, Status: advertisement.StatusArchived, IsOwner: &owner}, nil)
+		mockRepo.EXPECT().Renew(gomock.Any(), input.AdvertisementID, gomock.Any()).Return(nil)
+
+		_, err := service.Renew(context.Background(), input)
+		assert.ErrorIs(t, err, errTx)
+	})
+
+	t.Run("ошибка: чужое объявление", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&advertisement.AdvertisementList{IsOwner: &notOwner}, nil)
+
+		_, err := service.Renew(context.Background(), input)
+		assert.ErrorIs(t, err, advertisement.ErrNotOwner)
+	})
+
+	t.Run("ошибка: объявление не найдено", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		_, err := service.Renew(context.Background(), input)
+		assert.ErrorIs(t, err, advertisement.ErrAdNotFound)
+	})
+
+	t.Run("ошибка: торги по объявлению завершены", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), input.AdvertisementID, &input.UserID).
+			Return(&advertisement.AdvertisementList{
+				ID: input.AdvertisementID, ListingType: advertisement.ListingTypeAuction, Status: advertisement.StatusClosed, IsOwner: &owner,
+			}, nil)
+		mockRepo.EXPECT().Renew(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := service.Renew(context.Background(), input)
+		assert.ErrorIs(t, err, advertisement.ErrListingClosed)
+	})
+
+	t.Run("ошибка: торги завершились после чтения объявления", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), input.AdvertisementID, &input.UserID).
+			Return(&advertisement.AdvertisementList{
+				ID: input.AdvertisementID, ListingType: advertisement.ListingTypeAuction, Status: advertisement.StatusActive, IsOwner: &owner,
+			}, nil)
+		mockRepo.EXPECT().Renew(gomock.Any(), input.AdvertisementID, gomock.Any()).Return(advertisement.ErrListingClosed)
+
+		_, err := service.Renew(context.Background(), input)
+		assert.ErrorIs(t, err, advertisement.ErrListingClosed)
+	})
+}
+
+func TestService_Update(t *testing.T) {
+	owner, notOwner := true, false
+	updatedAt := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	adID, userID := uuid.New(), uuid.New()
+	stored := func() *advertisement.AdvertisementList {
+		return &advertisement.AdvertisementList{
+			ID: adID, Title: "Old title", Description: "Old description", ImageURL: "http://example.com/image.jpg",
+			PriceKopecks: 1000, ListingType: advertisement.ListingTypeFixed, IsOwner: &owner, UpdatedAt: updatedAt,
+		}
+	}
+	title := "New title"
+
+	t.Run("успешное изменение", func(t *testing.T) {
+		ctrl, mockRepo, events, service := setupEventsTest(t)
+		defer ctrl.Finish()
+
+		updated := stored()
+		updated.Title = title
+		updated.UpdatedAt = updatedAt.Add(time.Minute)
+		gomock.InOrder(
+			mockRepo.EXPECT().GetByID(gomock.Any(), adID, &userID).Return(stored(), nil),
+			mockRepo.EXPECT().Update(gomock.Any(), gomock.Any(), &updatedAt).
+				DoAndReturn(func(_ context.Context, ad *advertisement.AdvertisementList, _ *time.Time) error {
+					assert.Equal(t, title, ad.Title)
+					assert.Equal(t, "Old description", ad.Description)
+					return nil
+				}),
+			mockRepo.EXPECT().GetByID(gomock.Any(), adID, &userID).Return(updated, nil),
+		)
+
+		ad, err := service.Update(context.Background(), &advertisement.UpdateAdvertisementInput{
+			AdvertisementID: adID, UserID: userID, Title: &title,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, updated.UpdatedAt, ad.UpdatedAt)
+
+		assert.Len(t, events.events, 1)
+		var payload advertisement.AdvertisementEvent
+		assert.NoError(t, events.events[0].Decode(&payload))
+		assert.Equal(t, advertisement.EventUpdated, events.events[0].Type)
+		assert.Equal(t, title, payload.Title)
+		assert.Equal(t, userID, payload.AuthorID)
+	})
+
+	t.Run("изменение выполняется в транзакции", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRepo := mockad.NewMockRepositoryInterface(ctrl)
+		mockTx := mockad.NewMockTransactor(ctrl)
+		service := advertisement.NewAdService(mockRepo, mockTx, &eventLog{}, lifetime, pagination)
+
+		errTx := errors.New("commit failed")
+		mockTx.EXPECT().WithinTx(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				assert.NoError(t, fn(ctx))
+				return errTx
+			})
+		mockRepo.EXPECT().GetByID(gomock.Any(), adID, &userID).Return(stored(), nil).Times(2)
+		mockRepo.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+		_, err := service.Update(context.Background(), &advertisement.UpdateAdvertisementInput{
+			AdvertisementID: adID, UserID: userID, Title: &title,
+		})
+		assert.ErrorIs(t, err, errTx)
+	})
+
+	t.Run("ошибка: объявление изменилось", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), adID, &userID).Return(stored(), nil)
+
+		stale := updatedAt.Add(-time.Minute)
+		_, err := service.Update(context.Background(), &advertisement.UpdateAdvertisementInput{
+			AdvertisementID: adID, UserID: userID, UnmodifiedSince: &stale, Title: &title,
+		})
+		assert.ErrorIs(t, err, advertisement.ErrModified)
+	})
+
+	t.Run("ошибка: изменение во время записи", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), adID, &userID).Return(stored(), nil)
+		mockRepo.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Return(advertisement.ErrModified)
+
+		_, err := service.Update(context.Background(), &advertisement.UpdateAdvertisementInput{
+			AdvertisementID: adID, UserID: userID, Title: &title,
+		})
+		assert.ErrorIs(t, err, advertisement.ErrModified)
+	})
+
+	t.Run("ошибка: чужое объявление", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&advertisement.AdvertisementList{IsOwner: &notOwner}, nil)
+
+		_, err := service.Update(context.Background(), &advertisement.UpdateAdvertisementInput{
+			AdvertisementID: adID, UserID: userID, Title: &title,
+		})
+		assert.ErrorIs(t, err, advertisement.ErrNotOwner)
+	})
+
+	t.Run("ошибка: объявление не найдено", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		_, err := service.Update(context.Background(), &advertisement.UpdateAdvertisementInput{
+			AdvertisementID: adID, UserID: userID,
+		})
+		assert.ErrorIs(t, err, advertisement.ErrAdNotFound)
+	})
+
+	t.Run("ошибка: невалидный заголовок", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), adID, &userID).Return(stored(), nil)
+
+		empty := ""
+		_, err := service.Update(context.Background(), &advertisement.UpdateAdvertisementInput{
+			AdvertisementID: adID, UserID: userID, Title: &empty,
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("ошибка: изменение стартовой цены аукциона", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		auction := stored()
+		auction.ListingType = advertisement.ListingTypeAuction
+		mockRepo.EXPECT().GetByID(gomock.Any(), adID, &userID).Return(auction, nil)
+
+		price := 5000
+		_, err := service.Update(context.Background(), &advertisement.UpdateAdvertisementInput{
+			AdvertisementID: adID, UserID: userID, PriceKopecks: &price,
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestParseCategoryLifetimes(t *testing.T) {
+	lifetimes, err := advertisement.ParseCategoryLifetimes("Electronics=1440h, jobs=336h")
+	assert.NoError(t, err)
+	assert.Equal(t, 1440*time.Hour, lifetimes["electronics"])
+	assert.Equal(t, 336*time.Hour, lifetimes["jobs"])
+
+	_, err = advertisement.ParseCategoryLifetimes("jobs")
+	assert.Error(t, err)
+
+	_, err = advertisement.ParseCategoryLifetimes("jobs=-1h")
+	assert.Error(t, err)
+}
+
+func TestService_Reschedule(t *testing.T) {
+	owner := true
+	userID := uuid.New()
+	adID := uuid.New()
+	publishAt := time.Now().Add(24 * time.Hour)
+
+	t.Run("перенос черновика", func(t *testing.T) {
+		ctrl, mockRepo, events, service := setupEventsTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), adID, &userID).
+			Return(&advertisement.AdvertisementList{ID: adID, Status: advertisement.StatusDraft, IsOwner: &owner}, nil)
+		mockRepo.EXPECT().Schedule(gomock.Any(), adID, publishAt, publishAt.Add(lifetime.Default)).Return(nil)
+
+		ad, err := service.Reschedule(context.Background(), &advertisement.ScheduleAdvertisementInput{
+			AdvertisementID: adID, PublishAt: publishAt, UserID: userID,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, advertisement.StatusScheduled, ad.Status)
+
+		assert.Len(t, events.events, 1)
+		var payload advertisement.AdvertisementEvent
+		assert.NoError(t, events.events[0].Decode(&payload))
+		assert.Equal(t, advertisement.EventUpdated, events.events[0].Type)
+		assert.Equal(t, advertisement.StatusScheduled, payload.Status)
+		assert.Equal(t, publishAt.UTC(), payload.PublishAt.UTC())
+	})
+
+	t.Run("перенос выполняется в транзакции", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRepo := mockad.NewMockRepositoryInterface(ctrl)
+		mockTx := mockad.NewMockTransactor(ctrl)
+		service := advertisement.NewAdService(mockRepo, mockTx, &eventLog{}, lifetime, pagination)
+
+		errTx := errors.New("commit failed")
+		mockTx.EXPECT().WithinTx(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				assert.NoError(t, fn(ctx))
+				return errTx
+			})
+		mockRepo.EXPECT().GetByID(gomock.Any(), adID, &userID).
+			Return(&advertisement.AdvertisementList{ID: adID, Status: advertisement.StatusDraft, IsOwner: &owner}, nil)
+		mockRepo.EXPECT().Schedule(gomock.Any(), adID, gomock.Any(), gomock.Any()).Return(nil)
+
+		_, err := service.Reschedule(context.Background(), &advertisement.ScheduleAdvertisementInput{
+			AdvertisementID: adID, PublishAt: publishAt, UserID: userID,
+		})
+		assert.ErrorIs(t, err, errTx)
+	})
+
+	t.Run("ошибка: объявление уже опубликовано", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), adID, &userID).
+			Return(&advertisement.AdvertisementList{ID: adID, Status: advertisement.StatusActive, IsOwner: &owner}, nil)
+
+		_, err := service.Reschedule(context.Background(), &advertisement.ScheduleAdvertisementInput{
+			AdvertisementID: adID, PublishAt: publishAt, UserID: userID,
+		})
+		assert.ErrorIs(t, err, advertisement.ErrAlreadyPublished)
+	})
+
+	t.Run("ошибка: время в прошлом", func(t *testing.T) {
+		ctrl, _, service := setupTest(t)
+		defer ctrl.Finish()
+
+		_, err := service.Reschedule(context.Background(), &advertisement.ScheduleAdvertisementInput{
+			AdvertisementID: adID, PublishAt: time.Now().Add(-time.Hour), UserID: userID,
+		})
+		assert.ErrorContains(t, err, "invalid publish time")
+	})
+}
+
+func TestService_CancelSchedule(t *testing.T) {
+	owner := true
+	userID := uuid.New()
+	adID := uuid.New()
+
+	t.Run("успешная отмена", func(t *testing.T) {
+		ctrl, mockRepo, events, service := setupEventsTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), adID, &userID).
+			Return(&advertisement.AdvertisementList{ID: adID, Status: advertisement.StatusScheduled, IsOwner: &owner}, nil)
+		mockRepo.EXPECT().CancelSchedule(gomock.Any(), adID).Return(nil)
+
+		ad, err := service.CancelSchedule(context.Background(), &advertisement.CancelScheduleInput{AdvertisementID: adID, UserID: userID})
+		assert.NoError(t, err)
+		assert.Equal(t, advertisement.StatusDraft, ad.Status)
+		assert.Nil(t, ad.PublishAt)
+
+		assert.Len(t, events.events, 1)
+		var payload advertisement.AdvertisementEvent
+		assert.NoError(t, events.events[0].Decode(&payload))
+		assert.Equal(t, advertisement.EventUpdated, events.events[0].Type)
+		assert.Equal(t, advertisement.StatusDraft, payload.Status)
+	})
+
+	t.Run("черновик не меняется и событие не пишется", func(t *testing.T) {
+		ctrl, mockRepo, events, service := setupEventsTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), adID, &userID).
+			Return(&advertisement.AdvertisementList{ID: adID, Status: advertisement.StatusDraft, IsOwner: &owner}, nil)
+
+		_, err := service.CancelSchedule(context.Background(), &advertisement.CancelScheduleInput{AdvertisementID: adID, UserID: userID})
+		assert.NoError(t, err)
+		assert.Empty(t, events.events)
+	})
+
+	t.Run("продление неопубликованного запрещено", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().GetByID(gomock.Any(), adID, &userID).
+			Return(&advertisement.AdvertisementList{ID: adID, Status: advertisement.StatusScheduled, IsOwner: &owner}, nil)
+
+		_, err := service.Renew(context.Background(), &advertisement.RenewAdvertisementInput{AdvertisementID: adID, UserID: userID})
+		assert.ErrorIs(t, err, advertisement.ErrNotPublishedYet)
+	})
+}
+
+func TestService_PublishDue(t *testing.T) {
+	t.Run("событие на каждое опубликованное объявление", func(t *testing.T) {
+		ctrl, mockRepo, events, service := setupEventsTest(t)
+		defer ctrl.Finish()
+
+		published := []advertisement.Advertisement{
+			{ID: uuid.New(), Status: advertisement.StatusActive},
+			{ID: uuid.New(), Status: advertisement.StatusActive},
+		}
+		mockRepo.EXPECT().PublishDue(gomock.Any(), 100).Return(published, nil)
+
+		n, err := service.PublishDue(context.Background(), 100)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		assert.Len(t, events.events, 2)
+		for i, event := range events.events {
+			assert.Equal(t, advertisement.EventPublished, event.Type)
+			assert.Equal(t, published[i].ID, event.AggregateID)
+		}
+	})
+
+	t.Run("публикация выполняется в транзакции", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRepo := mockad.NewMockRepositoryInterface(ctrl)
+		mockTx := mockad.NewMockTransactor(ctrl)
+		service := advertisement.NewAdService(mockRepo, mockTx, &eventLog{}, lifetime, pagination)
+
+		errTx := errors.New("commit failed")
+		mockTx.EXPECT().WithinTx(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				assert.NoError(t, fn(ctx))
+				return errTx
+			})
+		mockRepo.EXPECT().PublishDue(gomock.Any(), 100).Return([]advertisement.Advertisement{{ID: uuid.New()}}, nil)
+
+		_, err := service.PublishDue(context.Background(), 100)
+		assert.ErrorIs(t, err, errTx)
+	})
+}
+
+func TestService_ArchiveExpired(t *testing.T) {
+	t.Run("событие на каждое снятое объявление", func(t *testing.T) {
+		ctrl, mockRepo, events, service := setupEventsTest(t)
+		defer ctrl.Finish()
+
+		authorID := uuid.New()
+		archived := []advertisement.Advertisement{
+			{ID: uuid.New(), AuthorID: authorID, Status: advertisement.StatusArchived},
+			{ID: uuid.New(), AuthorID: authorID, Status: advertisement.StatusArchived},
+		}
+		mockRepo.EXPECT().ArchiveExpired(gomock.Any(), 100).Return(archived, nil)
+
+		n, err := service.ArchiveExpired(context.Background(), 100)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		assert.Len(t, events.events, 2)
+		for i, event := range events.events {
+			var payload advertisement.AdvertisementEvent
+			assert.NoError(t, event.Decode(&payload))
+			assert.Equal(t, advertisement.EventExpired, event.Type)
+			assert.Equal(t, archived[i].ID, payload.ID)
+			assert.Equal(t, authorID, payload.AuthorID)
+		}
+	})
+
+	t.Run("снятие выполняется в транзакции", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRepo := mockad.NewMockRepositoryInterface(ctrl)
+		mockTx := mockad.NewMockTransactor(ctrl)
+		service := advertisement.NewAdService(mockRepo, mockTx, &eventLog{}, lifetime, pagination)
+
+		errTx := errors.New("commit failed")
+		mockTx.EXPECT().WithinTx(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				assert.NoError(t, fn(ctx))
+				return errTx
+			})
+		mockRepo.EXPECT().ArchiveExpired(gomock.Any(), 100).Return([]advertisement.Advertisement{{ID: uuid.New()}}, nil)
+
+		_, err := service.ArchiveExpired(context.Background(), 100)
+		assert.ErrorIs(t, err, errTx)
+	})
+}
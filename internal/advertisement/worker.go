@@ -0,0 +1,173 @@
+package advertisement
+
+import (
+	"context"
+	"fmt"
+	"marketplace-api/internal/logging"
+	"marketplace-api/internal/notification"
+	"time"
+)
+
+const workerBatchSize = 100
+
+// Expirer - фоновая задача: снимает с публикации истёкшие объявления
+// и напоминает авторам о скором окончании срока размещения
+type Expirer struct {
+	service      *Service
+	notifier     notification.Notifier
+	interval     time.Duration
+	remindBefore time.Duration
+}
+
+func NewExpirer(service *Service, notifier notification.Notifier, interval, remindBefore time.Duration) *Expirer {
+	return &Expirer{service: service, notifier: notifier, interval: interval, remindBefore: remindBefore}
+}
+
+// Run - запускает периодическую обработку до отмены ctx
+func (e *Expirer) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		e.archiveExpired(ctx)
+		e.sendReminders(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Expirer) archiveExpired(ctx context.Context) {
+	for {
+		archived, err := e.service.ArchiveExpired(ctx, workerBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				logging.FromContext(ctx).Error("error archiving expired advertisements", "error", err)
+			}
+			return
+		}
+		if archived > 0 {
+			logging.FromContext(ctx).Info("archived expired advertisements", "count", archived)
+		}
+		if archived < workerBatchSize {
+			return
+		}
+	}
+}
+
+func (e *Expirer) sendReminders(ctx context.Context) {
+	for {
+		reminders, err := e.service.ClaimExpiryReminders(ctx, e.remindBefore, workerBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				logging.FromContext(ctx).Error("error selecting expiry reminders", "error", err)
+			}
+			return
+		}
+
+		for _, rem := range reminders {
+			err := e.notifier.Notify(ctx, notification.Notification{
+				UserID:  rem.AuthorID,
+				Subject: "Срок размещения объявления истекает",
+				Body: fmt.Sprintf("Объявление %q будет снято с публикации %s. Продлите его, чтобы оно осталось в ленте.",
+					rem.Title, rem.ExpiresAt.Format("02.01.2006 15:04")),
+			})
+			if err != nil {
+				logging.FromContext(ctx).Error("error sending expiry reminder", "advertisement_id", rem.AdvertisementID, "error", err)
+			}
+		}
+
+		if len(reminders) < workerBatchSize {
+			return
+		}
+	}
+}
+
+// Scheduler - фоновая задача, публикующая объявления с наступившим временем публикации.
+// Состояние хранится в БД, поэтому пропущенные за время простоя публикации выполняются после перезапуска
+type Scheduler struct {
+	service  *Service
+	interval time.Duration
+}
+
+func NewScheduler(service *Service, interval time.Duration) *Scheduler {
+	return &Scheduler{service: service, interval: interval}
+}
+
+// Run - запускает периодическую публикацию до отмены ctx
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.publishDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) publishDue(ctx context.Context) {
+	for {
+		published, err := s.service.PublishDue(ctx, workerBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				logging.FromContext(ctx).Error("error publishing scheduled advertisements", "error", err)
+			}
+			return
+		}
+		if published > 0 {
+			logging.FromContext(ctx).Info("published scheduled advertisements", "count", published)
+		}
+		if published < workerBatchSize {
+			return
+		}
+	}
+}
+
+// ImportWorker - фоновая задача, обрабатывающая задания импорта объявлений по одному
+type ImportWorker struct {
+	importer *Importer
+	interval time.Duration
+}
+
+func NewImportWorker(importer *Importer, interval time.Duration) *ImportWorker {
+	return &ImportWorker{importer: importer, interval: interval}
+}
+
+// Run - запускает обработку очереди импорта до отмены ctx
+func (w *ImportWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.processQueued(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *ImportWorker) processQueued(ctx context.Context) {
+	for {
+		processed, err := w.importer.ProcessNext(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				logging.FromContext(ctx).Error("error processing advertisement import", "error", err)
+			}
+			return
+		}
+		if !processed {
+			return
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Package v1 - маршруты первой версии API. Обработчики и DTO живут в пакетах предметных
+// областей; следующая версия регистрирует свои обработчики поверх тех же сервисов
+package v1
+
+import (
+	"marketplace-api/internal/advertisement"
+	"marketplace-api/internal/apikey"
+	"marketplace-api/internal/auction"
+	"marketplace-api/internal/middleware"
+	"marketplace-api/internal/promotion"
+	"marketplace-api/internal/router"
+	"marketplace-api/internal/stats"
+	"marketplace-api/internal/user"
+	"marketplace-api/internal/webhook"
+)
+
+const Prefix = "/api/v1"
+
+// Handlers - обработчики версии. Promotion, Webhook и APIKey равны nil, если возможность выключена
+type Handlers struct {
+	User          *user.Handler
+	Advertisement *advertisement.Handler
+	Import        *advertisement.ImportHandler
+	Export        *advertisement.ExportHandler
+	Stats         *stats.Handler
+	Auction       *auction.Handler
+	Promotion     *promotion.Handler
+	Webhook       *webhook.Handler
+	APIKey        *apikey.Handler
+}
+
+// Middlewares - middleware групп маршрутов, nil - не применяется
+type Middlewares struct {
+	RequireAuth  middleware.Middleware
+	OptionalAuth middleware.Middleware
+	AuthLimit    middleware.Middleware // ограничение частоты регистрации и входа
+	WriteLimit   middleware.Middleware // ограничение частоты изменений
+	ReadLimit    middleware.Middleware // ограничение частоты чтения
+	Idempotency  middleware.Middleware // повтор ответа для создающих запросов
+	AdsRead      middleware.Middleware // разрешение API-ключа на чтение объявлений
+	AdsWrite     middleware.Middleware // разрешение API-ключа на изменение объявлений
+	SessionOnly  middleware.Middleware // маршрут недоступен по API-ключу
+}
+
+// Register - регистрирует маршруты версии относительно r: под Prefix или, для устаревших
+// псевдонимов, в корне. Ограничение частоты стоит после авторизации, чтобы считать по пользователю.
+// Маршруты объявлений доступны по API-ключу с нужным разрешением, остальные - только после входа
+func Register(r *router.Router, h Handlers, mw Middlewares) {
+	login := r.Group(mw.AuthLimit)
+	public := r.Group(mw.ReadLimit)
+	authorizedRead := r.Group(mw.RequireAuth, mw.ReadLimit)
+	authorizedWrite := r.Group(mw.RequireAuth, mw.WriteLimit)
+
+	adsOptional := r.Group(mw.OptionalAuth, mw.ReadLimit, mw.AdsRead)
+	adsRead := authorizedRead.Group(mw.AdsRead)
+	adsWrite := authorizedWrite.Group(mw.AdsWrite)
+	adsCreate := adsWrite.Group(mw.Idempotency)
+
+	sessionRead := authorizedRead.Group(mw.SessionOnly)
+	sessionWrite := authorizedWrite.Group(mw.SessionOnly)
+	sessionCreate := sessionWrite.Group(mw.Idempotency)
+
+	login.HandleFunc("POST /register", h.User.Register)
+	login.HandleFunc("POST /login", h.User.Login)
+
+	adsCreate.HandleFunc("POST /advertisement", h.Advertisement.CreateAd)
+	adsOptional.HandleFunc("GET /advertisement/{$}", h.Advertisement.ListAd)
+	adsOptional.HandleFunc("GET /advertisement/{id}", h.Advertisement.GetAd)
+	adsWrite.HandleFunc("PATCH /advertisement/{id}", h.Advertisement.UpdateAd)
+	adsWrite.HandleFunc("POST /advertisement/renew", h.Advertisement.Renew)
+	adsWrite.HandleFunc("POST /advertisement/schedule", h.Advertisement.Reschedule)
+	adsWrite.HandleFunc("POST /advertisement/schedule/cancel", h.Advertisement.CancelSchedule)
+	adsCreate.HandleFunc("POST /advertisement/imports", h.Import.StartImport)
+	adsRead.HandleFunc("GET /advertisement/imports/{id}", h.Import.GetImport)
+	adsRead.HandleFunc("GET /me/advertisements/scheduled", h.Advertisement.ListScheduled)
+	adsRead.HandleFunc("GET /me/advertisements/export", h.Export.ExportAds)
+	public.HandleFunc("GET /advertisement/feed", h.Export.Feed)
+	adsRead.HandleFunc("GET /me/advertisements/stats", h.Stats.SellerStats)
+
+	public.HandleFunc("GET /auction/{id}", h.Auction.GetAuction)
+	sessionCreate.HandleFunc("POST /auction/bid", h.Auction.PlaceBid)
+
+	if h.Promotion != nil {
+		sessionCreate.HandleFunc("POST /promotion", h.Promotion.CreatePromotion)
+	}
+
+	// Ответы с секретами не проходят через идемпотентность: её хранилище сохранило бы
+	// секрет в открытом виде и вернуло бы его повторно
+	if h.Webhook != nil {
+		sessionWrite.HandleFunc("POST /webhooks", h.Webhook.CreateEndpoint)
+		sessionRead.HandleFunc("GET /webhooks", h.Webhook.ListEndpoints)
+		sessionWrite.HandleFunc("DELETE /webhooks/{id}", h.Webhook.DeleteEndpoint)
+		sessionWrite.HandleFunc("POST /webhooks/{id}/enable", h.Webhook.EnableEndpoint)
+		sessionRead.HandleFunc("GET /webhooks/{id}/deliveries", h.Webhook.ListDeliveries)
+		sessionWrite.HandleFunc("POST /webhooks/{id}/deliveries/{deliveryID}/redeliver", h.Webhook.Redeliver)
+	}
+
+	if h.APIKey != nil {
+		sessionWrite.HandleFunc("POST /api-keys", h.APIKey.CreateKey)
+		sessionRead.HandleFunc("GET /api-keys", h.APIKey.ListKeys)
+		sessionWrite.HandleFunc("DELETE /api-keys/{id}", h.APIKey.RevokeKey)
+	}
+}
@@ -0,0 +1,104 @@
+package v1_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	v1 "marketplace-api/internal/api/v1"
+	"marketplace-api/internal/apikey"
+	mockapikey "marketplace-api/internal/apikey/mock"
+	"marketplace-api/internal/auth"
+	"marketplace-api/internal/idempotency"
+	"marketplace-api/internal/router"
+	"marketplace-api/internal/webhook"
+	mockwebhook "marketplace-api/internal/webhook/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// recordingStore - хранилище идемпотентности, запоминающее сохранённые ответы
+type recordingStore struct {
+	*idempotency.MemoryStore
+	bodies [][]byte
+}
+
+func (s *recordingStore) Complete(ctx context.Context, userID uuid.UUID, key string, resp idempotency.Response) error {
+	s.bodies = append(s.bodies, resp.Body)
+	return s.MemoryStore.Complete(ctx, userID, key, resp)
+}
+
+// setupRoutes - маршруты v1 с идемпотентностью и пользователем, вошедшим по паролю
+func setupRoutes(h v1.Handlers) (*router.Router, *recordingStore) {
+	store := &recordingStore{MemoryStore: idempotency.NewMemoryStore(time.Hour, time.Minute)}
+	userID := uuid.New()
+	mux := router.New()
+	v1.Register(mux.Mount(v1.Prefix), h, v1.Middlewares{
+		RequireAuth: func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				next.ServeHTTP(w, r.WithContext(auth.WithUserID(r.Context(), userID)))
+			})
+		},
+		Idempotency: idempotency.Middleware(store, time.Second, v1.Prefix),
+	})
+	return mux, store
+}
+
+func post(t *testing.T, mux http.Handler, path string, input any) *httptest.ResponseRecorder {
+	t.Helper()
+	body, _ := json.Marshal(input)
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	req.Header.Set(idempotency.Header, "key-1")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	return w
+}
+
+func TestRegister_SecretsNotStored(t *testing.T) {
+	t.Run("выпущенный API-ключ не сохраняется для повтора", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockService := mockapikey.NewMockServiceInterface(ctrl)
+		mockService.EXPECT().Create(gomock.Any(), gomock.Any()).
+			Return(&apikey.Key{ID: uuid.New(), Name: "bot", Key: "mk_secret"}, nil).Times(2)
+		mux, store := setupRoutes(v1.Handlers{APIKey: apikey.NewAPIKeyHandler(mockService)})
+
+		input := apikey.CreateKeyInput{Name: "bot", Scopes: []string{auth.ScopeAdsRead}}
+		first := post(t, mux, "/api/v1/api-keys", input)
+		assert.Equal(t, http.StatusCreated, first.Code)
+		assert.Contains(t, first.Body.String(), "mk_secret")
+
+		second := post(t, mux, "/api/v1/api-keys", input)
+		assert.Empty(t, second.Header().Get(idempotency.ReplayedHeader))
+		for _, body := range store.bodies {
+			assert.NotContains(t, string(body), "mk_secret")
+		}
+	})
+
+	t.Run("секрет подписи вебхука не сохраняется для повтора", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockService := mockwebhook.NewMockServiceInterface(ctrl)
+		mockService.EXPECT().CreateEndpoint(gomock.Any(), gomock.Any()).
+			Return(&webhook.Endpoint{ID: uuid.New(), URL: "https://example.com/hook", Secret: "whsec_secret"}, nil).Times(2)
+		mux, store := setupRoutes(v1.Handlers{Webhook: webhook.NewWebhookHandler(mockService)})
+
+		input := webhook.CreateEndpointInput{URL: "https://example.com/hook"}
+		first := post(t, mux, "/api/v1/webhooks", input)
+		assert.Equal(t, http.StatusCreated, first.Code)
+		assert.Contains(t, first.Body.String(), "whsec_secret")
+
+		second := post(t, mux, "/api/v1/webhooks", input)
+		assert.Empty(t, second.Header().Get(idempotency.ReplayedHeader))
+		for _, body := range store.bodies {
+			assert.NotContains(t, string(body), "whsec_secret")
+		}
+	})
+}
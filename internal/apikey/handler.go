@@ -0,0 +1,138 @@
+package apikey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"marketplace-api/internal/auth"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+type ServiceInterface interface {
+	Create(ctx context.Context, input *CreateKeyInput) (*Key, error)
+	List(ctx context.Context, userID uuid.UUID) ([]Key, error)
+	Revoke(ctx context.Context, userID, id uuid.UUID) error
+}
+
+type Handler struct {
+	service ServiceInterface
+}
+
+func NewAPIKeyHandler(service ServiceInterface) *Handler {
+	return &Handler{service: service}
+}
+
+// CreateKey godoc
+// @Summary Выпустить API-ключ
+// @Description Создаёт ключ для обращений с сервера: передаётся как Authorization: Bearer <ключ> вместо JWT. Ключ показывается только в этом ответе. Разрешения: ads:read - чтение объявлений и статистики, ads:write - создание и изменение объявлений. Управление ключами и остальные действия доступны только после входа по паролю
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param input body CreateKeyInput true "Название, разрешения и срок действия"
+// @Success 201 {object} Key
+// @Failure 400 {string} string "Неверный ввод"
+// @Failure 401 {string} string "Пользователь не авторизован"
+// @Failure 403 {string} string "Недоступно для API-ключей"
+// @Failure 409 {string} string "Слишком много ключей"
+// @Security AuthToken
+// @Router /api/v1/api-keys [post]
+func (h *Handler) CreateKey(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var input CreateKeyInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "invalid input", http.StatusBadRequest)
+		return
+	}
+
+	input.UserID = userID
+	key, err := h.service.Create(r.Context(), &input)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(key)
+}
+
+// ListKeys godoc
+// @Summary API-ключи
+// @Description Возвращает неотозванные ключи пользователя без самих ключей: название, начало ключа, разрешения, срок действия и время последнего использования
+// @Tags api-keys
+// @Produce json
+// @Success 200 {array} Key
+// @Failure 401 {string} string "Пользователь не авторизован"
+// @Failure 403 {string} string "Недоступно для API-ключей"
+// @Security AuthToken
+// @Router /api/v1/api-keys [get]
+func (h *Handler) ListKeys(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	keys, err := h.service.List(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(keys)
+}
+
+// RevokeKey godoc
+// @Summary Отозвать API-ключ
+// @Description Запросы с отозванным ключом сразу перестают приниматься
+// @Tags api-keys
+// @Param id path string true "ID ключа"
+// @Success 204
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 401 {string} string "Пользователь не авторизован"
+// @Failure 403 {string} string "Недоступно для API-ключей"
+// @Failure 404 {string} string "Ключ не найден"
+// @Security AuthToken
+// @Router /api/v1/api-keys/{id} [delete]
+func (h *Handler) RevokeKey(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "id must be a valid UUID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Revoke(r.Context(), userID, id); err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// errorStatus сопоставляет ошибку сервиса с HTTP-статусом
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrKeyNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrTooManyKeys):
+		return http.StatusConflict
+	case errors.Is(err, ErrNameRequired), errors.Is(err, ErrNoScopes), errors.Is(err, ErrUnknownScope), errors.Is(err, ErrExpiresInPast):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,117 @@
+package apikey_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"marketplace-api/internal/apikey"
+	mockapikey "marketplace-api/internal/apikey/mock"
+	"marketplace-api/internal/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func setupHandlerTest(t *testing.T) (*gomock.Controller, *mockapikey.MockServiceInterface, *apikey.Handler) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockService := mockapikey.NewMockServiceInterface(ctrl)
+	handler := apikey.NewAPIKeyHandler(mockService)
+	return ctrl, mockService, handler
+}
+
+func TestHandler_CreateKey(t *testing.T) {
+	userID := uuid.New()
+	input := apikey.CreateKeyInput{Name: "bot", Scopes: []string{auth.ScopeAdsWrite}}
+
+	t.Run("успешный выпуск", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, in *apikey.CreateKeyInput) (*apikey.Key, error) {
+				assert.Equal(t, userID, in.UserID)
+				return &apikey.Key{ID: uuid.New(), Name: in.Name, Key: "mk_secret", Hash: "hash"}, nil
+			})
+
+		body, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/api-keys", bytes.NewReader(body))
+		req = req.WithContext(auth.WithUserID(req.Context(), userID))
+		w := httptest.NewRecorder()
+
+		handler.CreateKey(w, req)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), "mk_secret")
+		assert.NotContains(t, w.Body.String(), "hash")
+	})
+
+	t.Run("ошибка: слишком много ключей", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, apikey.ErrTooManyKeys)
+
+		body, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/api-keys", bytes.NewReader(body))
+		req = req.WithContext(auth.WithUserID(req.Context(), userID))
+		w := httptest.NewRecorder()
+
+		handler.CreateKey(w, req)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("ошибка: неавторизован", func(t *testing.T) {
+		_, _, handler := setupHandlerTest(t)
+
+		body, _ := json.Marshal(input)
+		w := httptest.NewRecorder()
+
+		handler.CreateKey(w, httptest.NewRequest(http.MethodPost, "/api-keys", bytes.NewReader(body)))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestHandler_RevokeKey(t *testing.T) {
+	userID := uuid.New()
+	id := uuid.New()
+
+	request := func(id string) *http.Request {
+		req := httptest.NewRequest(http.MethodDelete, "/api-keys/"+id, nil)
+		req.SetPathValue("id", id)
+		return req.WithContext(auth.WithUserID(req.Context(), userID))
+	}
+
+	t.Run("успешный отзыв", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().Revoke(gomock.Any(), userID, id).Return(nil)
+
+		w := httptest.NewRecorder()
+		handler.RevokeKey(w, request(id.String()))
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("ошибка: ключ не найден", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().Revoke(gomock.Any(), userID, id).Return(apikey.ErrKeyNotFound)
+
+		w := httptest.NewRecorder()
+		handler.RevokeKey(w, request(id.String()))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("ошибка: некорректный ID", func(t *testing.T) {
+		_, _, handler := setupHandlerTest(t)
+
+		w := httptest.NewRecorder()
+		handler.RevokeKey(w, request("abc"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,131 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/apikey/service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/apikey/service.go -destination=internal/apikey/mock/mock_repository_interface.go -package=mockapikey
+//
+
+// Package mockapikey is a generated GoMock package.
+package mockapikey
+
+import (
+	context "context"
+	apikey "marketplace-api/internal/apikey"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepositoryInterface is a mock of RepositoryInterface interface.
+type MockRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryInterfaceMockRecorder is the mock recorder for MockRepositoryInterface.
+type MockRepositoryInterfaceMockRecorder struct {
+	mock *MockRepositoryInterface
+}
+
+// NewMockRepositoryInterface creates a new mock instance.
+func NewMockRepositoryInterface(ctrl *gomock.Controller) *MockRepositoryInterface {
+	mock := &MockRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepositoryInterface) EXPECT() *MockRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CountActive mocks base method.
+func (m *MockRepositoryInterface) CountActive(ctx context.Context, userID uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountActive", ctx, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountActive indicates an expected call of CountActive.
+func (mr *MockRepositoryInterfaceMockRecorder) CountActive(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountActive", reflect.TypeOf((*MockRepositoryInterface)(nil).CountActive), ctx, userID)
+}
+
+// Create mocks base method.
+func (m *MockRepositoryInterface) Create(ctx context.Context, k *apikey.Key) (*apikey.Key, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, k)
+	ret0, _ := ret[0].(*apikey.Key)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRepositoryInterfaceMockRecorder) Create(ctx, k any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepositoryInterface)(nil).Create), ctx, k)
+}
+
+// GetByHash mocks base method.
+func (m *MockRepositoryInterface) GetByHash(ctx context.Context, hash string) (*apikey.Key, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByHash", ctx, hash)
+	ret0, _ := ret[0].(*apikey.Key)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByHash indicates an expected call of GetByHash.
+func (mr *MockRepositoryInterfaceMockRecorder) GetByHash(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByHash", reflect.TypeOf((*MockRepositoryInterface)(nil).GetByHash), ctx, hash)
+}
+
+// List mocks base method.
+func (m *MockRepositoryInterface) List(ctx context.Context, userID uuid.UUID) ([]apikey.Key, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID)
+	ret0, _ := ret[0].([]apikey.Key)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRepositoryInterfaceMockRecorder) List(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRepositoryInterface)(nil).List), ctx, userID)
+}
+
+// Revoke mocks base method.
+func (m *MockRepositoryInterface) Revoke(ctx context.Context, userID, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockRepositoryInterfaceMockRecorder) Revoke(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockRepositoryInterface)(nil).Revoke), ctx, userID, id)
+}
+
+// Touch mocks base method.
+func (m *MockRepositoryInterface) Touch(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockRepositoryInterfaceMockRecorder) Touch(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockRepositoryInterface)(nil).Touch), ctx, id)
+}
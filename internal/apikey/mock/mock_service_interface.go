@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/apikey/handler.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/apikey/handler.go -destination=internal/apikey/mock/mock_service_interface.go -package=mockapikey
+//
+
+// Package mockapikey is a generated GoMock package.
+package mockapikey
+
+import (
+	context "context"
+	apikey "marketplace-api/internal/apikey"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockServiceInterface is a mock of ServiceInterface interface.
+type MockServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockServiceInterfaceMockRecorder is the mock recorder for MockServiceInterface.
+type MockServiceInterfaceMockRecorder struct {
+	mock *MockServiceInterface
+}
+
+// NewMockServiceInterface creates a new mock instance.
+func NewMockServiceInterface(ctrl *gomock.Controller) *MockServiceInterface {
+	mock := &MockServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockServiceInterface) EXPECT() *MockServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockServiceInterface) Create(ctx context.Context, input *apikey.CreateKeyInput) (*apikey.Key, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, input)
+	ret0, _ := ret[0].(*apikey.Key)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockServiceInterfaceMockRecorder) Create(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockServiceInterface)(nil).Create), ctx, input)
+}
+
+// List mocks base method.
+func (m *MockServiceInterface) List(ctx context.Context, userID uuid.UUID) ([]apikey.Key, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID)
+	ret0, _ := ret[0].([]apikey.Key)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockServiceInterfaceMockRecorder) List(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockServiceInterface)(nil).List), ctx, userID)
+}
+
+// Revoke mocks base method.
+func (m *MockServiceInterface) Revoke(ctx context.Context, userID, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockServiceInterfaceMockRecorder) Revoke(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockServiceInterface)(nil).Revoke), ctx, userID, id)
+}
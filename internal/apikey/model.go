@@ -0,0 +1,29 @@
+package apikey
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Key - API-ключ для обращений к API с сервера партнёра вместо JWT
+type Key struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"-"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`        // начало ключа, чтобы узнать его в списке
+	Key        string     `json:"key,omitempty"` // сам ключ, возвращается только при создании
+	Hash       string     `json:"-"`             // SHA-256 ключа, ключ в открытом виде не хранится
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type CreateKeyInput struct {
+	UserID    uuid.UUID  `swaggerignore:"true"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // без срока ключ действует до отзыва
+}
@@ -0,0 +1,98 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"marketplace-api/internal/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAPIKeyRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+const keyColumns = `id, user_id, name, prefix, key_hash, scopes, expires_at, last_used_at, revoked_at, created_at`
+
+func scanKey(row pgx.Row, k *Key) error {
+	return row.Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.Hash, &k.Scopes, &k.ExpiresAt, &k.LastUsedAt, &k.RevokedAt, &k.CreatedAt)
+}
+
+func (r *Repository) Create(ctx context.Context, k *Key) (*Key, error) {
+	query := `
+		INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`
+	err := db.Conn(ctx, r.pool).QueryRow(ctx, query, k.UserID, k.Name, k.Prefix, k.Hash, k.Scopes, k.ExpiresAt).
+		Scan(&k.ID, &k.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// CountActive - число неотозванных и неистёкших ключей пользователя
+func (r *Repository) CountActive(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `
+		SELECT count(*) FROM api_keys
+		WHERE user_id = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > now())`
+	var n int
+	err := db.Conn(ctx, r.pool).QueryRow(ctx, query, userID).Scan(&n)
+	return n, err
+}
+
+func (r *Repository) List(ctx context.Context, userID uuid.UUID) ([]Key, error) {
+	query := `SELECT ` + keyColumns + ` FROM api_keys WHERE user_id = $1 AND revoked_at IS NULL ORDER BY created_at`
+	rows, err := db.Conn(ctx, r.pool).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []Key{}
+	for rows.Next() {
+		var k Key
+		if err := scanKey(rows, &k); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// Revoke - отзывает ключ пользователя, чужой или уже отозванный ключ не найден
+func (r *Repository) Revoke(ctx context.Context, userID, id uuid.UUID) error {
+	query := `UPDATE api_keys SET revoked_at = now() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+	tag, err := db.Conn(ctx, r.pool).Exec(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrKeyNotFound
+	}
+	return nil
+}
+
+// GetByHash - ключ по хэшу (или nil, если не найден)
+func (r *Repository) GetByHash(ctx context.Context, hash string) (*Key, error) {
+	var k Key
+	err := scanKey(db.Conn(ctx, r.pool).QueryRow(ctx, `SELECT `+keyColumns+` FROM api_keys WHERE key_hash = $1`, hash), &k)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+func (r *Repository) Touch(ctx context.Context, id uuid.UUID) error {
+	_, err := db.Conn(ctx, r.pool).Exec(ctx, `UPDATE api_keys SET last_used_at = now() WHERE id = $1`, id)
+	return err
+}
@@ -0,0 +1,172 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"marketplace-api/internal/auth"
+	"marketplace-api/internal/logging"
+	"marketplace-api/internal/metrics"
+	"marketplace-api/internal/tracing"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxKeysPerUser - сколько действующих ключей может быть у пользователя
+	MaxKeysPerUser = 20
+	maxNameLength  = 100
+	prefixLength   = len(auth.APIKeyPrefix) + 8
+	// lastUsedPrecision - время последнего использования обновляется не чаще, чтобы не
+	// писать в базу на каждый запрос
+	lastUsedPrecision = time.Minute
+)
+
+var (
+	ErrKeyNotFound   = errors.New("api key not found")
+	ErrInvalidKey    = errors.New("invalid, expired or revoked api key")
+	ErrNameRequired  = errors.New("name is required and must be at most 100 characters")
+	ErrNoScopes      = errors.New("at least one scope is required")
+	ErrUnknownScope  = errors.New("unknown scope")
+	ErrExpiresInPast = errors.New("expires_at must be in the future")
+	ErrTooManyKeys   = errors.New("too many api keys, revoke unused ones")
+)
+
+type RepositoryInterface interface {
+	Create(ctx context.Context, k *Key) (*Key, error)
+	CountActive(ctx context.Context, userID uuid.UUID) (int, error)
+	List(ctx context.Context, userID uuid.UUID) ([]Key, error)
+	Revoke(ctx context.Context, userID, id uuid.UUID) error
+	GetByHash(ctx context.Context, hash string) (*Key, error)
+	Touch(ctx context.Context, id uuid.UUID) error
+}
+
+type Service struct {
+	repo RepositoryInterface
+}
+
+func NewAPIKeyService(repo RepositoryInterface) *Service {
+	return &Service{repo: repo}
+}
+
+// Create - выпуск ключа. Ключ возвращается один раз, хранится только его хэш
+func (s *Service) Create(ctx context.Context, input *CreateKeyInput) (*Key, error) {
+	ctx, span := tracing.Start(ctx, "apikey.Service.Create")
+	defer span.End()
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" || len([]rune(name)) > maxNameLength {
+		return nil, ErrNameRequired
+	}
+	if len(input.Scopes) == 0 {
+		return nil, ErrNoScopes
+	}
+	scopes := make([]string, 0, len(input.Scopes))
+	for _, scope := range input.Scopes {
+		if !slices.Contains(auth.Scopes, scope) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownScope, scope)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return nil, ErrExpiresInPast
+	}
+
+	count, err := s.repo.CountActive(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= MaxKeysPerUser {
+		return nil, ErrTooManyKeys
+	}
+
+	secret, err := newKey()
+	if err != nil {
+		return nil, err
+	}
+	key, err := s.repo.Create(ctx, &Key{
+		UserID:    input.UserID,
+		Name:      name,
+		Prefix:    secret[:prefixLength],
+		Hash:      hash(secret),
+		Scopes:    scopes,
+		ExpiresAt: input.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	key.Key = secret
+	return key, nil
+}
+
+// List - действующие и истёкшие ключи пользователя, отозванные не показываются
+func (s *Service) List(ctx context.Context, userID uuid.UUID) ([]Key, error) {
+	ctx, span := tracing.Start(ctx, "apikey.Service.List")
+	defer span.End()
+
+	return s.repo.List(ctx, userID)
+}
+
+// Revoke - отзыв ключа, запросы с ним сразу перестают приниматься
+func (s *Service) Revoke(ctx context.Context, userID, id uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "apikey.Service.Revoke")
+	defer span.End()
+
+	return s.repo.Revoke(ctx, userID, id)
+}
+
+// Authenticate - владелец и разрешения ключа для auth.AuthMiddleware
+func (s *Service) Authenticate(ctx context.Context, secret string) (uuid.UUID, []string, error) {
+	ctx, span := tracing.Start(ctx, "apikey.Service.Authenticate")
+	defer span.End()
+
+	key, err := s.repo.GetByHash(ctx, hash(secret))
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+
+	now := time.Now()
+	switch {
+	case key == nil:
+		metrics.APIKeyRejected("unknown")
+		return uuid.Nil, nil, ErrInvalidKey
+	case key.RevokedAt != nil:
+		metrics.APIKeyRejected("revoked")
+		return uuid.Nil, nil, ErrInvalidKey
+	case key.ExpiresAt != nil && !key.ExpiresAt.After(now):
+		metrics.APIKeyRejected("expired")
+		return uuid.Nil, nil, ErrInvalidKey
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedPrecision {
+		// Неудачная отметка не мешает запросу
+		if err := s.repo.Touch(ctx, key.ID); err != nil {
+			logging.FromContext(ctx).Warn("error updating api key last use", "key_id", key.ID, "error", err)
+		}
+	}
+	return key.UserID, key.Scopes, nil
+}
+
+// newKey - случайный ключ с префиксом auth.APIKeyPrefix
+func newKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return auth.APIKeyPrefix + hex.EncodeToString(b), nil
+}
+
+// hash - ключ случаен и длинен, поэтому достаточно быстрого хэша без соли
+func hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
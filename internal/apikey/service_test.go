@@ -0,0 +1,171 @@
+package apikey_test
+
+import (
+	"context"
+	"errors"
+	"marketplace-api/internal/apikey"
+	mockapikey "marketplace-api/internal/apikey/mock"
+	"marketplace-api/internal/auth"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func setupTest(t *testing.T) (*gomock.Controller, *mockapikey.MockRepositoryInterface, *apikey.Service) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	mockRepo := mockapikey.NewMockRepositoryInterface(ctrl)
+	service := apikey.NewAPIKeyService(mockRepo)
+
+	return ctrl, mockRepo, service
+}
+
+func TestService_Create(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("успешный выпуск", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		var stored *apikey.Key
+		mockRepo.EXPECT().CountActive(gomock.Any(), userID).Return(0, nil)
+		mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, k *apikey.Key) (*apikey.Key, error) {
+				stored = k
+				assert.Equal(t, "import bot", k.Name)
+				assert.Equal(t, []string{auth.ScopeAdsWrite}, k.Scopes)
+				assert.Len(t, k.Hash, 64)
+				assert.Empty(t, k.Key)
+				return k, nil
+			})
+
+		key, err := service.Create(context.Background(), &apikey.CreateKeyInput{
+			UserID: userID, Name: " import bot ", Scopes: []string{auth.ScopeAdsWrite, auth.ScopeAdsWrite},
+		})
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(key.Key, auth.APIKeyPrefix))
+		assert.True(t, strings.HasPrefix(key.Key, stored.Prefix))
+		assert.NotContains(t, stored.Hash, key.Key)
+	})
+
+	t.Run("валидация", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+		for name, tc := range map[string]struct {
+			input apikey.CreateKeyInput
+			err   error
+		}{
+			"без названия":           {apikey.CreateKeyInput{Scopes: []string{auth.ScopeAdsRead}}, apikey.ErrNameRequired},
+			"без разрешений":         {apikey.CreateKeyInput{Name: "bot"}, apikey.ErrNoScopes},
+			"неизвестное разрешение": {apikey.CreateKeyInput{Name: "bot", Scopes: []string{"admin"}}, apikey.ErrUnknownScope},
+			"срок в прошлом":         {apikey.CreateKeyInput{Name: "bot", Scopes: []string{auth.ScopeAdsRead}, ExpiresAt: &past}, apikey.ErrExpiresInPast},
+		} {
+			t.Run(name, func(t *testing.T) {
+				ctrl, _, service := setupTest(t)
+				defer ctrl.Finish()
+
+				tc.input.UserID = userID
+				_, err := service.Create(context.Background(), &tc.input)
+				assert.ErrorIs(t, err, tc.err)
+			})
+		}
+	})
+
+	t.Run("ошибка: слишком много ключей", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().CountActive(gomock.Any(), userID).Return(apikey.MaxKeysPerUser, nil)
+
+		_, err := service.Create(context.Background(), &apikey.CreateKeyInput{UserID: userID, Name: "bot", Scopes: []string{auth.ScopeAdsRead}})
+		assert.ErrorIs(t, err, apikey.ErrTooManyKeys)
+	})
+}
+
+func TestService_Authenticate(t *testing.T) {
+	userID := uuid.New()
+	scopes := []string{auth.ScopeAdsRead}
+
+	// issue - выпускает ключ и возвращает его вместе с сохранённой записью
+	issue := func(t *testing.T, service *apikey.Service, mockRepo *mockapikey.MockRepositoryInterface) (string, apikey.Key) {
+		t.Helper()
+		var stored apikey.Key
+		mockRepo.EXPECT().CountActive(gomock.Any(), userID).Return(0, nil)
+		mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, k *apikey.Key) (*apikey.Key, error) {
+				k.ID = uuid.New()
+				stored = *k
+				return k, nil
+			})
+		key, err := service.Create(context.Background(), &apikey.CreateKeyInput{UserID: userID, Name: "bot", Scopes: scopes})
+		require.NoError(t, err)
+		return key.Key, stored
+	}
+
+	t.Run("действующий ключ отмечается использованным", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		secret, stored := issue(t, service, mockRepo)
+		mockRepo.EXPECT().GetByHash(gomock.Any(), stored.Hash).Return(&stored, nil)
+		mockRepo.EXPECT().Touch(gomock.Any(), stored.ID).Return(nil)
+
+		gotUser, gotScopes, err := service.Authenticate(context.Background(), secret)
+		require.NoError(t, err)
+		assert.Equal(t, userID, gotUser)
+		assert.Equal(t, scopes, gotScopes)
+	})
+
+	t.Run("недавнее использование не обновляется", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		secret, stored := issue(t, service, mockRepo)
+		recently := time.Now().Add(-10 * time.Second)
+		stored.LastUsedAt = &recently
+		mockRepo.EXPECT().GetByHash(gomock.Any(), stored.Hash).Return(&stored, nil)
+
+		_, _, err := service.Authenticate(context.Background(), secret)
+		assert.NoError(t, err)
+	})
+
+	t.Run("ошибка отметки не мешает запросу", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		secret, stored := issue(t, service, mockRepo)
+		mockRepo.EXPECT().GetByHash(gomock.Any(), stored.Hash).Return(&stored, nil)
+		mockRepo.EXPECT().Touch(gomock.Any(), stored.ID).Return(errors.New("db down"))
+
+		_, _, err := service.Authenticate(context.Background(), secret)
+		assert.NoError(t, err)
+	})
+
+	t.Run("ошибка: отозванный, истёкший и неизвестный ключи", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		secret, stored := issue(t, service, mockRepo)
+		past := time.Now().Add(-time.Minute)
+
+		revoked := stored
+		revoked.RevokedAt = &past
+		expired := stored
+		expired.ExpiresAt = &past
+		gomock.InOrder(
+			mockRepo.EXPECT().GetByHash(gomock.Any(), stored.Hash).Return(&revoked, nil),
+			mockRepo.EXPECT().GetByHash(gomock.Any(), stored.Hash).Return(&expired, nil),
+			mockRepo.EXPECT().GetByHash(gomock.Any(), stored.Hash).Return(nil, nil),
+		)
+
+		for range 3 {
+			_, _, err := service.Authenticate(context.Background(), secret)
+			assert.ErrorIs(t, err, apikey.ErrInvalidKey)
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package auction
+
+import (
+	"context"
+	"marketplace-api/internal/outbox"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AggregateType - события аукциона относятся к объявлению и доставляются по порядку с его событиями
+const AggregateType = "advertisement"
+
+// EventSold - торги завершены с победителем
+const EventSold = "advertisement.sold"
+
+// SoldEvent - данные события EventSold
+type SoldEvent struct {
+	AdvertisementID uuid.UUID `json:"advertisement_id"`
+	SellerID        uuid.UUID `json:"seller_id"`
+	WinnerID        uuid.UUID `json:"winner_id"`
+	PriceKopecks    int       `json:"price_kopecks"`
+	SoldAt          time.Time `json:"sold_at"`
+}
+
+// recordSold - сохраняет событие о продаже по итогам торгов a
+func (s *Service) recordSold(ctx context.Context, a *Auction) error {
+	payload := SoldEvent{
+		AdvertisementID: a.AdvertisementID,
+		SellerID:        a.SellerID,
+		PriceKopecks:    a.CurrentPriceKopecks,
+	}
+	if a.WinnerID != nil {
+		payload.WinnerID = *a.WinnerID
+	}
+	if a.ClosedAt != nil {
+		payload.SoldAt = *a.ClosedAt
+	}
+	event, err := outbox.New(EventSold, AggregateType, a.AdvertisementID, payload)
+	if err != nil {
+		return err
+	}
+	return s.events.Record(ctx, event)
+}
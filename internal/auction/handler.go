@@ -0,0 +1,132 @@
+package auction
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"marketplace-api/internal/auth"
+	"marketplace-api/internal/logging"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+type ServiceInterface interface {
+	Get(ctx context.Context, advertisementID uuid.UUID) (*Auction, error)
+	PlaceBid(ctx context.Context, input *PlaceBidInput) (*BidResult, error)
+}
+
+type Handler struct {
+	service ServiceInterface
+}
+
+func NewAuctionHandler(service ServiceInterface) *Handler {
+	return &Handler{service: service}
+}
+
+// GetAuction godoc
+// @Summary Получить состояние аукциона
+// @Description Возвращает текущую цену, количество ставок, время окончания и победителя аукциона
+// @Tags auction
+// @Produce json
+// @Param id path string true "ID объявления"
+// @Success 200 {object} Auction
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Аукцион не найден"
+// @Failure 405 {string} string "Метод не разрешён"
+// @Router /api/v1/auction/{id} [get]
+func (h *Handler) GetAuction(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "id must be a valid UUID", http.StatusBadRequest)
+		return
+	}
+
+	a, err := h.service.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(a)
+}
+
+// PlaceBid godoc
+// @Summary Сделать ставку
+// @Description Размещает ставку авторизованного пользователя на аукционе
+// @Tags auction
+// @Accept json
+// @Produce json
+// @Param input body PlaceBidInput true "Ставка"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ"
+// @Success 201 {object} BidResult
+// @Failure 400 {string} string "Неверный ввод или слишком низкая ставка"
+// @Failure 401 {string} string "Пользователь не авторизован"
+// @Failure 403 {string} string "Ставка на собственный аукцион"
+// @Failure 404 {string} string "Аукцион не найден"
+// @Failure 405 {string} string "Метод не разрешён"
+// @Failure 409 {string} string "Аукцион завершён"
+// @Security AuthToken
+// @Router /api/v1/auction/bid [post]
+func (h *Handler) PlaceBid(w http.ResponseWriter, r *http.Request) {
+	//Получения ID авторизованного пользователя
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var input PlaceBidInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "invalid input", http.StatusBadRequest)
+		return
+	}
+
+	// Проверка обязательных полей
+	if input.AdvertisementID == uuid.Nil || input.AmountKopecks == 0 {
+		http.Error(w, "all fields are required", http.StatusBadRequest)
+		return
+	}
+
+	//Вызов сервиса
+	input.BidderID = userID
+	result, err := h.service.PlaceBid(r.Context(), &input)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
+
+// errorStatus сопоставляет ошибку сервиса с HTTP-статусом
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrAuctionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrOwnAuction):
+		return http.StatusForbidden
+	case errors.Is(err, ErrAuctionClosed):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidBid), errors.Is(err, ErrBidTooLow):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeError - ответ с ошибкой сервиса. Непредвиденная ошибка пишется в лог, а клиент
+// получает общее сообщение без подробностей устройства сервиса
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status := errorStatus(err)
+	if status == http.StatusInternalServerError {
+		logging.FromContext(r.Context()).Error("request failed", "error", err)
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	http.Error(w, err.Error(), status)
+}
@@ -0,0 +1,150 @@
+package auction_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"marketplace-api/internal/auction"
+	mockauction "marketplace-api/internal/auction/mock"
+	"marketplace-api/internal/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func setupHandlerTest(t *testing.T) (*gomock.Controller, *mockauction.MockServiceInterface, *auction.Handler) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockService := mockauction.NewMockServiceInterface(ctrl)
+	handler := auction.NewAuctionHandler(mockService)
+	return ctrl, mockService, handler
+}
+
+func TestHandler_PlaceBid(t *testing.T) {
+	userID := uuid.New()
+	input := auction.PlaceBidInput{AdvertisementID: uuid.New(), AmountKopecks: 1500}
+
+	t.Run("успешная ставка", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().PlaceBid(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, in *auction.PlaceBidInput) (*auction.BidResult, error) {
+				assert.Equal(t, userID, in.BidderID)
+				return &auction.BidResult{Bid: &auction.Bid{AmountKopecks: in.AmountKopecks}, Auction: &auction.Auction{}}, nil
+			})
+
+		body, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/auction/bid", bytes.NewReader(body))
+		req = req.WithContext(auth.WithUserID(req.Context(), userID))
+		w := httptest.NewRecorder()
+
+		handler.PlaceBid(w, req)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("ошибка: неавторизован", func(t *testing.T) {
+		_, _, handler := setupHandlerTest(t)
+
+		body, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/auction/bid", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		handler.PlaceBid(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("ошибка: аукцион завершён", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().PlaceBid(gomock.Any(), gomock.Any()).Return(nil, auction.ErrAuctionClosed)
+
+		body, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/auction/bid", bytes.NewReader(body))
+		req = req.WithContext(auth.WithUserID(req.Context(), userID))
+		w := httptest.NewRecorder()
+
+		handler.PlaceBid(w, req)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "auction is closed")
+	})
+
+	t.Run("ошибка: слишком низкая ставка", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().PlaceBid(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("%w: minimum bid is 2000", auction.ErrBidTooLow))
+
+		body, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/auction/bid", bytes.NewReader(body))
+		req = req.WithContext(auth.WithUserID(req.Context(), userID))
+		w := httptest.NewRecorder()
+
+		handler.PlaceBid(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "minimum bid is 2000")
+	})
+
+	t.Run("ошибка: непредвиденная ошибка сервиса", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().PlaceBid(gomock.Any(), gomock.Any()).Return(nil, errors.New("deadlock detected"))
+
+		body, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/auction/bid", bytes.NewReader(body))
+		req = req.WithContext(auth.WithUserID(req.Context(), userID))
+		w := httptest.NewRecorder()
+
+		handler.PlaceBid(w, req)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.NotContains(t, w.Body.String(), "deadlock")
+	})
+
+	t.Run("ошибка: пустые поля", func(t *testing.T) {
+		_, _, handler := setupHandlerTest(t)
+
+		req := httptest.NewRequest(http.MethodPost, "/auction/bid", bytes.NewReader([]byte(`{}`)))
+		req = req.WithContext(auth.WithUserID(req.Context(), userID))
+		w := httptest.NewRecorder()
+
+		handler.PlaceBid(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "all fields are required")
+	})
+}
+
+func TestHandler_GetAuction(t *testing.T) {
+	t.Run("ошибка: невалидный id", func(t *testing.T) {
+		_, _, handler := setupHandlerTest(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/auction/bad", nil)
+		req.SetPathValue("id", "bad")
+		w := httptest.NewRecorder()
+
+		handler.GetAuction(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("ошибка: не найден", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, auction.ErrAuctionNotFound)
+
+		id := uuid.NewString()
+		req := httptest.NewRequest(http.MethodGet, "/auction/"+id, nil)
+		req.SetPathValue("id", id)
+		w := httptest.NewRecorder()
+
+		handler.GetAuction(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,200 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/auction/service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/auction/service.go -destination=internal/auction/mock/mock_repository_interface.go -package=mockauction
+//
+
+// Package mockauction is a generated GoMock package.
+package mockauction
+
+import (
+	context "context"
+	auction "marketplace-api/internal/auction"
+	outbox "marketplace-api/internal/outbox"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepositoryInterface is a mock of RepositoryInterface interface.
+type MockRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryInterfaceMockRecorder is the mock recorder for MockRepositoryInterface.
+type MockRepositoryInterfaceMockRecorder struct {
+	mock *MockRepositoryInterface
+}
+
+// NewMockRepositoryInterface creates a new mock instance.
+func NewMockRepositoryInterface(ctrl *gomock.Controller) *MockRepositoryInterface {
+	mock := &MockRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepositoryInterface) EXPECT() *MockRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CloseDue mocks base method.
+func (m *MockRepositoryInterface) CloseDue(ctx context.Context, limit int, decide func(*auction.Auction)) ([]auction.Auction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseDue", ctx, limit, decide)
+	ret0, _ := ret[0].([]auction.Auction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseDue indicates an expected call of CloseDue.
+func (mr *MockRepositoryInterfaceMockRecorder) CloseDue(ctx, limit, decide any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseDue", reflect.TypeOf((*MockRepositoryInterface)(nil).CloseDue), ctx, limit, decide)
+}
+
+// CloseListing mocks base method.
+func (m *MockRepositoryInterface) CloseListing(ctx context.Context, advertisementID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseListing", ctx, advertisementID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseListing indicates an expected call of CloseListing.
+func (mr *MockRepositoryInterfaceMockRecorder) CloseListing(ctx, advertisementID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseListing", reflect.TypeOf((*MockRepositoryInterface)(nil).CloseListing), ctx, advertisementID)
+}
+
+// ExtendListing mocks base method.
+func (m *MockRepositoryInterface) ExtendListing(ctx context.Context, advertisementID uuid.UUID, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendListing", ctx, advertisementID, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExtendListing indicates an expected call of ExtendListing.
+func (mr *MockRepositoryInterfaceMockRecorder) ExtendListing(ctx, advertisementID, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendListing", reflect.TypeOf((*MockRepositoryInterface)(nil).ExtendListing), ctx, advertisementID, until)
+}
+
+// Get mocks base method.
+func (m *MockRepositoryInterface) Get(ctx context.Context, advertisementID uuid.UUID) (*auction.Auction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, advertisementID)
+	ret0, _ := ret[0].(*auction.Auction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRepositoryInterfaceMockRecorder) Get(ctx, advertisementID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRepositoryInterface)(nil).Get), ctx, advertisementID)
+}
+
+// PlaceBid mocks base method.
+func (m *MockRepositoryInterface) PlaceBid(ctx context.Context, advertisementID uuid.UUID, apply func(*auction.Auction) (*auction.Bid, error)) (*auction.Auction, *auction.Bid, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlaceBid", ctx, advertisementID, apply)
+	ret0, _ := ret[0].(*auction.Auction)
+	ret1, _ := ret[1].(*auction.Bid)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PlaceBid indicates an expected call of PlaceBid.
+func (mr *MockRepositoryInterfaceMockRecorder) PlaceBid(ctx, advertisementID, apply any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceBid", reflect.TypeOf((*MockRepositoryInterface)(nil).PlaceBid), ctx, advertisementID, apply)
+}
+
+// MockTransactor is a mock of Transactor interface.
+type MockTransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactorMockRecorder
+	isgomock struct{}
+}
+
+// MockTransactorMockRecorder is the mock recorder for MockTransactor.
+type MockTransactorMockRecorder struct {
+	mock *MockTransactor
+}
+
+// NewMockTransactor creates a new mock instance.
+func NewMockTransactor(ctrl *gomock.Controller) *MockTransactor {
+	mock := &MockTransactor{ctrl: ctrl}
+	mock.recorder = &MockTransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactor) EXPECT() *MockTransactorMockRecorder {
+	return m.recorder
+}
+
+// WithinTx mocks base method.
+func (m *MockTransactor) WithinTx(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTx indicates an expected call of WithinTx.
+func (mr *MockTransactorMockRecorder) WithinTx(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTx", reflect.TypeOf((*MockTransactor)(nil).WithinTx), ctx, fn)
+}
+
+// MockEventRecorder is a mock of EventRecorder interface.
+type MockEventRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventRecorderMockRecorder
+	isgomock struct{}
+}
+
+// MockEventRecorderMockRecorder is the mock recorder for MockEventRecorder.
+type MockEventRecorderMockRecorder struct {
+	mock *MockEventRecorder
+}
+
+// NewMockEventRecorder creates a new mock instance.
+func NewMockEventRecorder(ctrl *gomock.Controller) *MockEventRecorder {
+	mock := &MockEventRecorder{ctrl: ctrl}
+	mock.recorder = &MockEventRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventRecorder) EXPECT() *MockEventRecorderMockRecorder {
+	return m.recorder
+}
+
+// Record mocks base method.
+func (m *MockEventRecorder) Record(ctx context.Context, events ...outbox.Event) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Record", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockEventRecorderMockRecorder) Record(ctx any, events ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockEventRecorder)(nil).Record), varargs...)
+}
@@ -0,0 +1,73 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/auction/handler.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/auction/handler.go -destination=internal/auction/mock/mock_service_interface.go -package=mockauction
+//
+
+// Package mockauction is a generated GoMock package.
+package mockauction
+
+import (
+	context "context"
+	auction "marketplace-api/internal/auction"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockServiceInterface is a mock of ServiceInterface interface.
+type MockServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockServiceInterfaceMockRecorder is the mock recorder for MockServiceInterface.
+type MockServiceInterfaceMockRecorder struct {
+	mock *MockServiceInterface
+}
+
+// NewMockServiceInterface creates a new mock instance.
+func NewMockServiceInterface(ctrl *gomock.Controller) *MockServiceInterface {
+	mock := &MockServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockServiceInterface) EXPECT() *MockServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockServiceInterface) Get(ctx context.Context, advertisementID uuid.UUID) (*auction.Auction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, advertisementID)
+	ret0, _ := ret[0].(*auction.Auction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockServiceInterfaceMockRecorder) Get(ctx, advertisementID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockServiceInterface)(nil).Get), ctx, advertisementID)
+}
+
+// PlaceBid mocks base method.
+func (m *MockServiceInterface) PlaceBid(ctx context.Context, input *auction.PlaceBidInput) (*auction.BidResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlaceBid", ctx, input)
+	ret0, _ := ret[0].(*auction.BidResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceBid indicates an expected call of PlaceBid.
+func (mr *MockServiceInterfaceMockRecorder) PlaceBid(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceBid", reflect.TypeOf((*MockServiceInterface)(nil).PlaceBid), ctx, input)
+}
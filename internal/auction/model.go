@@ -0,0 +1,66 @@
+package auction
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Статусы аукциона
+const (
+	StatusOpen   = "open"   // идут торги
+	StatusSold   = "sold"   // торги завершены, определён победитель
+	StatusUnsold = "unsold" // торги завершены без победителя (нет ставок или не достигнута резервная цена)
+)
+
+type Auction struct {
+	AdvertisementID        uuid.UUID  `json:"advertisement_id"`
+	SellerID               uuid.UUID  `json:"seller_id"`
+	StartingPriceKopecks   int        `json:"starting_price_kopecks"`
+	CurrentPriceKopecks    int        `json:"current_price_kopecks"`
+	MinBidIncrementKopecks int        `json:"min_bid_increment_kopecks"`
+	BuyNowPriceKopecks     *int       `json:"buy_now_price_kopecks,omitempty"`
+	ReservePriceKopecks    *int       `json:"-"`           // резервная цена не раскрывается участникам
+	ReserveMet             bool       `json:"reserve_met"` // достигнута ли резервная цена
+	HighestBidderID        *uuid.UUID `json:"highest_bidder_id,omitempty"`
+	BidsCount              int        `json:"bids_count"`
+	EndsAt                 time.Time  `json:"ends_at"`
+	Status                 string     `json:"status"`
+	WinnerID               *uuid.UUID `json:"winner_id,omitempty"`
+	ClosedAt               *time.Time `json:"closed_at,omitempty"`
+}
+
+type Bid struct {
+	ID              uuid.UUID `json:"id"`
+	AdvertisementID uuid.UUID `json:"advertisement_id"`
+	BidderID        uuid.UUID `json:"bidder_id"`
+	AmountKopecks   int       `json:"amount_kopecks"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type PlaceBidInput struct {
+	AdvertisementID uuid.UUID `json:"advertisement_id"`
+	BidderID        uuid.UUID `swaggerignore:"true"`
+	AmountKopecks   int       `json:"amount_kopecks"`
+}
+
+type BidResult struct {
+	Bid     *Bid     `json:"bid"`
+	Auction *Auction `json:"auction"`
+}
+
+// minNextBid - минимально допустимая сумма следующей ставки
+func (a *Auction) minNextBid() int {
+	if a.BidsCount == 0 {
+		return a.StartingPriceKopecks
+	}
+	return a.CurrentPriceKopecks + a.MinBidIncrementKopecks
+}
+
+// reserveMet - есть ли ставка не ниже резервной цены
+func (a *Auction) reserveMet() bool {
+	if a.BidsCount == 0 {
+		return false
+	}
+	return a.ReservePriceKopecks == nil || a.CurrentPriceKopecks >= *a.ReservePriceKopecks
+}
@@ -0,0 +1,197 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"marketplace-api/internal/db"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const selectAuction = `
+	SELECT
+		au.advertisement_id,
+		a.author_id,
+		a.price_kopecks,
+		au.current_price_kopecks,
+		au.min_bid_increment_kopecks,
+		au.buy_now_price_kopecks,
+		au.reserve_price_kopecks,
+		au.highest_bidder_id,
+		au.bids_count,
+		au.ends_at,
+		au.status,
+		au.winner_id,
+		au.closed_at
+	FROM auctions au
+	JOIN advertisements a ON a.id = au.advertisement_id
+`
+
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuctionRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+// Get - возвращает аукцион по ID объявления (или nil, если не найден)
+func (r *Repository) Get(ctx context.Context, advertisementID uuid.UUID) (*Auction, error) {
+	row := db.Conn(ctx, r.pool).QueryRow(ctx, selectAuction+` WHERE au.advertisement_id = $1`, advertisementID)
+	a, err := scanAuction(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// PlaceBid - блокирует строку аукциона, передаёт её в apply для проверки и изменения,
+// затем сохраняет ставку и новое состояние аукциона в той же транзакции.
+// Блокировка FOR UPDATE гарантирует, что конкурентные ставки проверяются по очереди
+func (r *Repository) PlaceBid(ctx context.Context, advertisementID uuid.UUID, apply func(a *Auction) (*Bid, error)) (*Auction, *Bid, error) {
+	tx, err := db.Conn(ctx, r.pool).Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	row := tx.QueryRow(ctx, selectAuction+` WHERE au.advertisement_id = $1 FOR UPDATE OF au`, advertisementID)
+	a, err := scanAuction(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, ErrAuctionNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bid, err := apply(a)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bidQuery := `
+		INSERT INTO bids (advertisement_id, bidder_id, amount_kopecks)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+	err = tx.QueryRow(ctx, bidQuery, bid.AdvertisementID, bid.BidderID, bid.AmountKopecks).Scan(&bid.ID, &bid.CreatedAt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := updateAuction(ctx, tx, a); err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, err
+	}
+	return a, bid, nil
+}
+
+// CloseDue - выбирает до limit завершившихся открытых аукционов, передаёт каждый в decide
+// и сохраняет результат. SKIP LOCKED позволяет нескольким экземплярам работать параллельно
+func (r *Repository) CloseDue(ctx context.Context, limit int, decide func(a *Auction)) ([]Auction, error) {
+	tx, err := db.Conn(ctx, r.pool).Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := selectAuction + `
+		WHERE au.status = 'open' AND au.ends_at <= now()
+		ORDER BY au.ends_at
+		LIMIT $1
+		FOR UPDATE OF au SKIP LOCKED`
+
+	rows, err := tx.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var auctions []Auction
+	for rows.Next() {
+		a, err := scanAuction(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		auctions = append(auctions, *a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range auctions {
+		decide(&auctions[i])
+		if err := updateAuction(ctx, tx, &auctions[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return auctions, nil
+}
+
+// ExtendListing - продлевает размещение объявления до until, если оно заканчивается раньше
+func (r *Repository) ExtendListing(ctx context.Context, advertisementID uuid.UUID, until time.Time) error {
+	query := `UPDATE advertisements SET expires_at = $2 WHERE id = $1 AND expires_at < $2`
+	_, err := db.Conn(ctx, r.pool).Exec(ctx, query, advertisementID, until)
+	return err
+}
+
+// CloseListing - снимает объявление завершённого аукциона с публикации без права продления
+func (r *Repository) CloseListing(ctx context.Context, advertisementID uuid.UUID) error {
+	query := `UPDATE advertisements SET status = 'closed' WHERE id = $1`
+	_, err := db.Conn(ctx, r.pool).Exec(ctx, query, advertisementID)
+	return err
+}
+
+func updateAuction(ctx context.Context, tx pgx.Tx, a *Auction) error {
+	query := `
+		UPDATE auctions
+		SET current_price_kopecks = $2,
+			highest_bidder_id = $3,
+			bids_count = $4,
+			ends_at = $5,
+			status = $6,
+			winner_id = $7,
+			closed_at = $8
+		WHERE advertisement_id = $1
+	`
+	_, err := tx.Exec(ctx, query, a.AdvertisementID, a.CurrentPriceKopecks, a.HighestBidderID, a.BidsCount,
+		a.EndsAt, a.Status, a.WinnerID, a.ClosedAt)
+	return err
+}
+
+func scanAuction(row pgx.Row) (*Auction, error) {
+	var a Auction
+	err := row.Scan(
+		&a.AdvertisementID,
+		&a.SellerID,
+		&a.StartingPriceKopecks,
+		&a.CurrentPriceKopecks,
+		&a.MinBidIncrementKopecks,
+		&a.BuyNowPriceKopecks,
+		&a.ReservePriceKopecks,
+		&a.HighestBidderID,
+		&a.BidsCount,
+		&a.EndsAt,
+		&a.Status,
+		&a.WinnerID,
+		&a.ClosedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
@@ -0,0 +1,198 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"marketplace-api/internal/outbox"
+	"marketplace-api/internal/tracing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAuctionNotFound = errors.New("auction not found")
+	ErrAuctionClosed   = errors.New("auction is closed")
+	ErrOwnAuction      = errors.New("seller cannot bid on own auction")
+	ErrBidTooLow       = errors.New("bid is too low")
+	ErrInvalidBid      = errors.New("invalid bid amount: must be higher than 0")
+)
+
+type RepositoryInterface interface {
+	Get(ctx context.Context, advertisementID uuid.UUID) (*Auction, error)
+	PlaceBid(ctx context.Context, advertisementID uuid.UUID, apply func(a *Auction) (*Bid, error)) (*Auction, *Bid, error)
+	CloseDue(ctx context.Context, limit int, decide func(a *Auction)) ([]Auction, error)
+	ExtendListing(ctx context.Context, advertisementID uuid.UUID, until time.Time) error
+	CloseListing(ctx context.Context, advertisementID uuid.UUID) error
+}
+
+// AntiSniping - защита от ставок в последний момент:
+// ставка, сделанная менее чем за Window до окончания, продлевает торги до now + Extension
+type AntiSniping struct {
+	Window    time.Duration
+	Extension time.Duration
+}
+
+// Transactor - выполнение нескольких обращений к репозиториям в одной транзакции
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// EventRecorder - сохранение событий в одной транзакции с изменением
+type EventRecorder interface {
+	Record(ctx context.Context, events ...outbox.Event) error
+}
+
+type Service struct {
+	repo        RepositoryInterface
+	tx          Transactor
+	events      EventRecorder
+	antiSniping AntiSniping
+}
+
+func NewAuctionService(repo RepositoryInterface, tx Transactor, events EventRecorder, antiSniping AntiSniping) *Service {
+	return &Service{repo: repo, tx: tx, events: events, antiSniping: antiSniping}
+}
+
+// Get - получение аукциона по ID объявления
+func (s *Service) Get(ctx context.Context, advertisementID uuid.UUID) (*Auction, error) {
+	ctx, span := tracing.Start(ctx, "auction.Service.Get")
+	defer span.End()
+
+	a, err := s.repo.Get(ctx, advertisementID)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, ErrAuctionNotFound
+	}
+	a.ReserveMet = a.reserveMet()
+	return a, nil
+}
+
+// PlaceBid - размещение ставки
+func (s *Service) PlaceBid(ctx context.Context, input *PlaceBidInput) (*BidResult, error) {
+	ctx, span := tracing.Start(ctx, "auction.Service.PlaceBid")
+	defer span.End()
+
+	if input.AmountKopecks <= 0 {
+		return nil, ErrInvalidBid
+	}
+
+	// Ставка по цене "купить сейчас" завершает торги - объявление снимается с публикации,
+	// а событие о продаже сохраняется вместе со ставкой.
+	// Продлённые торги продлевают и размещение объявления, иначе оно скроется из ленты до окончания торгов
+	var a *Auction
+	var bid *Bid
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var endsAt time.Time
+		var err error
+		a, bid, err = s.repo.PlaceBid(ctx, input.AdvertisementID, func(a *Auction) (*Bid, error) {
+			endsAt = a.EndsAt
+			return s.applyBid(a, input, time.Now())
+		})
+		if err != nil {
+			return err
+		}
+		if a.EndsAt.After(endsAt) {
+			if err := s.repo.ExtendListing(ctx, a.AdvertisementID, a.EndsAt); err != nil {
+				return err
+			}
+		}
+		if a.Status == StatusSold {
+			if err := s.repo.CloseListing(ctx, a.AdvertisementID); err != nil {
+				return err
+			}
+			return s.recordSold(ctx, a)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	a.ReserveMet = a.reserveMet()
+	return &BidResult{Bid: bid, Auction: a}, nil
+}
+
+// applyBid проверяет ставку по текущему (заблокированному) состоянию аукциона и применяет её
+func (s *Service) applyBid(a *Auction, input *PlaceBidInput, now time.Time) (*Bid, error) {
+	if a.Status != StatusOpen || !now.Before(a.EndsAt) {
+		return nil, ErrAuctionClosed
+	}
+	if a.SellerID == input.BidderID {
+		return nil, ErrOwnAuction
+	}
+
+	amount := input.AmountKopecks
+	if minBid := a.minNextBid(); amount < minBid {
+		return nil, fmt.Errorf("%w: minimum bid is %d", ErrBidTooLow, minBid)
+	}
+
+	bidderID := input.BidderID
+	a.CurrentPriceKopecks = amount
+	a.HighestBidderID = &bidderID
+	a.BidsCount++
+
+	switch {
+	case a.BuyNowPriceKopecks != nil && amount >= *a.BuyNowPriceKopecks:
+		// Цена "купить сейчас" достигнута - торги завершаются сразу
+		a.CurrentPriceKopecks = *a.BuyNowPriceKopecks
+		a.Status = StatusSold
+		a.WinnerID = &bidderID
+		a.EndsAt = now
+		a.ClosedAt = &now
+	case s.antiSniping.Extension > 0 && a.EndsAt.Sub(now) < s.antiSniping.Window:
+		if extended := now.Add(s.antiSniping.Extension); extended.After(a.EndsAt) {
+			a.EndsAt = extended
+		}
+	}
+
+	return &Bid{
+		AdvertisementID: a.AdvertisementID,
+		BidderID:        bidderID,
+		AmountKopecks:   a.CurrentPriceKopecks,
+	}, nil
+}
+
+// CloseDue - закрытие завершившихся аукционов с определением победителя.
+// Объявления закрытых аукционов снимаются с публикации в той же транзакции
+func (s *Service) CloseDue(ctx context.Context, limit int) ([]Auction, error) {
+	ctx, span := tracing.Start(ctx, "auction.Service.CloseDue")
+	defer span.End()
+
+	var closed []Auction
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		closed, err = s.repo.CloseDue(ctx, limit, func(a *Auction) {
+			now := time.Now()
+			a.ClosedAt = &now
+			a.ReserveMet = a.reserveMet()
+			if a.ReserveMet {
+				a.Status = StatusSold
+				a.WinnerID = a.HighestBidderID
+				return
+			}
+			a.Status = StatusUnsold
+		})
+		if err != nil {
+			return err
+		}
+		for i := range closed {
+			if err := s.repo.CloseListing(ctx, closed[i].AdvertisementID); err != nil {
+				return err
+			}
+			if closed[i].Status == StatusSold {
+				if err := s.recordSold(ctx, &closed[i]); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return closed, nil
+}
@@ -0,0 +1,310 @@
+package auction_test
+
+import (
+	"context"
+	"errors"
+	"marketplace-api/internal/auction"
+	mockauction "marketplace-api/internal/auction/mock"
+	"marketplace-api/internal/outbox"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var antiSniping = auction.AntiSniping{Window: 5 * time.Minute, Extension: 5 * time.Minute}
+
+// noTx - выполняет функцию без транзакции
+type noTx struct{}
+
+func (noTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// eventLog - сохранённые сервисом события
+type eventLog struct {
+	events []outbox.Event
+}
+
+func (l *eventLog) Record(_ context.Context, events ...outbox.Event) error {
+	l.events = append(l.events, events...)
+	return nil
+}
+
+func setupTest(t *testing.T) (*gomock.Controller, *mockauction.MockRepositoryInterface, *auction.Service) {
+	t.Helper()
+	ctrl, mockRepo, _, service := setupEventsTest(t)
+	return ctrl, mockRepo, service
+}
+
+func setupEventsTest(t *testing.T) (*gomock.Controller, *mockauction.MockRepositoryInterface, *eventLog, *auction.Service) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	mockRepo := mockauction.NewMockRepositoryInterface(ctrl)
+	events := &eventLog{}
+	service := auction.NewAuctionService(mockRepo, noTx{}, events, antiSniping)
+
+	return ctrl, mockRepo, events, service
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+// expectPlaceBid подставляет состояние аукциона в функцию проверки ставки, как это делает репозиторий
+func expectPlaceBid(mockRepo *mockauction.MockRepositoryInterface, state *auction.Auction) {
+	mockRepo.EXPECT().
+		PlaceBid(gomock.Any(), state.AdvertisementID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, apply func(a *auction.Auction) (*auction.Bid, error)) (*auction.Auction, *auction.Bid, error) {
+			bid, err := apply(state)
+			if err != nil {
+				return nil, nil, err
+			}
+			return state, bid, nil
+		})
+}
+
+func openAuction() *auction.Auction {
+	return &auction.Auction{
+		AdvertisementID:        uuid.New(),
+		SellerID:               uuid.New(),
+		StartingPriceKopecks:   1000,
+		CurrentPriceKopecks:    1000,
+		MinBidIncrementKopecks: 100,
+		EndsAt:                 time.Now().Add(time.Hour),
+		Status:                 auction.StatusOpen,
+	}
+}
+
+func TestService_PlaceBid(t *testing.T) {
+	bidderID := uuid.New()
+
+	t.Run("первая ставка по стартовой цене", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		state := openAuction()
+		expectPlaceBid(mockRepo, state)
+
+		result, err := service.PlaceBid(context.Background(), &auction.PlaceBidInput{
+			AdvertisementID: state.AdvertisementID, BidderID: bidderID, AmountKopecks: 1000,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1000, result.Bid.AmountKopecks)
+		assert.Equal(t, 1, result.Auction.BidsCount)
+		assert.Equal(t, &bidderID, result.Auction.HighestBidderID)
+		assert.True(t, result.Auction.ReserveMet)
+	})
+
+	t.Run("ошибка: ставка ниже шага", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		state := openAuction()
+		state.BidsCount = 1
+		state.CurrentPriceKopecks = 1500
+		expectPlaceBid(mockRepo, state)
+
+		_, err := service.PlaceBid(context.Background(), &auction.PlaceBidInput{
+			AdvertisementID: state.AdvertisementID, BidderID: bidderID, AmountKopecks: 1550,
+		})
+		assert.ErrorIs(t, err, auction.ErrBidTooLow)
+		assert.ErrorContains(t, err, "minimum bid is 1600")
+	})
+
+	t.Run("ошибка: ставка на свой аукцион", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		state := openAuction()
+		expectPlaceBid(mockRepo, state)
+
+		_, err := service.PlaceBid(context.Background(), &auction.PlaceBidInput{
+			AdvertisementID: state.AdvertisementID, BidderID: state.SellerID, AmountKopecks: 2000,
+		})
+		assert.ErrorIs(t, err, auction.ErrOwnAuction)
+	})
+
+	t.Run("ошибка: торги завершены", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		state := openAuction()
+		state.EndsAt = time.Now().Add(-time.Second)
+		expectPlaceBid(mockRepo, state)
+
+		_, err := service.PlaceBid(context.Background(), &auction.PlaceBidInput{
+			AdvertisementID: state.AdvertisementID, BidderID: bidderID, AmountKopecks: 2000,
+		})
+		assert.ErrorIs(t, err, auction.ErrAuctionClosed)
+	})
+
+	t.Run("купить сейчас завершает торги", func(t *testing.T) {
+		ctrl, mockRepo, events, service := setupEventsTest(t)
+		defer ctrl.Finish()
+
+		state := openAuction()
+		state.BuyNowPriceKopecks = intPtr(5000)
+		expectPlaceBid(mockRepo, state)
+		// Проданный лот снимается с публикации
+		mockRepo.EXPECT().CloseListing(gomock.Any(), state.AdvertisementID).Return(nil)
+
+		result, err := service.PlaceBid(context.Background(), &auction.PlaceBidInput{
+			AdvertisementID: state.AdvertisementID, BidderID: bidderID, AmountKopecks: 7000,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 5000, result.Bid.AmountKopecks)
+		assert.Equal(t, auction.StatusSold, result.Auction.Status)
+		assert.Equal(t, &bidderID, result.Auction.WinnerID)
+
+		// Продажа сохраняется событием вместе со ставкой
+		assert.Len(t, events.events, 1)
+		var sold auction.SoldEvent
+		assert.NoError(t, events.events[0].Decode(&sold))
+		assert.Equal(t, auction.EventSold, events.events[0].Type)
+		assert.Equal(t, bidderID, sold.WinnerID)
+		assert.Equal(t, 5000, sold.PriceKopecks)
+	})
+
+	t.Run("ставка в последние минуты продлевает торги", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		state := openAuction()
+		state.EndsAt = time.Now().Add(time.Minute)
+		expectPlaceBid(mockRepo, state)
+		// Объявление остаётся в ленте, пока идут продлённые торги
+		mockRepo.EXPECT().ExtendListing(gomock.Any(), state.AdvertisementID, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ uuid.UUID, until time.Time) error {
+				assert.Equal(t, state.EndsAt, until)
+				return nil
+			})
+
+		result, err := service.PlaceBid(context.Background(), &auction.PlaceBidInput{
+			AdvertisementID: state.AdvertisementID, BidderID: bidderID, AmountKopecks: 1000,
+		})
+		assert.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(antiSniping.Extension), result.Auction.EndsAt, time.Second)
+	})
+
+	t.Run("ошибка продления размещения отменяет ставку", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		state := openAuction()
+		state.EndsAt = time.Now().Add(time.Minute)
+		expectPlaceBid(mockRepo, state)
+		errDB := errors.New("db is down")
+		mockRepo.EXPECT().ExtendListing(gomock.Any(), state.AdvertisementID, gomock.Any()).Return(errDB)
+
+		_, err := service.PlaceBid(context.Background(), &auction.PlaceBidInput{
+			AdvertisementID: state.AdvertisementID, BidderID: bidderID, AmountKopecks: 1000,
+		})
+		assert.ErrorIs(t, err, errDB)
+	})
+
+	t.Run("ошибка снятия объявления отменяет покупку", func(t *testing.T) {
+		ctrl, mockRepo, events, service := setupEventsTest(t)
+		defer ctrl.Finish()
+
+		state := openAuction()
+		state.BuyNowPriceKopecks = intPtr(5000)
+		expectPlaceBid(mockRepo, state)
+		errDB := errors.New("db is down")
+		mockRepo.EXPECT().CloseListing(gomock.Any(), state.AdvertisementID).Return(errDB)
+
+		_, err := service.PlaceBid(context.Background(), &auction.PlaceBidInput{
+			AdvertisementID: state.AdvertisementID, BidderID: bidderID, AmountKopecks: 5000,
+		})
+		assert.ErrorIs(t, err, errDB)
+		assert.Empty(t, events.events)
+	})
+
+	t.Run("ошибка: неположительная ставка", func(t *testing.T) {
+		ctrl, _, service := setupTest(t)
+		defer ctrl.Finish()
+
+		_, err := service.PlaceBid(context.Background(), &auction.PlaceBidInput{
+			AdvertisementID: uuid.New(), BidderID: bidderID, AmountKopecks: -1,
+		})
+		assert.ErrorContains(t, err, "invalid bid amount")
+	})
+}
+
+func TestService_CloseDue(t *testing.T) {
+	bidderID := uuid.New()
+
+	t.Run("определение победителя и учёт резервной цены", func(t *testing.T) {
+		ctrl, mockRepo, events, service := setupEventsTest(t)
+		defer ctrl.Finish()
+
+		withWinner := openAuction()
+		withWinner.BidsCount = 2
+		withWinner.CurrentPriceKopecks = 3000
+		withWinner.HighestBidderID = &bidderID
+		withWinner.ReservePriceKopecks = intPtr(2500)
+
+		reserveNotMet := openAuction()
+		reserveNotMet.BidsCount = 1
+		reserveNotMet.HighestBidderID = &bidderID
+		reserveNotMet.ReservePriceKopecks = intPtr(2500)
+
+		noBids := openAuction()
+
+		mockRepo.EXPECT().
+			CloseDue(gomock.Any(), 10, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ int, decide func(a *auction.Auction)) ([]auction.Auction, error) {
+				due := []auction.Auction{*withWinner, *reserveNotMet, *noBids}
+				for i := range due {
+					decide(&due[i])
+				}
+				return due, nil
+			})
+		// Объявления всех закрытых аукционов снимаются с публикации, проданные и непроданные
+		for _, a := range []*auction.Auction{withWinner, reserveNotMet, noBids} {
+			mockRepo.EXPECT().CloseListing(gomock.Any(), a.AdvertisementID).Return(nil)
+		}
+
+		closed, err := service.CloseDue(context.Background(), 10)
+		assert.NoError(t, err)
+		assert.Len(t, closed, 3)
+
+		assert.Equal(t, auction.StatusSold, closed[0].Status)
+		assert.Equal(t, &bidderID, closed[0].WinnerID)
+
+		assert.Equal(t, auction.StatusUnsold, closed[1].Status)
+		assert.Nil(t, closed[1].WinnerID)
+
+		assert.Equal(t, auction.StatusUnsold, closed[2].Status)
+		assert.NotNil(t, closed[2].ClosedAt)
+
+		// Событие о продаже - только для аукциона с победителем
+		assert.Len(t, events.events, 1)
+		assert.Equal(t, withWinner.AdvertisementID, events.events[0].AggregateID)
+	})
+
+	t.Run("тест ошибки из репозитория", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().CloseDue(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+
+		_, err := service.CloseDue(context.Background(), 10)
+		assert.EqualError(t, err, "db error")
+	})
+}
+
+func TestService_Get(t *testing.T) {
+	t.Run("ошибка: аукцион не найден", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		_, err := service.Get(context.Background(), uuid.New())
+		assert.ErrorIs(t, err, auction.ErrAuctionNotFound)
+	})
+}
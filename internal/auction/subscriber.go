@@ -0,0 +1,32 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"marketplace-api/internal/notification"
+	"marketplace-api/internal/outbox"
+)
+
+// NotifySold - подписчик на EventSold: уведомляет победителя и продавца
+func NotifySold(notifier notification.Notifier) outbox.Handler {
+	return func(ctx context.Context, e outbox.Event) error {
+		var sold SoldEvent
+		if err := e.Decode(&sold); err != nil {
+			return err
+		}
+
+		price := fmt.Sprintf("%d.%02d", sold.PriceKopecks/100, sold.PriceKopecks%100)
+		if err := notifier.Notify(ctx, notification.Notification{
+			UserID:  sold.WinnerID,
+			Subject: "Вы выиграли аукцион",
+			Body:    fmt.Sprintf("Объявление %s продано вам за %s руб.", sold.AdvertisementID, price),
+		}); err != nil {
+			return err
+		}
+		return notifier.Notify(ctx, notification.Notification{
+			UserID:  sold.SellerID,
+			Subject: "Аукцион завершён",
+			Body:    fmt.Sprintf("Объявление %s продано за %s руб.", sold.AdvertisementID, price),
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package auction
+
+import (
+	"context"
+	"marketplace-api/internal/logging"
+	"time"
+)
+
+const closeBatchSize = 100
+
+// Closer - фоновая задача, закрывающая аукционы с истёкшим временем торгов
+type Closer struct {
+	service  *Service
+	interval time.Duration
+}
+
+func NewCloser(service *Service, interval time.Duration) *Closer {
+	return &Closer{service: service, interval: interval}
+}
+
+// Run - запускает периодическое закрытие аукционов до отмены ctx
+func (c *Closer) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.closeDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Closer) closeDue(ctx context.Context) {
+	for {
+		closed, err := c.service.CloseDue(ctx, closeBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				logging.FromContext(ctx).Error("error closing auctions", "error", err)
+			}
+			return
+		}
+
+		for _, a := range closed {
+			if a.WinnerID != nil {
+				logging.FromContext(ctx).Info("auction sold", "advertisement_id", a.AdvertisementID, "winner_id", a.WinnerID, "price_kopecks", a.CurrentPriceKopecks)
+			} else {
+				logging.FromContext(ctx).Info("auction closed without winner", "advertisement_id", a.AdvertisementID)
+			}
+		}
+
+		if len(closed) < closeBatchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyPrefix - префикс API-ключей, по нему ключ отличается от JWT в заголовке Authorization
+const APIKeyPrefix = "mk_"
+
+// Разрешения API-ключей
+const (
+	ScopeAdsRead  = "ads:read"  // чтение объявлений и статистики продавца
+	ScopeAdsWrite = "ads:write" // создание и изменение объявлений
+)
+
+// Scopes - все разрешения, которые можно выдать ключу
+var Scopes = []string{ScopeAdsRead, ScopeAdsWrite}
+
+// KeyAuthenticator - проверка API-ключа: владелец и разрешения ключа
+type KeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (uuid.UUID, []string, error)
+}
+
+const scopesKey contextKey = "scopes"
+
+func WithScopes(ctx context.Context, scopes []string) context.Context {
+	return context.WithValue(ctx, scopesKey, scopes)
+}
+
+// ScopesFromContext - разрешения API-ключа запроса. ok == false, если запрос не по ключу:
+// вход по JWT ничем не ограничен
+func ScopesFromContext(ctx context.Context) ([]string, bool) {
+	scopes, ok := ctx.Value(scopesKey).([]string)
+	return scopes, ok
+}
+
+// RequireScope - запрос по API-ключу допускается, только если у ключа есть scope. Пустой
+// scope закрывает маршрут для ключей: он доступен только после входа по паролю. Запросы
+// по JWT и анонимные запросы проходят без проверки
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if scopes, ok := ScopesFromContext(r.Context()); ok {
+				if scope == "" {
+					http.Error(w, "not available for API keys", http.StatusForbidden)
+					return
+				}
+				if !slices.Contains(scopes, scope) {
+					http.Error(w, "API key lacks scope "+scope, http.StatusForbidden)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -13,10 +13,10 @@ type JWTManager struct {
 	tokenDuration time.Duration
 }
 
-func NewJWTManager(secret string) *JWTManager {
+func NewJWTManager(secret string, tokenDuration time.Duration) *JWTManager {
 	return &JWTManager{
 		secretKey:     secret,
-		tokenDuration: 24 * time.Hour,
+		tokenDuration: tokenDuration,
 	}
 }
 
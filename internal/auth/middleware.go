@@ -2,9 +2,13 @@ package auth
 
 import (
 	"context"
+	"errors"
+	"marketplace-api/internal/logging"
+	"marketplace-api/internal/metrics"
 	"net/http"
 	"strings"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
@@ -22,34 +26,35 @@ func WithUserID(ctx context.Context, userID uuid.UUID) context.Context {
 	return context.WithValue(ctx, userIDKey, userID)
 }
 
-func AuthMiddleware(jwtManager *JWTManager, next http.Handler) http.Handler {
+// AuthMiddleware - требует JWT или, если keys не nil, API-ключ в заголовке Authorization
+func AuthMiddleware(jwtManager *JWTManager, keys KeyAuthenticator, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
+			metrics.JWTValidationFailed("missing_header")
 			http.Error(w, "missing Authorization header", http.StatusUnauthorized)
 			return
 		}
 
 		parts := strings.SplitN(authHeader, " ", 2)
 		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+			metrics.JWTValidationFailed("malformed_header")
 			http.Error(w, "invalid Authorization header format", http.StatusUnauthorized)
 			return
 		}
 
-		token := parts[1]
-		userID, err := jwtManager.Parse(token)
+		ctx, err := identify(r.Context(), jwtManager, keys, parts[1])
 		if err != nil {
 			http.Error(w, "invalid or expired token", http.StatusUnauthorized)
 			return
 		}
-
-		// Добавление userID в context
-		ctx := WithUserID(r.Context(), userID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
-func OptionalAuthMiddleware(jwtManager *JWTManager, next http.Handler) http.Handler {
+// OptionalAuthMiddleware - как AuthMiddleware, но без токена или с неверным токеном
+// запрос продолжается анонимно
+func OptionalAuthMiddleware(jwtManager *JWTManager, keys KeyAuthenticator, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		// Токен отсутствует — просто продолжаем без userID
@@ -64,15 +69,67 @@ func OptionalAuthMiddleware(jwtManager *JWTManager, next http.Handler) http.Hand
 			return
 		}
 
-		token := parts[1]
-		userID, err := jwtManager.Parse(token)
+		ctx, err := identify(r.Context(), jwtManager, keys, parts[1])
 		if err != nil {
 			next.ServeHTTP(w, r)
 			return
 		}
-
-		// Добавляем userID в context
-		ctx := WithUserID(r.Context(), userID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// identify - контекст с пользователем по токену: API-ключу (по префиксу) или JWT
+func identify(ctx context.Context, jwtManager *JWTManager, keys KeyAuthenticator, token string) (context.Context, error) {
+	if keys != nil && strings.HasPrefix(token, APIKeyPrefix) {
+		userID, scopes, err := keys.Authenticate(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		ctx = WithScopes(WithUserID(ctx, userID), scopes)
+		logging.AddAttrs(ctx, "user_id", userID.String(), "auth", "api_key")
+		return ctx, nil
+	}
+
+	userID, err := jwtManager.Parse(token)
+	if err != nil {
+		metrics.JWTValidationFailed(failureReason(err))
+		return nil, err
+	}
+
+	// Добавление userID в context
+	ctx = WithUserID(ctx, userID)
+	logging.AddAttrs(ctx, "user_id", userID.String())
+	return ctx, nil
+}
+
+// Required - AuthMiddleware в виде middleware для цепочек и групп маршрутов
+func Required(jwtManager *JWTManager, keys KeyAuthenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return AuthMiddleware(jwtManager, keys, next)
+	}
+}
+
+// Optional - OptionalAuthMiddleware в виде middleware для цепочек и групп маршрутов
+func Optional(jwtManager *JWTManager, keys KeyAuthenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return OptionalAuthMiddleware(jwtManager, keys, next)
+	}
+}
+
+// failureReason - причина отказа в токене для метрик
+func failureReason(err error) string {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return "expired"
+	case errors.Is(err, jwt.ErrTokenNotValidYet):
+		return "not_valid_yet"
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid):
+		return "invalid_signature"
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return "malformed_token"
+	case errors.Is(err, jwt.ErrTokenUnverifiable):
+		return "unverifiable"
+	default:
+		return "invalid"
+	}
+}
@@ -0,0 +1,123 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"marketplace-api/internal/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKeys - API-ключи в памяти
+type fakeKeys map[string][]string
+
+func (k fakeKeys) Authenticate(_ context.Context, key string) (uuid.UUID, []string, error) {
+	scopes, ok := k[key]
+	if !ok {
+		return uuid.Nil, nil, errors.New("invalid key")
+	}
+	return keyOwner, scopes, nil
+}
+
+var keyOwner = uuid.New()
+
+// whoami - отвечает ID пользователя из контекста или "anonymous"
+var whoami = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	if userID, ok := auth.UserIDFromContext(r.Context()); ok {
+		w.Write([]byte(userID.String()))
+		return
+	}
+	w.Write([]byte("anonymous"))
+})
+
+func serve(handler http.Handler, authorization string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	jwtManager := auth.NewJWTManager("secret", time.Hour)
+	keys := fakeKeys{"mk_read": {auth.ScopeAdsRead}}
+	userID := uuid.New()
+	token, err := jwtManager.Generate(userID)
+	require.NoError(t, err)
+
+	t.Run("JWT", func(t *testing.T) {
+		w := serve(auth.AuthMiddleware(jwtManager, keys, whoami), "Bearer "+token)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, userID.String(), w.Body.String())
+	})
+
+	t.Run("API-ключ", func(t *testing.T) {
+		w := serve(auth.AuthMiddleware(jwtManager, keys, whoami), "Bearer mk_read")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, keyOwner.String(), w.Body.String())
+	})
+
+	t.Run("ошибка: неизвестный ключ", func(t *testing.T) {
+		w := serve(auth.AuthMiddleware(jwtManager, keys, whoami), "Bearer mk_unknown")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("ошибка: ключи выключены", func(t *testing.T) {
+		w := serve(auth.AuthMiddleware(jwtManager, nil, whoami), "Bearer mk_read")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("ошибка: нет заголовка", func(t *testing.T) {
+		w := serve(auth.AuthMiddleware(jwtManager, keys, whoami), "")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("необязательная авторизация с неверным ключом", func(t *testing.T) {
+		w := serve(auth.OptionalAuthMiddleware(jwtManager, keys, whoami), "Bearer mk_unknown")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "anonymous", w.Body.String())
+	})
+}
+
+func TestRequireScope(t *testing.T) {
+	jwtManager := auth.NewJWTManager("secret", time.Hour)
+	keys := fakeKeys{"mk_read": {auth.ScopeAdsRead}, "mk_write": {auth.ScopeAdsRead, auth.ScopeAdsWrite}}
+	token, err := jwtManager.Generate(uuid.New())
+	require.NoError(t, err)
+
+	protected := func(scope string) http.Handler {
+		return auth.AuthMiddleware(jwtManager, keys, auth.RequireScope(scope)(whoami))
+	}
+
+	t.Run("ключ с разрешением", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve(protected(auth.ScopeAdsWrite), "Bearer mk_write").Code)
+	})
+
+	t.Run("ошибка: у ключа нет разрешения", func(t *testing.T) {
+		w := serve(protected(auth.ScopeAdsWrite), "Bearer mk_read")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), auth.ScopeAdsWrite)
+	})
+
+	t.Run("ошибка: маршрут недоступен по ключу", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, serve(protected(""), "Bearer mk_write").Code)
+	})
+
+	t.Run("JWT не ограничен разрешениями", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve(protected(""), "Bearer "+token).Code)
+		assert.Equal(t, http.StatusOK, serve(protected(auth.ScopeAdsWrite), "Bearer "+token).Code)
+	})
+
+	t.Run("анонимный запрос проходит", func(t *testing.T) {
+		handler := auth.OptionalAuthMiddleware(jwtManager, keys, auth.RequireScope(auth.ScopeAdsRead)(whoami))
+		assert.Equal(t, http.StatusOK, serve(handler, "").Code)
+	})
+}
@@ -0,0 +1,15 @@
+// Package cache - кэши с ограничением по времени жизни и размеру
+package cache
+
+import "context"
+
+// Backend - хранилище кэша. Реализации могут быть локальными (LRU) или общими для
+// нескольких экземпляров; ошибки внешнего хранилища считаются промахом
+type Backend[V any] interface {
+	// Get - значение по ключу, если оно есть и не устарело
+	Get(ctx context.Context, key string) (V, bool)
+	// Set - сохраняет значение
+	Set(ctx context.Context, key string, value V)
+	// Purge - удаляет все значения
+	Purge(ctx context.Context)
+}
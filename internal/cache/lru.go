@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// LRU - кэш в памяти процесса: хранит не больше size значений, вытесняя давно
+// не запрашиваемые, и не отдаёт значения старше ttl
+type LRU[V any] struct {
+	size int
+	ttl  time.Duration
+
+	mu    sync.Mutex
+	order *list.List // от недавно запрошенных к давно запрошенным
+	items map[string]*list.Element
+}
+
+type lruEntry[V any] struct {
+	key       string
+	value     V
+	expiresAt time.Time
+}
+
+// NewLRU - создаёт кэш на size значений с временем жизни ttl
+func NewLRU[V any](size int, ttl time.Duration) *LRU[V] {
+	return &LRU[V]{
+		size:  size,
+		ttl:   ttl,
+		order: list.New(),
+		items: make(map[string]*list.Element, size),
+	}
+}
+
+func (c *LRU[V]) Get(_ context.Context, key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	el, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+	entry := el.Value.(*lruEntry[V])
+	if !time.Now().Before(entry.expiresAt) {
+		c.remove(el)
+		return zero, false
+	}
+	c.order.MoveToFront(el)
+	return entry.value, true
+}
+
+func (c *LRU[V]) Set(_ context.Context, key string, value V) {
+	if c.size <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := time.Now().Add(c.ttl)
+	if el, ok := c.items[key]; ok {
+		entry := el.Value.(*lruEntry[V])
+		entry.value, entry.expiresAt = value, expiresAt
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.order.PushFront(&lruEntry[V]{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *LRU[V]) Purge(context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	clear(c.items)
+}
+
+// Len - количество значений, включая ещё не удалённые устаревшие
+func (c *LRU[V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *LRU[V]) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*lruEntry[V]).key)
+}
@@ -0,0 +1,99 @@
+package cache_test
+
+import (
+	"context"
+	"marketplace-api/internal/cache"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("сохранённое значение возвращается", func(t *testing.T) {
+		c := cache.NewLRU[string](2, time.Minute)
+		c.Set(ctx, "a", "1")
+
+		v, ok := c.Get(ctx, "a")
+		assert.True(t, ok)
+		assert.Equal(t, "1", v)
+
+		_, ok = c.Get(ctx, "b")
+		assert.False(t, ok)
+	})
+
+	t.Run("вытесняется давно не запрашиваемое", func(t *testing.T) {
+		c := cache.NewLRU[string](2, time.Minute)
+		c.Set(ctx, "a", "1")
+		c.Set(ctx, "b", "2")
+		c.Get(ctx, "a")
+		c.Set(ctx, "c", "3")
+
+		_, ok := c.Get(ctx, "b")
+		assert.False(t, ok)
+		_, ok = c.Get(ctx, "a")
+		assert.True(t, ok)
+		_, ok = c.Get(ctx, "c")
+		assert.True(t, ok)
+		assert.Equal(t, 2, c.Len())
+	})
+
+	t.Run("перезапись не увеличивает размер", func(t *testing.T) {
+		c := cache.NewLRU[string](2, time.Minute)
+		c.Set(ctx, "a", "1")
+		c.Set(ctx, "a", "2")
+
+		v, _ := c.Get(ctx, "a")
+		assert.Equal(t, "2", v)
+		assert.Equal(t, 1, c.Len())
+	})
+
+	t.Run("устаревшее значение не возвращается", func(t *testing.T) {
+		c := cache.NewLRU[string](2, 20*time.Millisecond)
+		c.Set(ctx, "a", "1")
+		time.Sleep(30 * time.Millisecond)
+
+		_, ok := c.Get(ctx, "a")
+		assert.False(t, ok)
+		assert.Equal(t, 0, c.Len())
+	})
+
+	t.Run("Purge удаляет всё", func(t *testing.T) {
+		c := cache.NewLRU[string](2, time.Minute)
+		c.Set(ctx, "a", "1")
+		c.Set(ctx, "b", "2")
+		c.Purge(ctx)
+
+		_, ok := c.Get(ctx, "a")
+		assert.False(t, ok)
+		assert.Equal(t, 0, c.Len())
+	})
+
+	t.Run("нулевой размер отключает кэш", func(t *testing.T) {
+		c := cache.NewLRU[string](0, time.Minute)
+		c.Set(ctx, "a", "1")
+
+		_, ok := c.Get(ctx, "a")
+		assert.False(t, ok)
+	})
+
+	t.Run("конкурентный доступ", func(t *testing.T) {
+		c := cache.NewLRU[int](10, time.Minute)
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				key := strconv.Itoa(i % 20)
+				c.Set(ctx, key, i)
+				c.Get(ctx, key)
+			}()
+		}
+		wg.Wait()
+		assert.LessOrEqual(t, c.Len(), 10)
+	})
+}
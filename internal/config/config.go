@@ -0,0 +1,294 @@
+package config
+
+import (
+	"time"
+)
+
+// Config - настройки приложения.
+// Источники в порядке приоритета: флаги, переменные окружения, файл (YAML или TOML), значения по умолчанию
+type Config struct {
+	Env           string        `yaml:"env" toml:"env"` // окружение: dev или prod
+	Server        Server        `yaml:"server" toml:"server"`
+	Database      Database      `yaml:"database" toml:"database"`
+	Auth          Auth          `yaml:"auth" toml:"auth"`
+	Pagination    Pagination    `yaml:"pagination" toml:"pagination"`
+	Advertisement Advertisement `yaml:"advertisement" toml:"advertisement"`
+	Payments      Payments      `yaml:"payments" toml:"payments"`
+	Stats         Stats         `yaml:"stats" toml:"stats"`
+	Features      Features      `yaml:"features" toml:"features"`
+	Tracing       Tracing       `yaml:"tracing" toml:"tracing"`
+	Log           Log           `yaml:"log" toml:"log"`
+	CORS          CORS          `yaml:"cors" toml:"cors"`
+	Compression   Compression   `yaml:"compression" toml:"compression"`
+	API           API           `yaml:"api" toml:"api"`
+	RateLimit     RateLimit     `yaml:"rate_limit" toml:"rate_limit"`
+	Idempotency   Idempotency   `yaml:"idempotency" toml:"idempotency"`
+	FeedCache     FeedCache     `yaml:"feed_cache" toml:"feed_cache"`
+	Webhooks      Webhooks      `yaml:"webhooks" toml:"webhooks"`
+
+	File        string `yaml:"-" toml:"-"` // путь к файлу конфигурации, если он задан
+	PrintConfig bool   `yaml:"-" toml:"-"` // вывести итоговую конфигурацию и завершиться
+}
+
+type Server struct {
+	Port              int           `yaml:"port" toml:"port"`
+	PublicURL         string        `yaml:"public_url" toml:"public_url"` // внешний адрес для swagger и платёжных ссылок
+	ReadTimeout       time.Duration `yaml:"read_timeout" toml:"read_timeout"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" toml:"read_header_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" toml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" toml:"idle_timeout"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+	ShutdownDelay     time.Duration `yaml:"shutdown_delay" toml:"shutdown_delay"` // сколько отдавать not-ready до остановки, чтобы балансировщик снял трафик
+	ReadinessTimeout  time.Duration `yaml:"readiness_timeout" toml:"readiness_timeout"`
+	MaxHeaderBytes    int           `yaml:"max_header_bytes" toml:"max_header_bytes"`
+	MaxBodyBytes      int64         `yaml:"max_body_bytes" toml:"max_body_bytes"`
+	TLSCertFile       string        `yaml:"tls_cert_file" toml:"tls_cert_file"`
+	TLSKeyFile        string        `yaml:"tls_key_file" toml:"tls_key_file"`
+}
+
+type Database struct {
+	DSN               string        `yaml:"dsn" toml:"dsn"`
+	MaxConns          int           `yaml:"max_conns" toml:"max_conns"`
+	MinConns          int           `yaml:"min_conns" toml:"min_conns"`
+	MaxConnLifetime   time.Duration `yaml:"max_conn_lifetime" toml:"max_conn_lifetime"`
+	MaxConnIdleTime   time.Duration `yaml:"max_conn_idle_time" toml:"max_conn_idle_time"`
+	HealthCheckPeriod time.Duration `yaml:"health_check_period" toml:"health_check_period"`
+	ConnectTimeout    time.Duration `yaml:"connect_timeout" toml:"connect_timeout"`
+	MigrateOnStart    bool          `yaml:"migrate_on_start" toml:"migrate_on_start"` // применять миграции при запуске
+}
+
+type Auth struct {
+	JWTSecret string        `yaml:"jwt_secret" toml:"jwt_secret"`
+	TokenTTL  time.Duration `yaml:"token_ttl" toml:"token_ttl"`
+}
+
+type Pagination struct {
+	DefaultLimit int `yaml:"default_limit" toml:"default_limit"`
+	MaxLimit     int `yaml:"max_limit" toml:"max_limit"`
+}
+
+type Advertisement struct {
+	Lifetime           time.Duration            `yaml:"lifetime" toml:"lifetime"`
+	LifetimeByCategory map[string]time.Duration `yaml:"lifetime_by_category" toml:"lifetime_by_category"`
+	ExpiryReminder     time.Duration            `yaml:"expiry_reminder" toml:"expiry_reminder"` // за сколько до окончания срока напоминать автору
+	PageURL            string                   `yaml:"page_url" toml:"page_url"`               // страница объявления в лентах и карте сайта, {id} заменяется на ID; пусто - объявление в API
+}
+
+// Окружения
+const (
+	EnvDev  = "dev"
+	EnvProd = "prod"
+)
+
+// Платёжные провайдеры
+const (
+	PaymentProviderDisabled = "disabled" // платное продвижение недоступно
+	PaymentProviderFake     = "fake"     // локальная замена, оплата без денег - только для dev
+)
+
+type Payments struct {
+	Provider      string `yaml:"provider" toml:"provider"` // disabled или fake
+	WebhookSecret string `yaml:"webhook_secret" toml:"webhook_secret"`
+}
+
+// Stats - статистика просмотров объявлений
+type Stats struct {
+	ViewerSecret string `yaml:"viewer_secret" toml:"viewer_secret"` // ключ HMAC для IP-адресов и ID зрителей, без него хэш подбирается перебором
+}
+
+type Tracing struct {
+	Exporter     string  `yaml:"exporter" toml:"exporter"` // none, stdout или otlp
+	OTLPEndpoint string  `yaml:"otlp_endpoint" toml:"otlp_endpoint"`
+	SampleRatio  float64 `yaml:"sample_ratio" toml:"sample_ratio"`
+}
+
+type Log struct {
+	Level  string `yaml:"level" toml:"level"`   // debug, info, warn, error
+	Format string `yaml:"format" toml:"format"` // json или text
+}
+
+type CORS struct {
+	AllowedOrigins   []string      `yaml:"allowed_origins" toml:"allowed_origins"` // пусто - CORS выключен, "*" - любой источник
+	AllowedHeaders   []string      `yaml:"allowed_headers" toml:"allowed_headers"`
+	ExposedHeaders   []string      `yaml:"exposed_headers" toml:"exposed_headers"`
+	AllowCredentials bool          `yaml:"allow_credentials" toml:"allow_credentials"`
+	MaxAge           time.Duration `yaml:"max_age" toml:"max_age"`
+}
+
+type Compression struct {
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	MinSize int  `yaml:"min_size" toml:"min_size"` // ответы меньше этого размера не сжимаются
+}
+
+// API - версии API. Маршруты без версии обслуживаются как устаревшие псевдонимы /api/v1
+type API struct {
+	LegacyRoutes     bool   `yaml:"legacy_routes" toml:"legacy_routes"`
+	LegacyDeprecated string `yaml:"legacy_deprecated" toml:"legacy_deprecated"` // дата объявления устаревшими, YYYY-MM-DD
+	LegacySunset     string `yaml:"legacy_sunset" toml:"legacy_sunset"`         // дата отключения, YYYY-MM-DD
+}
+
+// DateLayout - формат дат в конфигурации
+const DateLayout = time.DateOnly
+
+// LegacyDeprecatedAt - дата объявления маршрутов без версии устаревшими
+func (a API) LegacyDeprecatedAt() time.Time {
+	t, _ := time.Parse(DateLayout, a.LegacyDeprecated)
+	return t
+}
+
+// LegacySunsetAt - дата отключения маршрутов без версии
+func (a API) LegacySunsetAt() time.Time {
+	t, _ := time.Parse(DateLayout, a.LegacySunset)
+	return t
+}
+
+// RateLimit - ограничение частоты запросов. Политики в формате "запросов/период[:всплеск]",
+// например "10/1m" или "300/1m:50"; пустая строка - без ограничения
+type RateLimit struct {
+	Enabled        bool     `yaml:"enabled" toml:"enabled"`
+	Store          string   `yaml:"store" toml:"store"`                     // memory или postgres (общий для нескольких экземпляров)
+	TrustedProxies []string `yaml:"trusted_proxies" toml:"trusted_proxies"` // адреса и подсети прокси, которым доверяется X-Forwarded-For
+	Auth           string   `yaml:"auth" toml:"auth"`                       // регистрация и вход, по адресу клиента
+	Write          string   `yaml:"write" toml:"write"`                     // изменения от авторизованных пользователей
+	Read           string   `yaml:"read" toml:"read"`                       // чтение
+}
+
+// Idempotency - повтор ответов на POST-запросы с заголовком Idempotency-Key
+type Idempotency struct {
+	Enabled     bool          `yaml:"enabled" toml:"enabled"`
+	Store       string        `yaml:"store" toml:"store"`               // memory или postgres
+	TTL         time.Duration `yaml:"ttl" toml:"ttl"`                   // сколько хранится ответ
+	LockTimeout time.Duration `yaml:"lock_timeout" toml:"lock_timeout"` // через сколько брошенный незавершённый запрос отпускает ключ
+	Wait        time.Duration `yaml:"wait" toml:"wait"`                 // сколько повтор ждёт завершения первого запроса до 409
+}
+
+// FeedCache - кэш страниц публичной ленты объявлений для анонимных запросов
+type FeedCache struct {
+	Enabled bool          `yaml:"enabled" toml:"enabled"`
+	TTL     time.Duration `yaml:"ttl" toml:"ttl"`   // сколько страница может отставать от изменений в обход кэша
+	Size    int           `yaml:"size" toml:"size"` // сколько страниц хранится
+}
+
+// Webhooks - уведомления партнёров о событиях их объявлений
+type Webhooks struct {
+	Enabled       bool          `yaml:"enabled" toml:"enabled"`
+	AllowInsecure bool          `yaml:"allow_insecure" toml:"allow_insecure"` // разрешить http и внутренние адреса, только для разработки
+	Timeout       time.Duration `yaml:"timeout" toml:"timeout"`               // ограничение времени запроса к получателю
+	MaxAttempts   int           `yaml:"max_attempts" toml:"max_attempts"`     // попыток на одну доставку
+	DisableAfter  int           `yaml:"disable_after" toml:"disable_after"`   // адрес отключается после стольких неудачных попыток подряд
+}
+
+// Features - переключатели необязательных возможностей
+type Features struct {
+	Promotions bool `yaml:"promotions" toml:"promotions"`
+	APIKeys    bool `yaml:"api_keys" toml:"api_keys"`
+	ViewStats  bool `yaml:"view_stats" toml:"view_stats"`
+	Swagger    bool `yaml:"swagger" toml:"swagger"`
+}
+
+// Default - значения по умолчанию. Секреты и DSN не имеют значений по умолчанию и обязательны
+func Default() *Config {
+	return &Config{
+		Env: EnvProd,
+		Server: Server{
+			Port:              8080,
+			PublicURL:         "http://localhost:8080",
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       120 * time.Second,
+			ShutdownTimeout:   30 * time.Second,
+			ShutdownDelay:     5 * time.Second,
+			ReadinessTimeout:  2 * time.Second,
+			MaxHeaderBytes:    1 << 20,
+			MaxBodyBytes:      10 << 20,
+		},
+		Database: Database{
+			MaxConns:          10,
+			MinConns:          0,
+			MaxConnLifetime:   time.Hour,
+			MaxConnIdleTime:   30 * time.Minute,
+			HealthCheckPeriod: time.Minute,
+			ConnectTimeout:    5 * time.Second,
+		},
+		Auth: Auth{
+			TokenTTL: 24 * time.Hour,
+		},
+		Pagination: Pagination{
+			DefaultLimit: 10,
+			MaxLimit:     100,
+		},
+		Advertisement: Advertisement{
+			Lifetime:       30 * 24 * time.Hour,
+			ExpiryReminder: 3 * 24 * time.Hour,
+		},
+		Payments: Payments{
+			Provider: PaymentProviderDisabled,
+		},
+		Tracing: Tracing{
+			Exporter:    "none",
+			SampleRatio: 1,
+		},
+		Log: Log{
+			Level:  "info",
+			Format: "json",
+		},
+		CORS: CORS{
+			AllowedHeaders: []string{"Authorization", "Content-Type", "X-Request-ID", "Idempotency-Key", "If-Match", "If-None-Match", "If-Modified-Since"},
+			ExposedHeaders: []string{"X-Request-ID", "Idempotent-Replayed", "ETag", "Last-Modified",
+				"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After",
+				"Deprecation", "Sunset", "Link"},
+			MaxAge: 10 * time.Minute,
+		},
+		Compression: Compression{
+			Enabled: true,
+			MinSize: 1024,
+		},
+		API: API{
+			LegacyRoutes:     true,
+			LegacyDeprecated: "2026-10-19",
+			LegacySunset:     "2027-04-30",
+		},
+		RateLimit: RateLimit{
+			Enabled: true,
+			Store:   "memory",
+			Auth:    "10/1m",
+			Write:   "30/1m",
+			Read:    "300/1m",
+		},
+		Idempotency: Idempotency{
+			Enabled:     true,
+			Store:       "postgres",
+			TTL:         24 * time.Hour,
+			LockTimeout: time.Minute,
+			Wait:        5 * time.Second,
+		},
+		FeedCache: FeedCache{
+			Enabled: true,
+			TTL:     30 * time.Second,
+			Size:    1000,
+		},
+		Webhooks: Webhooks{
+			Enabled:      true,
+			Timeout:      10 * time.Second,
+			MaxAttempts:  8,
+			DisableAfter: 50,
+		},
+		Features: Features{
+			Promotions: true,
+			APIKeys:    true,
+			ViewStats:  true,
+			Swagger:    true,
+		},
+	}
+}
+
+// TLS - включён ли TLS
+func (c *Config) TLS() bool {
+	return c.Server.TLSCertFile != "" || c.Server.TLSKeyFile != ""
+}
+
+// PaymentsEnabled - доступно ли платное продвижение
+func (c *Config) PaymentsEnabled() bool {
+	return c.Features.Promotions && c.Payments.Provider != PaymentProviderDisabled
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"fmt"
+	"marketplace-api/internal/advertisement"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// field - настройка, доступная через переменную окружения и флаг
+type field struct {
+	flag   string
+	env    string
+	usage  string
+	secret bool
+	ptr    any // указатель на поле Config
+}
+
+func fields(c *Config) []field {
+	return []field{
+		{flag: "env", env: "APP_ENV", usage: "environment: dev or prod", ptr: &c.Env},
+		{flag: "port", env: "PORT", usage: "HTTP port", ptr: &c.Server.Port},
+		{flag: "public-url", env: "PUBLIC_URL", usage: "external base URL of the API", ptr: &c.Server.PublicURL},
+		{flag: "http-read-timeout", env: "HTTP_READ_TIMEOUT", usage: "max duration for reading the whole request", ptr: &c.Server.ReadTimeout},
+		{flag: "http-read-header-timeout", env: "HTTP_READ_HEADER_TIMEOUT", usage: "max duration for reading request headers", ptr: &c.Server.ReadHeaderTimeout},
+		{flag: "http-write-timeout", env: "HTTP_WRITE_TIMEOUT", usage: "max duration for writing the response", ptr: &c.Server.WriteTimeout},
+		{flag: "http-idle-timeout", env: "HTTP_IDLE_TIMEOUT", usage: "keep-alive idle timeout", ptr: &c.Server.IdleTimeout},
+		{flag: "http-shutdown-timeout", env: "HTTP_SHUTDOWN_TIMEOUT", usage: "graceful shutdown deadline", ptr: &c.Server.ShutdownTimeout},
+		{flag: "http-shutdown-delay", env: "HTTP_SHUTDOWN_DELAY", usage: "how long to report not ready before shutting down", ptr: &c.Server.ShutdownDelay},
+		{flag: "readiness-timeout", env: "READINESS_TIMEOUT", usage: "timeout of readiness checks", ptr: &c.Server.ReadinessTimeout},
+		{flag: "http-max-header-bytes", env: "HTTP_MAX_HEADER_BYTES", usage: "max size of request headers", ptr: &c.Server.MaxHeaderBytes},
+		{flag: "http-max-body-bytes", env: "HTTP_MAX_BODY_BYTES", usage: "max size of request body", ptr: &c.Server.MaxBodyBytes},
+		{flag: "tls-cert-file", env: "TLS_CERT_FILE", usage: "TLS certificate file", ptr: &c.Server.TLSCertFile},
+		{flag: "tls-key-file", env: "TLS_KEY_FILE", usage: "TLS private key file", ptr: &c.Server.TLSKeyFile},
+
+		{flag: "database-dsn", env: "DATABASE_DSN", usage: "PostgreSQL connection string", secret: true, ptr: &c.Database.DSN},
+		{flag: "db-max-conns", env: "DB_MAX_CONNS", usage: "max pool size", ptr: &c.Database.MaxConns},
+		{flag: "db-min-conns", env: "DB_MIN_CONNS", usage: "min pool size", ptr: &c.Database.MinConns},
+		{flag: "db-max-conn-lifetime", env: "DB_MAX_CONN_LIFETIME", usage: "max lifetime of a pooled connection", ptr: &c.Database.MaxConnLifetime},
+		{flag: "db-max-conn-idle-time", env: "DB_MAX_CONN_IDLE_TIME", usage: "max idle time of a pooled connection", ptr: &c.Database.MaxConnIdleTime},
+		{flag: "db-health-check-period", env: "DB_HEALTH_CHECK_PERIOD", usage: "pool health check period", ptr: &c.Database.HealthCheckPeriod},
+		{flag: "db-connect-timeout", env: "DB_CONNECT_TIMEOUT", usage: "database connect timeout", ptr: &c.Database.ConnectTimeout},
+		{flag: "db-migrate-on-start", env: "DB_MIGRATE_ON_START", usage: "apply pending migrations on startup", ptr: &c.Database.MigrateOnStart},
+
+		{flag: "jwt-secret", env: "JWT_SECRET", usage: "JWT signing secret", secret: true, ptr: &c.Auth.JWTSecret},
+		{flag: "jwt-token-ttl", env: "JWT_TOKEN_TTL", usage: "access token lifetime", ptr: &c.Auth.TokenTTL},
+
+		{flag: "pagination-default-limit", env: "PAGINATION_DEFAULT_LIMIT", usage: "page size when limit is not set", ptr: &c.Pagination.DefaultLimit},
+		{flag: "pagination-max-limit", env: "PAGINATION_MAX_LIMIT", usage: "max page size", ptr: &c.Pagination.MaxLimit},
+
+		{flag: "ad-lifetime", env: "AD_LIFETIME", usage: "default advertisement lifetime", ptr: &c.Advertisement.Lifetime},
+		{flag: "ad-lifetime-by-category", env: "AD_LIFETIME_BY_CATEGORY", usage: "lifetime overrides, e.g. jobs=336h,electronics=1440h", ptr: &c.Advertisement.LifetimeByCategory},
+		{flag: "ad-expiry-reminder", env: "AD_EXPIRY_REMINDER", usage: "how long before expiry to remind the author", ptr: &c.Advertisement.ExpiryReminder},
+		{flag: "ad-page-url", env: "AD_PAGE_URL", usage: "advertisement page URL for feeds and sitemap, {id} is replaced with the ID", ptr: &c.Advertisement.PageURL},
+
+		{flag: "payment-provider", env: "PAYMENT_PROVIDER", usage: "payment provider: disabled or fake (dev only)", ptr: &c.Payments.Provider},
+		{flag: "payment-webhook-secret", env: "PAYMENT_WEBHOOK_SECRET", usage: "payment callback signing secret", secret: true, ptr: &c.Payments.WebhookSecret},
+
+		{flag: "stats-viewer-secret", env: "STATS_VIEWER_SECRET", usage: "key for hashing viewer IP addresses and user IDs", secret: true, ptr: &c.Stats.ViewerSecret},
+
+		{flag: "trace-exporter", env: "OTEL_TRACES_EXPORTER", usage: "trace exporter: none, stdout or otlp", ptr: &c.Tracing.Exporter},
+		{flag: "trace-otlp-endpoint", env: "OTEL_EXPORTER_OTLP_ENDPOINT", usage: "OTLP/HTTP collector URL", ptr: &c.Tracing.OTLPEndpoint},
+		{flag: "trace-sample-ratio", env: "OTEL_TRACES_SAMPLER_ARG", usage: "fraction of new traces to sample, 0..1", ptr: &c.Tracing.SampleRatio},
+
+		{flag: "log-level", env: "LOG_LEVEL", usage: "log level: debug, info, warn or error", ptr: &c.Log.Level},
+		{flag: "log-format", env: "LOG_FORMAT", usage: "log format: json or text", ptr: &c.Log.Format},
+
+		{flag: "cors-allowed-origins", env: "CORS_ALLOWED_ORIGINS", usage: "comma-separated origins allowed to call the API, * for any", ptr: &c.CORS.AllowedOrigins},
+		{flag: "cors-allowed-headers", env: "CORS_ALLOWED_HEADERS", usage: "comma-separated request headers allowed in CORS requests", ptr: &c.CORS.AllowedHeaders},
+		{flag: "cors-exposed-headers", env: "CORS_EXPOSED_HEADERS", usage: "comma-separated response headers exposed to browsers", ptr: &c.CORS.ExposedHeaders},
+		{flag: "cors-allow-credentials", env: "CORS_ALLOW_CREDENTIALS", usage: "allow cookies and authorization in CORS requests", ptr: &c.CORS.AllowCredentials},
+		{flag: "cors-max-age", env: "CORS_MAX_AGE", usage: "how long browsers may cache preflight responses", ptr: &c.CORS.MaxAge},
+
+		{flag: "compression", env: "COMPRESSION_ENABLED", usage: "compress responses with gzip or brotli", ptr: &c.Compression.Enabled},
+		{flag: "compression-min-size", env: "COMPRESSION_MIN_SIZE", usage: "minimal response size to compress", ptr: &c.Compression.MinSize},
+
+		{flag: "api-legacy-routes", env: "API_LEGACY_ROUTES", usage: "serve unversioned routes as deprecated aliases of /api/v1", ptr: &c.API.LegacyRoutes},
+		{flag: "api-legacy-deprecated", env: "API_LEGACY_DEPRECATED", usage: "date unversioned routes were deprecated, YYYY-MM-DD", ptr: &c.API.LegacyDeprecated},
+		{flag: "api-legacy-sunset", env: "API_LEGACY_SUNSET", usage: "date unversioned routes will be removed, YYYY-MM-DD", ptr: &c.API.LegacySunset},
+
+		{flag: "rate-limit-enabled", env: "RATE_LIMIT_ENABLED", usage: "enable request rate limiting", ptr: &c.RateLimit.Enabled},
+		{flag: "rate-limit-store", env: "RATE_LIMIT_STORE", usage: "rate limit store: memory or postgres", ptr: &c.RateLimit.Store},
+		{flag: "rate-limit-trusted-proxies", env: "RATE_LIMIT_TRUSTED_PROXIES", usage: "comma-separated proxy addresses or CIDRs trusted for X-Forwarded-For", ptr: &c.RateLimit.TrustedProxies},
+		{flag: "rate-limit-auth", env: "RATE_LIMIT_AUTH", usage: "register and login limit, e.g. 10/1m", ptr: &c.RateLimit.Auth},
+		{flag: "rate-limit-write", env: "RATE_LIMIT_WRITE", usage: "limit for authenticated writes, e.g. 30/1m", ptr: &c.RateLimit.Write},
+		{flag: "rate-limit-read", env: "RATE_LIMIT_READ", usage: "limit for reads, e.g. 300/1m:50", ptr: &c.RateLimit.Read},
+
+		{flag: "idempotency-enabled", env: "IDEMPOTENCY_ENABLED", usage: "replay responses for repeated Idempotency-Key", ptr: &c.Idempotency.Enabled},
+		{flag: "idempotency-store", env: "IDEMPOTENCY_STORE", usage: "idempotency key store: memory or postgres", ptr: &c.Idempotency.Store},
+		{flag: "idempotency-ttl", env: "IDEMPOTENCY_TTL", usage: "how long stored responses are replayed", ptr: &c.Idempotency.TTL},
+		{flag: "idempotency-lock-timeout", env: "IDEMPOTENCY_LOCK_TIMEOUT", usage: "when an unfinished request stops holding its key", ptr: &c.Idempotency.LockTimeout},
+		{flag: "idempotency-wait", env: "IDEMPOTENCY_WAIT", usage: "how long a duplicate waits for the first request before 409", ptr: &c.Idempotency.Wait},
+
+		{flag: "feed-cache-enabled", env: "FEED_CACHE_ENABLED", usage: "cache anonymous advertisement feed pages", ptr: &c.FeedCache.Enabled},
+		{flag: "feed-cache-ttl", env: "FEED_CACHE_TTL", usage: "how long a cached feed page is served", ptr: &c.FeedCache.TTL},
+		{flag: "feed-cache-size", env: "FEED_CACHE_SIZE", usage: "max number of cached feed pages", ptr: &c.FeedCache.Size},
+
+		{flag: "webhooks-enabled", env: "WEBHOOKS_ENABLED", usage: "send partner webhooks", ptr: &c.Webhooks.Enabled},
+		{flag: "webhooks-allow-insecure", env: "WEBHOOKS_ALLOW_INSECURE", usage: "allow http and private webhook addresses (development only)", ptr: &c.Webhooks.AllowInsecure},
+		{flag: "webhooks-timeout", env: "WEBHOOKS_TIMEOUT", usage: "webhook request timeout", ptr: &c.Webhooks.Timeout},
+		{flag: "webhooks-max-attempts", env: "WEBHOOKS_MAX_ATTEMPTS", usage: "delivery attempts per webhook event", ptr: &c.Webhooks.MaxAttempts},
+		{flag: "webhooks-disable-after", env: "WEBHOOKS_DISABLE_AFTER", usage: "consecutive failed attempts before an endpoint is disabled", ptr: &c.Webhooks.DisableAfter},
+
+		{flag: "feature-promotions", env: "FEATURE_PROMOTIONS", usage: "enable paid promotions", ptr: &c.Features.Promotions},
+		{flag: "feature-api-keys", env: "FEATURE_API_KEYS", usage: "accept API keys alongside JWT", ptr: &c.Features.APIKeys},
+		{flag: "feature-view-stats", env: "FEATURE_VIEW_STATS", usage: "enable advertisement view recording", ptr: &c.Features.ViewStats},
+		{flag: "feature-swagger", env: "FEATURE_SWAGGER", usage: "serve swagger UI", ptr: &c.Features.Swagger},
+	}
+}
+
+// set - разбор строкового значения в поле
+func (f field) set(raw string) error {
+	raw = strings.TrimSpace(raw)
+	switch p := f.ptr.(type) {
+	case *string:
+		*p = raw
+	case *[]string:
+		var values []string
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		*p = values
+	case *int:
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("must be an integer, got %q", raw)
+		}
+		*p = v
+	case *int64:
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("must be an integer, got %q", raw)
+		}
+		*p = v
+	case *float64:
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("must be a number, got %q", raw)
+		}
+		*p = v
+	case *bool:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("must be a boolean, got %q", raw)
+		}
+		*p = v
+	case *time.Duration:
+		v, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("must be a duration like 30s or 24h, got %q", raw)
+		}
+		*p = v
+	case *map[string]time.Duration:
+		v, err := advertisement.ParseCategoryLifetimes(raw)
+		if err != nil {
+			return err
+		}
+		*p = v
+	default:
+		return fmt.Errorf("unsupported type %T", f.ptr)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "[REDACTED]"
+
+// Load - собирает конфигурацию из файла, окружения и флагов и проверяет её.
+// Путь к файлу задаётся флагом --config или переменной CONFIG_FILE
+func Load(args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	cfg := Default()
+
+	// Флаги разбираются в строки и применяются последними, поверх файла и окружения
+	fs := flag.NewFlagSet("marketplace-api", flag.ContinueOnError)
+	fs.StringVar(&cfg.File, "config", "", "path to a YAML or TOML config file")
+	fs.BoolVar(&cfg.PrintConfig, "print-config", false, "print the effective configuration with secrets redacted and exit")
+	flagValues := make(map[string]*string)
+	for _, f := range fields(cfg) {
+		flagValues[f.flag] = fs.String(f.flag, "", f.usage+" (env "+f.env+")")
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if cfg.File == "" {
+		cfg.File, _ = lookupEnv("CONFIG_FILE")
+	}
+	if cfg.File != "" {
+		if err := loadFile(cfg, cfg.File); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+	setFlags := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { setFlags[f.Name] = true })
+
+	for _, f := range fields(cfg) {
+		if raw, ok := lookupEnv(f.env); ok && raw != "" {
+			if err := f.set(raw); err != nil {
+				errs = append(errs, fmt.Errorf("env %s: %w", f.env, err))
+			}
+		}
+		if setFlags[f.flag] {
+			if err := f.set(*flagValues[f.flag]); err != nil {
+				errs = append(errs, fmt.Errorf("flag --%s: %w", f.flag, err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loadFile - читает файл конфигурации, формат определяется по расширению
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, cfg)
+	case ".toml":
+		err = toml.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("config file %s: unsupported format, expected .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Print - выводит итоговую конфигурацию в YAML, секреты скрыты
+func (c *Config) Print(w io.Writer) error {
+	safe := *c
+	for _, f := range fields(&safe) {
+		if p, ok := f.ptr.(*string); ok && f.secret && *p != "" {
+			*p = redacted
+		}
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&safe); err != nil {
+		return err
+	}
+	return enc.Close()
+}
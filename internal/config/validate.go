@@ -0,0 +1,144 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"marketplace-api/internal/logging"
+	"marketplace-api/internal/ratelimit"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const minSecretLength = 16
+
+// Validate - проверяет конфигурацию и возвращает все найденные ошибки разом
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: "+format, append([]any{key}, args...)...))
+		}
+	}
+	positive := func(d time.Duration, key string) {
+		check(d > 0, key, "must be positive, got %s", d)
+	}
+
+	check(c.Env == EnvDev || c.Env == EnvProd, "env", "must be dev or prod, got %q", c.Env)
+
+	s := c.Server
+	check(s.Port > 0 && s.Port <= 65535, "server.port", "must be between 1 and 65535, got %d", s.Port)
+	if u, err := url.Parse(s.PublicURL); err != nil || u.Scheme == "" || u.Host == "" {
+		errs = append(errs, fmt.Errorf("server.public_url: must be an absolute URL, got %q", s.PublicURL))
+	}
+	positive(s.ReadTimeout, "server.read_timeout")
+	positive(s.ReadHeaderTimeout, "server.read_header_timeout")
+	positive(s.WriteTimeout, "server.write_timeout")
+	positive(s.IdleTimeout, "server.idle_timeout")
+	positive(s.ShutdownTimeout, "server.shutdown_timeout")
+	check(s.ShutdownDelay >= 0, "server.shutdown_delay", "must not be negative, got %s", s.ShutdownDelay)
+	positive(s.ReadinessTimeout, "server.readiness_timeout")
+	check(s.MaxHeaderBytes > 0, "server.max_header_bytes", "must be positive")
+	check(s.MaxBodyBytes > 0, "server.max_body_bytes", "must be positive")
+	check((s.TLSCertFile == "") == (s.TLSKeyFile == ""), "server.tls_cert_file", "tls_cert_file and tls_key_file must be set together")
+
+	d := c.Database
+	check(d.DSN != "", "database.dsn", "is required")
+	check(d.MaxConns > 0, "database.max_conns", "must be positive, got %d", d.MaxConns)
+	check(d.MinConns >= 0 && d.MinConns <= d.MaxConns, "database.min_conns", "must be between 0 and max_conns, got %d", d.MinConns)
+	positive(d.MaxConnLifetime, "database.max_conn_lifetime")
+	positive(d.MaxConnIdleTime, "database.max_conn_idle_time")
+	positive(d.HealthCheckPeriod, "database.health_check_period")
+	positive(d.ConnectTimeout, "database.connect_timeout")
+
+	check(len(c.Auth.JWTSecret) >= minSecretLength, "auth.jwt_secret", "is required and must be at least %d characters", minSecretLength)
+	positive(c.Auth.TokenTTL, "auth.token_ttl")
+
+	p := c.Pagination
+	check(p.DefaultLimit > 0, "pagination.default_limit", "must be positive, got %d", p.DefaultLimit)
+	check(p.MaxLimit >= p.DefaultLimit, "pagination.max_limit", "must not be less than default_limit, got %d", p.MaxLimit)
+
+	positive(c.Advertisement.Lifetime, "advertisement.lifetime")
+	for category, lifetime := range c.Advertisement.LifetimeByCategory {
+		positive(lifetime, "advertisement.lifetime_by_category."+category)
+	}
+	positive(c.Advertisement.ExpiryReminder, "advertisement.expiry_reminder")
+	if pageURL := c.Advertisement.PageURL; pageURL != "" {
+		u, err := url.Parse(pageURL)
+		check(err == nil && u.Scheme != "" && u.Host != "" && strings.Contains(pageURL, "{id}"), "advertisement.page_url",
+			"must be an absolute URL containing {id}, got %q", pageURL)
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout", "otlp":
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter: must be none, stdout or otlp, got %q", c.Tracing.Exporter))
+	}
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio", "must be between 0 and 1, got %v", c.Tracing.SampleRatio)
+
+	if _, err := logging.ParseLevel(c.Log.Level); err != nil {
+		errs = append(errs, fmt.Errorf("log.level: must be debug, info, warn or error, got %q", c.Log.Level))
+	}
+	check(c.Log.Format == logging.FormatJSON || c.Log.Format == logging.FormatText, "log.format", "must be json or text, got %q", c.Log.Format)
+
+	check(c.CORS.MaxAge >= 0, "cors.max_age", "must not be negative, got %s", c.CORS.MaxAge)
+	check(c.Compression.MinSize >= 0, "compression.min_size", "must not be negative, got %d", c.Compression.MinSize)
+
+	if c.API.LegacyRoutes {
+		deprecated, errDeprecated := time.Parse(DateLayout, c.API.LegacyDeprecated)
+		check(errDeprecated == nil, "api.legacy_deprecated", "must be a date in YYYY-MM-DD format, got %q", c.API.LegacyDeprecated)
+		sunset, errSunset := time.Parse(DateLayout, c.API.LegacySunset)
+		check(errSunset == nil, "api.legacy_sunset", "must be a date in YYYY-MM-DD format, got %q", c.API.LegacySunset)
+		if errDeprecated == nil && errSunset == nil {
+			check(sunset.After(deprecated), "api.legacy_sunset", "must be after legacy_deprecated")
+		}
+	}
+
+	// Доверенные прокси нужны не только ограничению частоты, но и учёту просмотров
+	if _, err := ratelimit.NewIPResolver(c.RateLimit.TrustedProxies); err != nil {
+		errs = append(errs, fmt.Errorf("rate_limit.trusted_proxies: %w", err))
+	}
+	if rl := c.RateLimit; rl.Enabled {
+		check(rl.Store == "memory" || rl.Store == "postgres", "rate_limit.store", "must be memory or postgres, got %q", rl.Store)
+		for key, spec := range map[string]string{"rate_limit.auth": rl.Auth, "rate_limit.write": rl.Write, "rate_limit.read": rl.Read} {
+			if _, err := ratelimit.ParseLimit(spec); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+			}
+		}
+	}
+
+	if idem := c.Idempotency; idem.Enabled {
+		check(idem.Store == "memory" || idem.Store == "postgres", "idempotency.store", "must be memory or postgres, got %q", idem.Store)
+		positive(idem.TTL, "idempotency.ttl")
+		positive(idem.LockTimeout, "idempotency.lock_timeout")
+		check(idem.Wait >= 0, "idempotency.wait", "must not be negative, got %s", idem.Wait)
+	}
+
+	if fc := c.FeedCache; fc.Enabled {
+		positive(fc.TTL, "feed_cache.ttl")
+		check(fc.Size > 0, "feed_cache.size", "must be positive, got %d", fc.Size)
+	}
+
+	if wh := c.Webhooks; wh.Enabled {
+		positive(wh.Timeout, "webhooks.timeout")
+		check(wh.MaxAttempts > 0, "webhooks.max_attempts", "must be positive, got %d", wh.MaxAttempts)
+		check(wh.DisableAfter > 0, "webhooks.disable_after", "must be positive, got %d", wh.DisableAfter)
+	}
+
+	switch c.Payments.Provider {
+	case PaymentProviderDisabled:
+	case PaymentProviderFake:
+		// Фиктивный провайдер подтверждает любой платёж без оплаты
+		check(c.Env == EnvDev, "payments.provider", "fake provider is allowed only in dev environment")
+	default:
+		errs = append(errs, fmt.Errorf("payments.provider: must be disabled or fake, got %q", c.Payments.Provider))
+	}
+	if c.PaymentsEnabled() {
+		check(c.Payments.WebhookSecret != "", "payments.webhook_secret", "is required when promotions are enabled")
+	}
+	if c.Features.ViewStats {
+		check(len(c.Stats.ViewerSecret) >= minSecretLength, "stats.viewer_secret", "is required when view stats are enabled and must be at least %d characters", minSecretLength)
+	}
+
+	return errors.Join(errs...)
+}
@@ -2,24 +2,50 @@ package db
 
 import (
 	"context"
-	"log"
+	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func Connect(dsn string) *pgxpool.Pool {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// Config - параметры пула соединений
+type Config struct {
+	DSN               string
+	MaxConns          int32
+	MinConns          int32
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+	ConnectTimeout    time.Duration
+	Tracer            pgx.QueryTracer // nil - без трассировки запросов
+}
+
+func Connect(cfg Config) (*pgxpool.Pool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectTimeout)
 	defer cancel()
 
-	pool, err := pgxpool.New(ctx, dsn)
+	poolConfig, err := pgxpool.ParseConfig(cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing database dsn: %w", err)
+	}
+	poolConfig.MaxConns = cfg.MaxConns
+	poolConfig.MinConns = cfg.MinConns
+	poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
+	poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	poolConfig.HealthCheckPeriod = cfg.HealthCheckPeriod
+	poolConfig.ConnConfig.ConnectTimeout = cfg.ConnectTimeout
+	poolConfig.ConnConfig.Tracer = cfg.Tracer
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
-		log.Fatalf("error сonnection pool creation : %v", err)
+		return nil, fmt.Errorf("error сonnection pool creation: %w", err)
 	}
 
 	if err := pool.Ping(ctx); err != nil {
-		log.Fatalf("error database ping : %v", err)
+		pool.Close()
+		return nil, fmt.Errorf("error database ping: %w", err)
 	}
 
-	return pool
+	return pool, nil
 }
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Коды ошибок PostgreSQL, после которых транзакцию можно повторить целиком
+const (
+	codeSerializationFailure = "40001"
+	codeDeadlockDetected     = "40P01"
+)
+
+// Querier - общее у пула и транзакции: репозитории работают с ним и не знают,
+// выполняется ли запрос в транзакции. Begin внутри транзакции создаёт точку сохранения
+type Querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Beginner - источник транзакций, обычно *pgxpool.Pool
+type Beginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+type txKey struct{}
+
+// txState - транзакция, начатая TxManager, и действия после её фиксации
+type txState struct {
+	tx          pgx.Tx
+	afterCommit []func()
+}
+
+// Conn - транзакция из контекста, если запрос выполняется внутри TxManager.WithinTx, иначе fallback
+func Conn(ctx context.Context, fallback Querier) Querier {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx
+	}
+	return fallback
+}
+
+// AfterCommit - выполняет fn после фиксации транзакции из контекста или сразу, если транзакции нет.
+// При откате fn не вызывается
+func AfterCommit(ctx context.Context, fn func()) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.afterCommit = append(state.afterCommit, fn)
+		return
+	}
+	fn()
+}
+
+// TxManager - выполняет функции сервисного слоя в одной транзакции. Репозитории
+// присоединяются к ней через Conn, поэтому сервисам не нужны типы pgx
+type TxManager struct {
+	db          Beginner
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// NewTxManager - транзакции из db; после ошибки сериализации или взаимной блокировки
+// транзакция повторяется, всего не больше maxAttempts попыток
+func NewTxManager(db Beginner, maxAttempts int) *TxManager {
+	return &TxManager{db: db, maxAttempts: max(maxAttempts, 1), backoff: 10 * time.Millisecond}
+}
+
+// WithinTx - выполняет fn в транзакции: фиксирует, если fn вернула nil, иначе откатывает.
+// Вложенный вызов присоединяется к внешней транзакции, а повторы выполняет только внешний,
+// поэтому fn может быть вызвана несколько раз и не должна иметь побочных эффектов вне БД
+// (для них есть AfterCommit)
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*txState); ok {
+		return fn(ctx)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := m.attempt(ctx, fn)
+		if err == nil || attempt >= m.maxAttempts || !IsRetryable(err) {
+			return err
+		}
+
+		// Случайная пауза, чтобы столкнувшиеся транзакции не повторялись синхронно
+		delay := m.backoff<<(attempt-1) + rand.N(m.backoff)
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (m *TxManager) attempt(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := m.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	// Откат после фиксации ничего не делает, а при панике в fn освобождает соединение
+	defer tx.Rollback(context.WithoutCancel(ctx))
+
+	state := &txState{tx: tx}
+	if err := fn(context.WithValue(ctx, txKey{}, state)); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	for _, f := range state.afterCommit {
+		f()
+	}
+	return nil
+}
+
+// IsRetryable - транзакция прервана из-за конфликта с другой и может быть повторена
+func IsRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == codeSerializationFailure || pgErr.Code == codeDeadlockDetected
+}
@@ -0,0 +1,159 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"marketplace-api/internal/db"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTx - транзакция, которая только запоминает фиксацию и откат
+type fakeTx struct {
+	pgx.Tx
+	commitErr  error
+	committed  bool
+	rolledBack bool
+}
+
+func (tx *fakeTx) Commit(context.Context) error {
+	if tx.commitErr != nil {
+		return tx.commitErr
+	}
+	tx.committed = true
+	return nil
+}
+
+func (tx *fakeTx) Rollback(context.Context) error {
+	if !tx.committed {
+		tx.rolledBack = true
+	}
+	return nil
+}
+
+// fakeDB - выдаёт транзакции, первые commitErrs из которых не фиксируются
+type fakeDB struct {
+	db.Querier
+	txs        []*fakeTx
+	commitErrs []error
+}
+
+func (d *fakeDB) Begin(context.Context) (pgx.Tx, error) {
+	tx := &fakeTx{}
+	if len(d.txs) < len(d.commitErrs) {
+		tx.commitErr = d.commitErrs[len(d.txs)]
+	}
+	d.txs = append(d.txs, tx)
+	return tx, nil
+}
+
+var errSerialization = &pgconn.PgError{Code: "40001"}
+
+func TestTxManager_WithinTx(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("фиксация и действия после неё", func(t *testing.T) {
+		fdb := &fakeDB{}
+		var calls []string
+		err := db.NewTxManager(fdb, 3).WithinTx(ctx, func(ctx context.Context) error {
+			assert.Same(t, fdb.txs[0], db.Conn(ctx, fdb))
+			db.AfterCommit(ctx, func() {
+				assert.True(t, fdb.txs[0].committed)
+				calls = append(calls, "after commit")
+			})
+			calls = append(calls, "fn")
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"fn", "after commit"}, calls)
+	})
+
+	t.Run("откат при ошибке", func(t *testing.T) {
+		fdb := &fakeDB{}
+		errFn := errors.New("fn failed")
+		afterCommit := false
+		err := db.NewTxManager(fdb, 3).WithinTx(ctx, func(ctx context.Context) error {
+			db.AfterCommit(ctx, func() { afterCommit = true })
+			return errFn
+		})
+		assert.ErrorIs(t, err, errFn)
+		assert.Len(t, fdb.txs, 1)
+		assert.True(t, fdb.txs[0].rolledBack)
+		assert.False(t, afterCommit)
+	})
+
+	t.Run("откат при панике", func(t *testing.T) {
+		fdb := &fakeDB{}
+		assert.Panics(t, func() {
+			db.NewTxManager(fdb, 3).WithinTx(ctx, func(ctx context.Context) error {
+				panic("boom")
+			})
+		})
+		assert.True(t, fdb.txs[0].rolledBack)
+	})
+
+	t.Run("повтор после ошибки сериализации", func(t *testing.T) {
+		fdb := &fakeDB{}
+		attempts := 0
+		err := db.NewTxManager(fdb, 3).WithinTx(ctx, func(ctx context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return errSerialization
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+		assert.True(t, fdb.txs[2].committed)
+	})
+
+	t.Run("повтор после взаимной блокировки при фиксации", func(t *testing.T) {
+		fdb := &fakeDB{commitErrs: []error{&pgconn.PgError{Code: "40P01"}}}
+		err := db.NewTxManager(fdb, 3).WithinTx(ctx, func(ctx context.Context) error { return nil })
+		assert.NoError(t, err)
+		assert.Len(t, fdb.txs, 2)
+	})
+
+	t.Run("попытки ограничены", func(t *testing.T) {
+		fdb := &fakeDB{}
+		err := db.NewTxManager(fdb, 2).WithinTx(ctx, func(ctx context.Context) error { return errSerialization })
+		assert.ErrorIs(t, err, errSerialization)
+		assert.Len(t, fdb.txs, 2)
+	})
+
+	t.Run("прочие ошибки не повторяются", func(t *testing.T) {
+		fdb := &fakeDB{}
+		err := db.NewTxManager(fdb, 3).WithinTx(ctx, func(ctx context.Context) error {
+			return &pgconn.PgError{Code: "23505"}
+		})
+		assert.Error(t, err)
+		assert.Len(t, fdb.txs, 1)
+	})
+
+	t.Run("вложенный вызов присоединяется к внешней транзакции", func(t *testing.T) {
+		fdb := &fakeDB{}
+		tm := db.NewTxManager(fdb, 3)
+		err := tm.WithinTx(ctx, func(ctx context.Context) error {
+			return tm.WithinTx(ctx, func(ctx context.Context) error {
+				assert.Same(t, fdb.txs[0], db.Conn(ctx, fdb))
+				return nil
+			})
+		})
+		assert.NoError(t, err)
+		assert.Len(t, fdb.txs, 1)
+	})
+}
+
+func TestConn(t *testing.T) {
+	fdb := &fakeDB{}
+	assert.Same(t, fdb, db.Conn(context.Background(), fdb))
+}
+
+func TestAfterCommit(t *testing.T) {
+	called := false
+	db.AfterCommit(context.Background(), func() { called = true })
+	assert.True(t, called)
+}
@@ -0,0 +1,189 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Check - проверка компонента, ошибка означает, что компонент недоступен
+type Check func(ctx context.Context) error
+
+// Database - база данных с точки зрения проверок готовности
+type Database interface {
+	Ping(ctx context.Context) error
+	MigrationVersion(ctx context.Context) (int64, error)
+	PoolStats() PoolStats
+}
+
+type PoolStats struct {
+	TotalConns    int32 `json:"total_conns"`
+	IdleConns     int32 `json:"idle_conns"`
+	AcquiredConns int32 `json:"acquired_conns"`
+	MaxConns      int32 `json:"max_conns"`
+}
+
+type DatabaseStatus struct {
+	Status           string    `json:"status"`
+	Error            string    `json:"error,omitempty"`
+	MigrationVersion int64     `json:"migration_version,omitempty"`
+	Pool             PoolStats `json:"pool"`
+}
+
+type CheckStatus struct {
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// Report - ответ readiness
+type Report struct {
+	Status   string                 `json:"status"` // ready, not_ready или shutting_down
+	Database DatabaseStatus         `json:"database"`
+	Checks   map[string]CheckStatus `json:"checks,omitempty"`
+}
+
+// Registry - набор проверок готовности сервиса
+type Registry struct {
+	db      Database
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	checks map[string]Check
+
+	shuttingDown atomic.Bool
+}
+
+func NewRegistry(db Database, timeout time.Duration) *Registry {
+	return &Registry{db: db, timeout: timeout, checks: make(map[string]Check)}
+}
+
+// Register - добавляет проверку компонента (почта, хранилище файлов, фоновые задачи)
+func (r *Registry) Register(name string, check Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[name] = check
+}
+
+// SetShuttingDown - переводит сервис в состояние "не готов", чтобы балансировщик
+// перестал присылать запросы до начала остановки сервера
+func (r *Registry) SetShuttingDown() {
+	r.shuttingDown.Store(true)
+}
+
+// Ready - выполняет все проверки параллельно с общим таймаутом
+func (r *Registry) Ready(ctx context.Context) Report {
+	if r.shuttingDown.Load() {
+		return Report{Status: "shutting_down", Database: DatabaseStatus{Status: StatusDown}}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	r.mu.RLock()
+	checks := make(map[string]Check, len(r.checks))
+	for name, check := range r.checks {
+		checks[name] = check
+	}
+	r.mu.RUnlock()
+
+	report := Report{Status: "ready", Checks: make(map[string]CheckStatus, len(checks))}
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status := run(ctx, check)
+			mu.Lock()
+			report.Checks[name] = status
+			mu.Unlock()
+		}()
+	}
+
+	report.Database = r.database(ctx)
+	wg.Wait()
+
+	if report.Database.Status != StatusUp {
+		report.Status = "not_ready"
+	}
+	for _, status := range report.Checks {
+		if status.Status != StatusUp {
+			report.Status = "not_ready"
+		}
+	}
+	return report
+}
+
+func (r *Registry) database(ctx context.Context) DatabaseStatus {
+	status := DatabaseStatus{Status: StatusUp, Pool: r.db.PoolStats()}
+	if err := r.db.Ping(ctx); err != nil {
+		status.Status = StatusDown
+		status.Error = err.Error()
+		return status
+	}
+
+	version, err := r.db.MigrationVersion(ctx)
+	if err != nil {
+		status.Status = StatusDown
+		status.Error = err.Error()
+		return status
+	}
+	status.MigrationVersion = version
+	return status
+}
+
+func run(ctx context.Context, check Check) CheckStatus {
+	start := time.Now()
+	err := check(ctx)
+	status := CheckStatus{Status: StatusUp, DurationMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		status.Status = StatusDown
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// Livez - процесс жив и обрабатывает запросы, зависимости не проверяются
+// @Summary Liveness-проба
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /livez [get]
+func (r *Registry) Livez(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"status": "alive"})
+}
+
+// Readyz - сервис готов принимать трафик
+// @Summary Readiness-проба
+// @Description Проверяет базу данных, версию миграций, пул соединений и зарегистрированные компоненты. Во время остановки возвращает 503
+// @Tags health
+// @Produce json
+// @Success 200 {object} Report
+// @Failure 503 {object} Report
+// @Router /readyz [get]
+func (r *Registry) Readyz(w http.ResponseWriter, req *http.Request) {
+	report := r.Ready(req.Context())
+
+	code := http.StatusOK
+	if report.Status != "ready" {
+		code = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(report)
+}
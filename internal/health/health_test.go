@@ -0,0 +1,104 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"marketplace-api/internal/health"
+	mockhealth "marketplace-api/internal/health/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func setupTest(t *testing.T) (*gomock.Controller, *mockhealth.MockDatabase, *health.Registry) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	db := mockhealth.NewMockDatabase(ctrl)
+	return ctrl, db, health.NewRegistry(db, time.Second)
+}
+
+func readyz(t *testing.T, registry *health.Registry) (int, health.Report) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	registry.Readyz(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var report health.Report
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&report))
+	return w.Code, report
+}
+
+func TestRegistry_Readyz(t *testing.T) {
+	stats := health.PoolStats{TotalConns: 3, IdleConns: 2, AcquiredConns: 1, MaxConns: 10}
+
+	t.Run("готов", func(t *testing.T) {
+		ctrl, db, registry := setupTest(t)
+		defer ctrl.Finish()
+
+		db.EXPECT().PoolStats().Return(stats)
+		db.EXPECT().Ping(gomock.Any()).Return(nil)
+		db.EXPECT().MigrationVersion(gomock.Any()).Return(int64(20261019140000), nil)
+		registry.Register("workers", func(context.Context) error { return nil })
+
+		code, report := readyz(t, registry)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "ready", report.Status)
+		assert.Equal(t, int64(20261019140000), report.Database.MigrationVersion)
+		assert.Equal(t, stats, report.Database.Pool)
+		assert.Equal(t, health.StatusUp, report.Checks["workers"].Status)
+	})
+
+	t.Run("не готов: база недоступна", func(t *testing.T) {
+		ctrl, db, registry := setupTest(t)
+		defer ctrl.Finish()
+
+		db.EXPECT().PoolStats().Return(stats)
+		db.EXPECT().Ping(gomock.Any()).Return(errors.New("connection refused"))
+
+		code, report := readyz(t, registry)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "not_ready", report.Status)
+		assert.Equal(t, "connection refused", report.Database.Error)
+	})
+
+	t.Run("не готов: компонент недоступен", func(t *testing.T) {
+		ctrl, db, registry := setupTest(t)
+		defer ctrl.Finish()
+
+		db.EXPECT().PoolStats().Return(stats)
+		db.EXPECT().Ping(gomock.Any()).Return(nil)
+		db.EXPECT().MigrationVersion(gomock.Any()).Return(int64(1), nil)
+		registry.Register("workers", func(context.Context) error { return errors.New("background workers stopped: expirer") })
+
+		code, report := readyz(t, registry)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, health.StatusDown, report.Checks["workers"].Status)
+		assert.Contains(t, report.Checks["workers"].Error, "expirer")
+	})
+
+	t.Run("не готов: идёт остановка", func(t *testing.T) {
+		ctrl, _, registry := setupTest(t)
+		defer ctrl.Finish()
+
+		registry.SetShuttingDown()
+
+		code, report := readyz(t, registry)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "shutting_down", report.Status)
+	})
+}
+
+func TestRegistry_Livez(t *testing.T) {
+	ctrl, _, registry := setupTest(t)
+	defer ctrl.Finish()
+
+	registry.SetShuttingDown()
+	w := httptest.NewRecorder()
+	registry.Livez(w, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
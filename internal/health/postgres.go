@@ -0,0 +1,38 @@
+package health
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Postgres - Database поверх пула pgx, версия миграций берётся из таблицы goose
+type Postgres struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgres(pool *pgxpool.Pool) *Postgres {
+	return &Postgres{pool: pool}
+}
+
+func (p *Postgres) Ping(ctx context.Context) error {
+	return p.pool.Ping(ctx)
+}
+
+func (p *Postgres) MigrationVersion(ctx context.Context) (int64, error) {
+	var version int64
+	err := p.pool.QueryRow(ctx, `
+		SELECT COALESCE(MAX(version_id), 0) FROM goose_db_version WHERE is_applied
+	`).Scan(&version)
+	return version, err
+}
+
+func (p *Postgres) PoolStats() PoolStats {
+	stat := p.pool.Stat()
+	return PoolStats{
+		TotalConns:    stat.TotalConns(),
+		IdleConns:     stat.IdleConns(),
+		AcquiredConns: stat.AcquiredConns(),
+		MaxConns:      stat.MaxConns(),
+	}
+}
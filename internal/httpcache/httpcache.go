@@ -0,0 +1,123 @@
+// Package httpcache - валидаторы ответов (ETag, Last-Modified) и условные запросы (RFC 9110)
+package httpcache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ETag - сильный ETag по телу ответа
+func ETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// EncodedETag - ETag сжатого представления. Сжатое тело побайтно отличается от исходного,
+// поэтому к сильному ETag добавляется кодировка: "abc" -> "abc-gzip". Слабые ETag не меняются
+func EncodedETag(etag, encoding string) string {
+	if !strings.HasPrefix(etag, `"`) || !strings.HasSuffix(etag, `"`) || len(etag) < 2 {
+		return etag
+	}
+	return strings.TrimSuffix(etag, `"`) + "-" + encoding + `"`
+}
+
+// encodingSuffixes - суффиксы EncodedETag, при сравнении отбрасываются: содержимое сжатого
+// и исходного представлений одинаково
+var encodingSuffixes = []string{`-gzip"`, `-br"`}
+
+func stripEncoding(etag string) string {
+	for _, suffix := range encodingSuffixes {
+		if trimmed, ok := strings.CutSuffix(etag, suffix); ok {
+			return trimmed + `"`
+		}
+	}
+	return etag
+}
+
+// Encode - JSON-представление v в том виде, в каком его отдаёт WriteJSON, и его ETag
+func Encode(v any) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), ETag(buf.Bytes()), nil
+}
+
+// WriteJSON - отправляет v с ETag и Last-Modified (если lastModified не нулевое) или 304,
+// если у клиента актуальная копия. Ответ зависит от пользователя, поэтому кэшируется
+// только клиентом и всегда перепроверяется
+func WriteJSON(w http.ResponseWriter, r *http.Request, status int, v any, lastModified time.Time) {
+	body, etag, err := Encode(v)
+	if err != nil {
+		http.Error(w, "error encoding response", http.StatusInternalServerError)
+		return
+	}
+
+	h := w.Header()
+	h.Set("ETag", etag)
+	if !lastModified.IsZero() {
+		h.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	h.Set("Cache-Control", "private, no-cache")
+	h.Add("Vary", "Authorization")
+
+	if status == http.StatusOK && NotModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	h.Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// NotModified - у клиента актуальная копия: совпал If-None-Match или, если его нет,
+// ресурс не менялся после If-Modified-Since. Только для GET и HEAD
+func NotModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return matches(inm, etag, false)
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(ims)
+		return err == nil && !lastModified.Truncate(time.Second).After(t)
+	}
+	return false
+}
+
+// IfMatch - проверка If-Match для изменения ресурса с текущим etag. present - заголовок
+// передан, ok - условие выполнено (или заголовка нет)
+func IfMatch(r *http.Request, etag string) (present, ok bool) {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return false, true
+	}
+	return true, matches(header, etag, true)
+}
+
+// matches - etag входит в список из заголовка. Для If-Match сравнение сильное:
+// слабые ETag (W/) не совпадают ни с чем
+func matches(header, etag string, strong bool) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if strings.HasPrefix(candidate, "W/") {
+			if strong {
+				continue
+			}
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if stripEncoding(candidate) == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package httpcache_test
+
+import (
+	"marketplace-api/internal/httpcache"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type payload struct {
+	Title string `json:"title"`
+}
+
+func TestETag(t *testing.T) {
+	t.Run("одинаковое тело - одинаковый ETag", func(t *testing.T) {
+		assert.Equal(t, httpcache.ETag([]byte("a")), httpcache.ETag([]byte("a")))
+		assert.NotEqual(t, httpcache.ETag([]byte("a")), httpcache.ETag([]byte("b")))
+	})
+
+	t.Run("ETag в кавычках", func(t *testing.T) {
+		etag := httpcache.ETag([]byte("a"))
+		assert.Equal(t, byte('"'), etag[0])
+		assert.Equal(t, byte('"'), etag[len(etag)-1])
+	})
+}
+
+func TestEncodedETag(t *testing.T) {
+	assert.Equal(t, `"abc-gzip"`, httpcache.EncodedETag(`"abc"`, "gzip"))
+	assert.Equal(t, `"abc-br"`, httpcache.EncodedETag(`"abc"`, "br"))
+	assert.Equal(t, `W/"abc"`, httpcache.EncodedETag(`W/"abc"`, "gzip"), "слабый ETag не меняется")
+}
+
+func TestWriteJSON(t *testing.T) {
+	modified := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	v := payload{Title: "ad"}
+	_, etag, err := httpcache.Encode(v)
+	require.NoError(t, err)
+
+	t.Run("полный ответ с валидаторами", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		httpcache.WriteJSON(w, httptest.NewRequest(http.MethodGet, "/", nil), http.StatusOK, v, modified)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+		assert.Equal(t, "Mon, 19 Oct 2026 12:00:00 GMT", w.Header().Get("Last-Modified"))
+		assert.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"title":"ad"}`, w.Body.String())
+	})
+
+	t.Run("без lastModified нет Last-Modified", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		httpcache.WriteJSON(w, httptest.NewRequest(http.MethodGet, "/", nil), http.StatusOK, v, time.Time{})
+		assert.Empty(t, w.Header().Get("Last-Modified"))
+	})
+
+	t.Run("304 по If-None-Match", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("If-None-Match", `"other", W/`+etag)
+		w := httptest.NewRecorder()
+		httpcache.WriteJSON(w, r, http.StatusOK, v, modified)
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("If-None-Match важнее If-Modified-Since", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("If-None-Match", `"other"`)
+		r.Header.Set("If-Modified-Since", modified.Format(http.TimeFormat))
+		w := httptest.NewRecorder()
+		httpcache.WriteJSON(w, r, http.StatusOK, v, modified)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("304 по If-Modified-Since", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("If-Modified-Since", modified.Format(http.TimeFormat))
+		w := httptest.NewRecorder()
+		httpcache.WriteJSON(w, r, http.StatusOK, v, modified.Add(500*time.Millisecond))
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+	})
+
+	t.Run("ресурс изменился после If-Modified-Since", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("If-Modified-Since", modified.Add(-time.Second).Format(http.TimeFormat))
+		w := httptest.NewRecorder()
+		httpcache.WriteJSON(w, r, http.StatusOK, v, modified)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("условия не действуют для изменяющих запросов", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPatch, "/", nil)
+		r.Header.Set("If-None-Match", etag)
+		w := httptest.NewRecorder()
+		httpcache.WriteJSON(w, r, http.StatusOK, v, modified)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestIfMatch(t *testing.T) {
+	etag := httpcache.ETag([]byte("a"))
+	cases := []struct {
+		name            string
+		header          string
+		present, wantOK bool
+	}{
+		{"заголовка нет", "", false, true},
+		{"совпадает", etag, true, true},
+		{"совпадает один из списка", `"other", ` + etag, true, true},
+		{"звёздочка", "*", true, true},
+		{"не совпадает", `"other"`, true, false},
+		{"слабый ETag не совпадает", "W/" + etag, true, false},
+		{"ETag сжатого представления совпадает", httpcache.EncodedETag(etag, "gzip"), true, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPatch, "/", nil)
+			if tc.header != "" {
+				r.Header.Set("If-Match", tc.header)
+			}
+			present, ok := httpcache.IfMatch(r, etag)
+			assert.Equal(t, tc.present, present)
+			assert.Equal(t, tc.wantOK, ok)
+		})
+	}
+}
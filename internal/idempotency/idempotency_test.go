@@ -0,0 +1,255 @@
+package idempotency_test
+
+import (
+	"context"
+	"errors"
+	"marketplace-api/internal/auth"
+	"marketplace-api/internal/idempotency"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRequest(userID uuid.UUID, key, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/advertisement", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(idempotency.Header, key)
+	}
+	return req.WithContext(auth.WithUserID(req.Context(), userID))
+}
+
+// createHandler - считает вызовы и отвечает 201 с номером вызова
+func createHandler(calls *atomic.Int32, delay time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		time.Sleep(delay)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/api/v1/advertisement/"+strconv.Itoa(int(n)))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"call":` + strconv.Itoa(int(n)) + `}`))
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("повтор возвращает сохранённый ответ", func(t *testing.T) {
+		var calls atomic.Int32
+		h := idempotency.Middleware(idempotency.NewMemoryStore(time.Hour, time.Minute), time.Second, "/api/v1")(createHandler(&calls, 0))
+
+		first := httptest.NewRecorder()
+		h.ServeHTTP(first, newRequest(userID, "key-1", `{"title":"bike"}`))
+		assert.Equal(t, http.StatusCreated, first.Code)
+		assert.Empty(t, first.Header().Get(idempotency.ReplayedHeader))
+
+		second := httptest.NewRecorder()
+		h.ServeHTTP(second, newRequest(userID, "key-1", `{"title":"bike"}`))
+		assert.Equal(t, http.StatusCreated, second.Code)
+		assert.Equal(t, "true", second.Header().Get(idempotency.ReplayedHeader))
+		assert.Equal(t, first.Header().Get("Location"), second.Header().Get("Location"))
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("ключи раздельные для пользователей", func(t *testing.T) {
+		var calls atomic.Int32
+		h := idempotency.Middleware(idempotency.NewMemoryStore(time.Hour, time.Minute), time.Second, "/api/v1")(createHandler(&calls, 0))
+
+		h.ServeHTTP(httptest.NewRecorder(), newRequest(userID, "key-1", `{}`))
+		h.ServeHTTP(httptest.NewRecorder(), newRequest(uuid.New(), "key-1", `{}`))
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("без ключа запросы не дедуплицируются", func(t *testing.T) {
+		var calls atomic.Int32
+		h := idempotency.Middleware(idempotency.NewMemoryStore(time.Hour, time.Minute), time.Second, "/api/v1")(createHandler(&calls, 0))
+
+		h.ServeHTTP(httptest.NewRecorder(), newRequest(userID, "", `{}`))
+		h.ServeHTTP(httptest.NewRecorder(), newRequest(userID, "", `{}`))
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("ошибка: тот же ключ с другим телом", func(t *testing.T) {
+		var calls atomic.Int32
+		h := idempotency.Middleware(idempotency.NewMemoryStore(time.Hour, time.Minute), time.Second, "/api/v1")(createHandler(&calls, 0))
+
+		h.ServeHTTP(httptest.NewRecorder(), newRequest(userID, "key-1", `{"title":"bike"}`))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newRequest(userID, "key-1", `{"title":"car"}`))
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("повтор через псевдоним без версии совпадает с запросом к /api/v1", func(t *testing.T) {
+		var calls atomic.Int32
+		idempotent := idempotency.Middleware(idempotency.NewMemoryStore(time.Hour, time.Minute), time.Second, "/api/v1")
+		mux := http.NewServeMux()
+		mux.Handle("POST /api/v1/advertisement", idempotent(createHandler(&calls, 0)))
+		mux.Handle("POST /advertisement", idempotent(createHandler(&calls, 0)))
+		mux.Handle("POST /api/v1/promotion", idempotent(createHandler(&calls, 0)))
+
+		mux.ServeHTTP(httptest.NewRecorder(), newRequest(userID, "key-1", `{"title":"bike"}`))
+
+		legacy := newRequest(userID, "key-1", `{"title":"bike"}`)
+		legacy.URL.Path = "/advertisement"
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, legacy)
+		assert.Equal(t, "true", w.Header().Get(idempotency.ReplayedHeader))
+
+		other := newRequest(userID, "key-1", `{"title":"bike"}`)
+		other.URL.Path = "/api/v1/promotion"
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, other)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, "тот же ключ на другом маршруте")
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("одновременный повтор ждёт первый запрос", func(t *testing.T) {
+		var calls atomic.Int32
+		h := idempotency.Middleware(idempotency.NewMemoryStore(time.Hour, time.Minute), time.Second, "/api/v1")(createHandler(&calls, 200*time.Millisecond))
+
+		var wg sync.WaitGroup
+		recorders := []*httptest.ResponseRecorder{httptest.NewRecorder(), httptest.NewRecorder()}
+		for i, w := range recorders {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				time.Sleep(time.Duration(i) * 50 * time.Millisecond)
+				h.ServeHTTP(w, newRequest(userID, "key-1", `{}`))
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Equal(t, http.StatusCreated, recorders[1].Code)
+		assert.Equal(t, recorders[0].Body.String(), recorders[1].Body.String())
+		assert.Equal(t, "true", recorders[1].Header().Get(idempotency.ReplayedHeader))
+	})
+
+	t.Run("ошибка: первый запрос не успел завершиться", func(t *testing.T) {
+		var calls atomic.Int32
+		h := idempotency.Middleware(idempotency.NewMemoryStore(time.Hour, time.Minute), 0, "/api/v1")(createHandler(&calls, 200*time.Millisecond))
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			h.ServeHTTP(httptest.NewRecorder(), newRequest(userID, "key-1", `{}`))
+		}()
+		time.Sleep(50 * time.Millisecond)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newRequest(userID, "key-1", `{}`))
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "1", w.Header().Get("Retry-After"))
+		<-done
+	})
+
+	t.Run("ответ 5xx не сохраняется", func(t *testing.T) {
+		var calls atomic.Int32
+		h := idempotency.Middleware(idempotency.NewMemoryStore(time.Hour, time.Minute), time.Second, "/api/v1")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				http.Error(w, "database is down", http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newRequest(userID, "key-1", `{}`))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, newRequest(userID, "key-1", `{}`))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("паника освобождает ключ", func(t *testing.T) {
+		store := idempotency.NewMemoryStore(time.Hour, time.Minute)
+		h := idempotency.Middleware(store, time.Second, "/api/v1")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		}))
+
+		assert.Panics(t, func() {
+			h.ServeHTTP(httptest.NewRecorder(), newRequest(userID, "key-1", `{}`))
+		})
+		_, created, err := store.Begin(context.Background(), userID, "key-1", "other")
+		require.NoError(t, err)
+		assert.True(t, created)
+	})
+
+	t.Run("ошибка: слишком длинный ключ", func(t *testing.T) {
+		var calls atomic.Int32
+		h := idempotency.Middleware(idempotency.NewMemoryStore(time.Hour, time.Minute), time.Second, "/api/v1")(createHandler(&calls, 0))
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newRequest(userID, strings.Repeat("k", 256), `{}`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Zero(t, calls.Load())
+	})
+
+	t.Run("ошибка: хранилище недоступно", func(t *testing.T) {
+		var calls atomic.Int32
+		h := idempotency.Middleware(failingStore{}, time.Second, "/api/v1")(createHandler(&calls, 0))
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newRequest(userID, "key-1", `{}`))
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Zero(t, calls.Load())
+	})
+}
+
+func TestMemoryStore_Expiry(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("истёкший ключ занимается заново", func(t *testing.T) {
+		store := idempotency.NewMemoryStore(10*time.Millisecond, time.Minute)
+		_, created, _ := store.Begin(ctx, userID, "k", "a")
+		require.True(t, created)
+		require.NoError(t, store.Complete(ctx, userID, "k", idempotency.Response{Status: http.StatusCreated}))
+
+		time.Sleep(20 * time.Millisecond)
+		rec, created, err := store.Begin(ctx, userID, "k", "b")
+		require.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, "b", rec.Fingerprint)
+	})
+
+	t.Run("брошенный запрос отпускает ключ", func(t *testing.T) {
+		store := idempotency.NewMemoryStore(time.Hour, 10*time.Millisecond)
+		_, created, _ := store.Begin(ctx, userID, "k", "a")
+		require.True(t, created)
+
+		_, created, _ = store.Begin(ctx, userID, "k", "a")
+		assert.False(t, created)
+
+		time.Sleep(20 * time.Millisecond)
+		_, created, _ = store.Begin(ctx, userID, "k", "a")
+		assert.True(t, created)
+	})
+}
+
+type failingStore struct{}
+
+func (failingStore) Begin(context.Context, uuid.UUID, string, string) (idempotency.Record, bool, error) {
+	return idempotency.Record{}, false, errors.New("connection refused")
+}
+
+func (failingStore) Complete(context.Context, uuid.UUID, string, idempotency.Response) error {
+	return errors.New("connection refused")
+}
+
+func (failingStore) Release(context.Context, uuid.UUID, string) error {
+	return errors.New("connection refused")
+}
@@ -0,0 +1,84 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemoryStore - ключи в памяти процесса, для одного экземпляра сервиса
+type MemoryStore struct {
+	ttl         time.Duration
+	lockTimeout time.Duration
+
+	mu      sync.Mutex
+	records map[memoryKey]*memoryRecord
+}
+
+type memoryKey struct {
+	userID uuid.UUID
+	key    string
+}
+
+type memoryRecord struct {
+	Record
+	createdAt time.Time
+}
+
+func NewMemoryStore(ttl, lockTimeout time.Duration) *MemoryStore {
+	return &MemoryStore{ttl: ttl, lockTimeout: lockTimeout, records: make(map[memoryKey]*memoryRecord)}
+}
+
+func (s *MemoryStore) Begin(_ context.Context, userID uuid.UUID, key, fingerprint string) (Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	k := memoryKey{userID: userID, key: key}
+	if rec, ok := s.records[k]; ok && !s.expired(rec, now) {
+		return rec.Record, false, nil
+	}
+	s.records[k] = &memoryRecord{Record: Record{Fingerprint: fingerprint}, createdAt: now}
+	return Record{Fingerprint: fingerprint}, true, nil
+}
+
+func (s *MemoryStore) Complete(_ context.Context, userID uuid.UUID, key string, resp Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.records[memoryKey{userID: userID, key: key}]
+	if !ok {
+		return errKeyReleased
+	}
+	rec.Response = &resp
+	return nil
+}
+
+func (s *MemoryStore) Release(_ context.Context, userID uuid.UUID, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, memoryKey{userID: userID, key: key})
+	return nil
+}
+
+// Cleanup - удаляет истёкшие ключи
+func (s *MemoryStore) Cleanup(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for k, rec := range s.records {
+		if s.expired(rec, now) {
+			delete(s.records, k)
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) expired(rec *memoryRecord, now time.Time) bool {
+	age := now.Sub(rec.createdAt)
+	return age >= s.ttl || (rec.Response == nil && age >= s.lockTimeout)
+}
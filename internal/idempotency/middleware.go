@@ -0,0 +1,186 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"marketplace-api/internal/auth"
+	"marketplace-api/internal/logging"
+	"marketplace-api/internal/problem"
+	"marketplace-api/internal/server"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	Header         = "Idempotency-Key"
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+	pollInterval = 100 * time.Millisecond
+)
+
+// Middleware - для POST-запросов с заголовком Idempotency-Key сохраняет первый ответ и
+// повторяет его на запросы с тем же ключом. Ключи раздельные для каждого пользователя,
+// поэтому middleware стоит после авторизации. Повтор, пришедший пока первый запрос
+// выполняется, ждёт до wait и получает 409; тот же ключ с другим телом - 422.
+// prefix - префикс версии API: маршрут под ним и его псевдоним без версии считаются одним
+func Middleware(store Store, wait time.Duration, prefix string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(Header)
+			userID, authorized := auth.UserIDFromContext(r.Context())
+			if key == "" || r.Method != http.MethodPost || !authorized {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxKeyLength {
+				problem.Write(w, r, http.StatusBadRequest, "Idempotency-Key must not be longer than 255 characters")
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					problem.Write(w, r, http.StatusRequestEntityTooLarge, "request body too large")
+					return
+				}
+				problem.Write(w, r, http.StatusBadRequest, "error reading request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			fp := fingerprint(r, prefix, body)
+			logger := logging.FromContext(r.Context())
+			deadline := time.Now().Add(wait)
+
+			for {
+				rec, created, err := store.Begin(r.Context(), userID, key, fp)
+				switch {
+				case err != nil:
+					logger.Error("error reserving idempotency key", "error", err)
+					problem.Write(w, r, http.StatusServiceUnavailable, "idempotency store unavailable")
+					return
+				case created:
+					serve(store, next, w, r, key)
+					return
+				case rec.Fingerprint != fp:
+					problem.Write(w, r, http.StatusUnprocessableEntity, "Idempotency-Key was already used with a different request")
+					return
+				case rec.Response != nil:
+					replay(w, rec.Response)
+					return
+				case time.Now().After(deadline):
+					w.Header().Set("Retry-After", "1")
+					problem.Write(w, r, http.StatusConflict, "a request with this Idempotency-Key is still in progress")
+					return
+				}
+
+				select {
+				case <-r.Context().Done():
+					return
+				case <-time.After(pollInterval):
+				}
+			}
+		})
+	}
+}
+
+// serve - выполняет первый запрос и сохраняет ответ. Ответы 5xx и паника освобождают ключ,
+// чтобы клиент мог повторить запрос
+func serve(store Store, next http.Handler, w http.ResponseWriter, r *http.Request, key string) {
+	userID, _ := auth.UserIDFromContext(r.Context())
+	ctx := context.WithoutCancel(r.Context())
+	logger := logging.FromContext(ctx)
+
+	rec := &recorder{ResponseWriter: w, header: http.Header{}}
+	defer func() {
+		if p := recover(); p != nil {
+			if err := store.Release(ctx, userID, key); err != nil {
+				logger.Error("error releasing idempotency key", "error", err)
+			}
+			panic(p)
+		}
+	}()
+	next.ServeHTTP(rec, r)
+
+	if rec.status >= http.StatusInternalServerError {
+		if err := store.Release(ctx, userID, key); err != nil {
+			logger.Error("error releasing idempotency key", "error", err)
+		}
+		return
+	}
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	err := store.Complete(ctx, userID, key, Response{Status: rec.status, Header: rec.header, Body: rec.body.Bytes()})
+	if err != nil {
+		logger.Error("error saving idempotent response", "error", err)
+	}
+}
+
+func replay(w http.ResponseWriter, resp *Response) {
+	for name, values := range resp.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(resp.Status)
+	w.Write(resp.Body)
+}
+
+// fingerprint - хеш метода, маршрута и тела: тот же ключ с другим запросом отклоняется.
+// Берётся шаблон маршрута, а не путь: повтор через псевдоним без версии - тот же запрос
+func fingerprint(r *http.Request, prefix string, body []byte) string {
+	route := server.Route(r)
+	if route == "" {
+		route = r.URL.Path
+	}
+	if prefix != "" && (route == prefix || strings.HasPrefix(route, prefix+"/")) {
+		route = route[len(prefix):]
+	}
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+route+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recorder - передаёт ответ клиенту и запоминает его. Заголовки обработчика собираются
+// отдельно от выставленных внешними middleware, сохраняются только они
+type recorder struct {
+	http.ResponseWriter
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *recorder) Header() http.Header {
+	return rec.header
+}
+
+func (rec *recorder) WriteHeader(code int) {
+	if rec.status != 0 {
+		return
+	}
+	rec.status = code
+	for name, values := range rec.header {
+		rec.ResponseWriter.Header()[name] = values
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *recorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.WriteHeader(http.StatusOK)
+	}
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+// Unwrap - для http.ResponseController
+func (rec *recorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
@@ -0,0 +1,113 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresStore - ключи в таблице idempotency_keys, общие для всех экземпляров сервиса
+type PostgresStore struct {
+	pool        *pgxpool.Pool
+	ttl         time.Duration
+	lockTimeout time.Duration
+}
+
+func NewPostgresStore(pool *pgxpool.Pool, ttl, lockTimeout time.Duration) *PostgresStore {
+	return &PostgresStore{pool: pool, ttl: ttl, lockTimeout: lockTimeout}
+}
+
+func (s *PostgresStore) Begin(ctx context.Context, userID uuid.UUID, key, fingerprint string) (Record, bool, error) {
+	// Истёкшая или брошенная запись перезанимается тем же запросом
+	insert := `
+		INSERT INTO idempotency_keys AS k (user_id, key, fingerprint, created_at)
+		VALUES ($1, $2, $3, now())
+		ON CONFLICT (user_id, key) DO UPDATE SET
+			fingerprint = EXCLUDED.fingerprint,
+			created_at = EXCLUDED.created_at,
+			response_status = NULL,
+			response_header = NULL,
+			response_body = NULL
+		WHERE k.created_at < now() - make_interval(secs => $4)
+			OR (k.response_status IS NULL AND k.created_at < now() - make_interval(secs => $5))
+		RETURNING true`
+
+	selectExisting := `
+		SELECT fingerprint, response_status, response_header, response_body
+		FROM idempotency_keys
+		WHERE user_id = $1 AND key = $2`
+
+	// Вторая попытка - если запись отпустили между INSERT и SELECT
+	for range 2 {
+		var inserted bool
+		err := s.pool.QueryRow(ctx, insert, userID, key, fingerprint, s.ttl.Seconds(), s.lockTimeout.Seconds()).Scan(&inserted)
+		if err == nil {
+			return Record{Fingerprint: fingerprint}, true, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return Record{}, false, err
+		}
+
+		var rec Record
+		var status *int
+		var header []byte
+		var body []byte
+		err = s.pool.QueryRow(ctx, selectExisting, userID, key).Scan(&rec.Fingerprint, &status, &header, &body)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return Record{}, false, err
+		}
+		if status != nil {
+			rec.Response = &Response{Status: *status, Body: body}
+			if err := json.Unmarshal(header, &rec.Response.Header); err != nil {
+				return Record{}, false, err
+			}
+		}
+		return rec, false, nil
+	}
+	return Record{}, false, errKeyReleased
+}
+
+func (s *PostgresStore) Complete(ctx context.Context, userID uuid.UUID, key string, resp Response) error {
+	header := resp.Header
+	if header == nil {
+		header = http.Header{}
+	}
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE idempotency_keys
+		SET response_status = $3, response_header = $4, response_body = $5
+		WHERE user_id = $1 AND key = $2`
+
+	tag, err := s.pool.Exec(ctx, query, userID, key, resp.Status, headerJSON, resp.Body)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errKeyReleased
+	}
+	return nil
+}
+
+func (s *PostgresStore) Release(ctx context.Context, userID uuid.UUID, key string) error {
+	_, err := s.pool.Exec(ctx, `DELETE FROM idempotency_keys WHERE user_id = $1 AND key = $2 AND response_status IS NULL`, userID, key)
+	return err
+}
+
+// Cleanup - удаляет истёкшие ключи
+func (s *PostgresStore) Cleanup(ctx context.Context) error {
+	_, err := s.pool.Exec(ctx, `DELETE FROM idempotency_keys WHERE created_at < now() - make_interval(secs => $1)`, s.ttl.Seconds())
+	return err
+}
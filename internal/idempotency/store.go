@@ -0,0 +1,72 @@
+// Package idempotency - повтор ответа на запрос с тем же заголовком Idempotency-Key
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"marketplace-api/internal/logging"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Response - сохранённый ответ на первый запрос. Header содержит только заголовки,
+// выставленные обработчиком
+type Response struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+// Record - состояние ключа. Response равен nil, пока первый запрос выполняется
+type Record struct {
+	Fingerprint string
+	Response    *Response
+}
+
+// Store - хранилище ключей. Ключи живут ttl с момента первого запроса; незавершённый
+// запрос, не отпустивший ключ за lockTimeout (экземпляр упал), перестаёт его удерживать
+type Store interface {
+	// Begin - занимает ключ. Если ключ уже занят, возвращает его запись и false
+	Begin(ctx context.Context, userID uuid.UUID, key, fingerprint string) (Record, bool, error)
+	// Complete - сохраняет ответ для повторов
+	Complete(ctx context.Context, userID uuid.UUID, key string, resp Response) error
+	// Release - освобождает ключ без ответа, чтобы запрос можно было повторить
+	Release(ctx context.Context, userID uuid.UUID, key string) error
+}
+
+// Cleaner - удаление истёкших ключей
+type Cleaner interface {
+	Cleanup(ctx context.Context) error
+}
+
+var errKeyReleased = errors.New("idempotency key released concurrently")
+
+// Janitor - фоновая задача: периодически удаляет истёкшие ключи
+type Janitor struct {
+	store    Cleaner
+	interval time.Duration
+}
+
+func NewJanitor(store Cleaner, interval time.Duration) *Janitor {
+	return &Janitor{store: store, interval: interval}
+}
+
+// Run - запускает периодическую очистку до отмены ctx
+func (j *Janitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := j.store.Cleanup(ctx); err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).Error("error cleaning up idempotency keys", "error", err)
+		}
+	}
+}
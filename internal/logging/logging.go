@@ -0,0 +1,99 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+
+	redacted = "[REDACTED]"
+)
+
+// sensitiveKeys - части имён атрибутов, значения которых не попадают в лог
+var sensitiveKeys = []string{"password", "token", "secret", "authorization", "cookie", "dsn"}
+
+// Setup - создаёт логгер и делает его логгером по умолчанию, в том числе для пакета log
+func Setup(w io.Writer, format string, level slog.Level) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: redact}
+
+	var handler slog.Handler
+	switch format {
+	case FormatJSON, "":
+		handler = slog.NewJSONHandler(w, opts)
+	case FormatText:
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+
+	logger := slog.New(handler)
+	slog.SetDefault(logger)
+	return logger, nil
+}
+
+// ParseLevel - debug, info, warn или error
+func ParseLevel(raw string) (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(raw))
+	return level, err
+}
+
+func redact(_ []string, a slog.Attr) slog.Attr {
+	key := strings.ToLower(a.Key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return slog.String(a.Key, redacted)
+		}
+	}
+	return a
+}
+
+type ctxKey struct{}
+
+// scope - логгер запроса, дополняется по мере обработки (например, user_id после авторизации)
+type scope struct {
+	mu     sync.Mutex
+	logger *slog.Logger
+	attrs  []any
+}
+
+// WithLogger - кладёт логгер в контекст
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, &scope{logger: logger})
+}
+
+// FromContext - логгер запроса или логгер по умолчанию
+func FromContext(ctx context.Context) *slog.Logger {
+	if s, ok := ctx.Value(ctxKey{}).(*scope); ok {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.logger
+	}
+	return slog.Default()
+}
+
+// AddAttrs - добавляет атрибуты к логгеру запроса и к итоговой записи access-лога
+func AddAttrs(ctx context.Context, args ...any) {
+	if s, ok := ctx.Value(ctxKey{}).(*scope); ok {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.logger = s.logger.With(args...)
+		s.attrs = append(s.attrs, args...)
+	}
+}
+
+func attrsFromContext(ctx context.Context) []any {
+	if s, ok := ctx.Value(ctxKey{}).(*scope); ok {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return append([]any(nil), s.attrs...)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package logging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"marketplace-api/internal/logging"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTest(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	prev := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	var buf bytes.Buffer
+	_, err := logging.Setup(&buf, logging.FormatJSON, slog.LevelDebug)
+	require.NoError(t, err)
+	return &buf
+}
+
+func lastEntry(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &entry))
+	return entry
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("запрос с X-Request-ID", func(t *testing.T) {
+		buf := setupTest(t)
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/advertisement/", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "abc-123", logging.RequestIDFromContext(r.Context()))
+			logging.AddAttrs(r.Context(), "user_id", "42")
+			logging.FromContext(r.Context()).Info("inside handler")
+			w.WriteHeader(http.StatusTeapot)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/advertisement/1", nil)
+		req.Header.Set(logging.RequestIDHeader, "abc-123")
+		w := httptest.NewRecorder()
+		logging.Middleware(mux).ServeHTTP(w, req)
+
+		assert.Equal(t, "abc-123", w.Header().Get(logging.RequestIDHeader))
+		assert.Contains(t, buf.String(), `"msg":"inside handler"`)
+
+		entry := lastEntry(t, buf)
+		assert.Equal(t, "http request", entry["msg"])
+		assert.Equal(t, "abc-123", entry["request_id"])
+		assert.Equal(t, "/advertisement/", entry["route"])
+		assert.Equal(t, float64(http.StatusTeapot), entry["status"])
+		assert.Equal(t, "42", entry["user_id"])
+	})
+
+	t.Run("некорректный X-Request-ID заменяется", func(t *testing.T) {
+		setupTest(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(logging.RequestIDHeader, "bad id\nwith newline")
+		w := httptest.NewRecorder()
+		logging.Middleware(http.NotFoundHandler()).ServeHTTP(w, req)
+
+		id := w.Header().Get(logging.RequestIDHeader)
+		assert.Len(t, id, 36)
+	})
+}
+
+func TestRedaction(t *testing.T) {
+	buf := setupTest(t)
+
+	slog.Info("login", "login", "Sanches", "password", "Syperpassword1", "access_token", "eyJ...", slog.Group("config", "jwt_secret", "s"))
+
+	out := buf.String()
+	assert.Contains(t, out, "Sanches")
+	assert.NotContains(t, out, "Syperpassword1")
+	assert.NotContains(t, out, "eyJ")
+	assert.NotContains(t, out, `"jwt_secret":"s"`)
+}
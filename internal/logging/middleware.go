@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"marketplace-api/internal/server"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// Входящий идентификатор принимается, только если он не может испортить лог
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+type requestIDKey struct{}
+
+// RequestIDFromContext - идентификатор текущего запроса
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Middleware - назначает или принимает X-Request-ID, кладёт логгер запроса в контекст
+// и пишет строку access-лога по завершении запроса
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestID := r.Header.Get(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		logger := slog.Default().With("request_id", requestID)
+		if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+			logger = logger.With("trace_id", sc.TraceID().String())
+		}
+
+		ctx := context.WithValue(r.Context(), requestIDKey{}, requestID)
+		ctx = WithLogger(ctx, logger)
+		req := r.WithContext(ctx)
+		rec := server.NewStatusRecorder(w)
+
+		next.ServeHTTP(rec, req)
+
+		level := slog.LevelInfo
+		if rec.Status() >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		args := append([]any{
+			"method", r.Method,
+			"route", server.Route(req),
+			"path", r.URL.Path,
+			"status", rec.Status(),
+			"latency_ms", time.Since(start).Milliseconds(),
+		}, attrsFromContext(ctx)...)
+		logger.Log(ctx, level, "http request", args...)
+	})
+}
@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "marketplace"
+
+// Registry - реестр метрик приложения, отдаётся на /metrics
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	httpRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route pattern, method and status.",
+	}, []string{"route", "method", "status"})
+
+	httpDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route pattern, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	registrations = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "user_registrations_total",
+		Help:      "Successful user registrations.",
+	})
+
+	logins = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "user_logins_total",
+		Help:      "Login attempts by result.",
+	}, []string{"result"})
+
+	advertisementsCreated = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "advertisements_created_total",
+		Help:      "Created advertisements.",
+	})
+
+	jwtFailures = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "jwt_validation_failures_total",
+		Help:      "Rejected bearer tokens by reason.",
+	}, []string{"reason"})
+
+	apiKeyRejections = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "api_key_rejections_total",
+		Help:      "Rejected API keys by reason.",
+	}, []string{"reason"})
+
+	outboxDeliveries = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "outbox_deliveries_total",
+		Help:      "Outbox event delivery attempts by result: published, retry or failed.",
+	}, []string{"result"})
+
+	webhookDeliveries = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_deliveries_total",
+		Help:      "Outgoing webhook delivery attempts by result: succeeded, retry or failed.",
+	}, []string{"result"})
+
+	cacheLookups = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_lookups_total",
+		Help:      "Cache lookups by cache name and result.",
+	}, []string{"cache", "result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler - обработчик /metrics
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// RegistrationCompleted - пользователь зарегистрирован
+func RegistrationCompleted() {
+	registrations.Inc()
+}
+
+// LoginAttempt - попытка входа, succeeded - выдан ли токен
+func LoginAttempt(succeeded bool) {
+	if succeeded {
+		logins.WithLabelValues("succeeded").Inc()
+		return
+	}
+	logins.WithLabelValues("failed").Inc()
+}
+
+// AdvertisementCreated - создано объявление
+func AdvertisementCreated() {
+	advertisementsCreated.Inc()
+}
+
+// JWTValidationFailed - токен отклонён, reason - короткая причина (expired, malformed, ...)
+func JWTValidationFailed(reason string) {
+	jwtFailures.WithLabelValues(reason).Inc()
+}
+
+// CacheLookup - обращение к кэшу name, hit - значение найдено
+func CacheLookup(name string, hit bool) {
+	if hit {
+		cacheLookups.WithLabelValues(name, "hit").Inc()
+		return
+	}
+	cacheLookups.WithLabelValues(name, "miss").Inc()
+}
+
+// APIKeyRejected - отклонённый API-ключ, reason - unknown, revoked или expired
+func APIKeyRejected(reason string) {
+	apiKeyRejections.WithLabelValues(reason).Inc()
+}
+
+// OutboxDelivery - попытка доставки события из outbox, result - published, retry или failed
+func OutboxDelivery(result string) {
+	outboxDeliveries.WithLabelValues(result).Inc()
+}
+
+// WebhookDelivery - попытка доставки уведомления партнёру, result - succeeded, retry или failed
+func WebhookDelivery(result string) {
+	webhookDeliveries.WithLabelValues(result).Inc()
+}
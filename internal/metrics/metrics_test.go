@@ -0,0 +1,66 @@
+package metrics_test
+
+import (
+	"io"
+	"marketplace-api/internal/metrics"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	body, err := io.ReadAll(w.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/advertisement/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	handler := metrics.Middleware(mux)
+
+	// Запросы к разным id попадают в один ряд по шаблону маршрута
+	for _, path := range []string{"/advertisement/1", "/advertisement/2", "/unknown"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	body := scrape(t)
+	assert.Contains(t, body, `marketplace_http_requests_total{method="GET",route="/advertisement/",status="404"} 2`)
+	assert.Contains(t, body, `marketplace_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, body, `marketplace_http_request_duration_seconds_count{method="GET",route="/advertisement/",status="404"} 2`)
+}
+
+func TestBusinessCounters(t *testing.T) {
+	metrics.RegistrationCompleted()
+	metrics.LoginAttempt(true)
+	metrics.LoginAttempt(false)
+	metrics.AdvertisementCreated()
+	metrics.JWTValidationFailed("expired")
+	metrics.APIKeyRejected("revoked")
+	metrics.OutboxDelivery("published")
+	metrics.WebhookDelivery("retry")
+	metrics.CacheLookup("test", true)
+	metrics.CacheLookup("test", false)
+	metrics.CacheLookup("test", false)
+
+	body := scrape(t)
+	assert.Contains(t, body, "marketplace_user_registrations_total 1")
+	assert.Contains(t, body, `marketplace_user_logins_total{result="succeeded"} 1`)
+	assert.Contains(t, body, `marketplace_user_logins_total{result="failed"} 1`)
+	assert.Contains(t, body, "marketplace_advertisements_created_total 1")
+	assert.Contains(t, body, `marketplace_jwt_validation_failures_total{reason="expired"} 1`)
+	assert.Contains(t, body, `marketplace_api_key_rejections_total{reason="revoked"} 1`)
+	assert.Contains(t, body, `marketplace_outbox_deliveries_total{result="published"} 1`)
+	assert.Contains(t, body, `marketplace_webhook_deliveries_total{result="retry"} 1`)
+	assert.Contains(t, body, `marketplace_cache_lookups_total{cache="test",result="hit"} 1`)
+	assert.Contains(t, body, `marketplace_cache_lookups_total{cache="test",result="miss"} 2`)
+}
@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"marketplace-api/internal/server"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Middleware - считает запросы и время ответа. Метка route - шаблон маршрута из ServeMux,
+// а не путь, чтобы идентификаторы в URL не раздували число рядов
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := server.NewStatusRecorder(w)
+
+		next.ServeHTTP(rec, r)
+
+		route := server.Route(r)
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(rec.Status())
+		httpRequests.WithLabelValues(route, r.Method, status).Inc()
+		httpDuration.WithLabelValues(route, r.Method, status).Observe(time.Since(start).Seconds())
+	})
+}
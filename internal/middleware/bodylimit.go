@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"marketplace-api/internal/problem"
+	"net/http"
+)
+
+// BodyLimit - ограничивает размер тела запроса, чтение сверх лимита возвращает *http.MaxBytesError
+func BodyLimit(maxBytes int64) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > maxBytes {
+				w.Header().Set("Connection", "close")
+				problem.Write(w, r, http.StatusRequestEntityTooLarge, "request body too large")
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,19 @@
+// Package middleware - общие middleware HTTP-сервера и их сборка в цепочку
+package middleware
+
+import "net/http"
+
+type Middleware func(http.Handler) http.Handler
+
+// Chain - объединяет middleware, первый в списке выполняется первым (самый внешний).
+// nil пропускаются, так выключенная возможность не требует заглушки
+func Chain(middlewares ...Middleware) Middleware {
+	return func(next http.Handler) http.Handler {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			if middlewares[i] != nil {
+				next = middlewares[i](next)
+			}
+		}
+		return next
+	}
+}
@@ -0,0 +1,223 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"marketplace-api/internal/httpcache"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+)
+
+var (
+	gzipPool   = sync.Pool{New: func() any { return gzip.NewWriter(io.Discard) }}
+	brotliPool = sync.Pool{New: func() any { return brotli.NewWriterLevel(io.Discard, brotli.DefaultCompression) }}
+)
+
+// compressibleTypes - сжимаются только текстовые ответы, картинки и архивы уже сжаты
+var compressibleTypes = []string{"application/json", "application/problem+json", "application/xml", "application/javascript", "text/"}
+
+// Compress - сжатие ответа gzip или brotli по заголовку Accept-Encoding.
+// Ответы меньше minSize байт отправляются без сжатия
+func Compress(minSize int) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, encoding: encoding, minSize: minSize, status: http.StatusOK,
+				ifNoneMatch: r.Header.Get("If-None-Match")}
+			defer cw.Close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding - brotli предпочтительнее gzip при равном q
+func negotiateEncoding(header string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != encodingBrotli && name != encodingGzip {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > bestQ || (q == bestQ && q > 0 && name == encodingBrotli) {
+			best, bestQ = name, q
+		}
+	}
+	if bestQ == 0 {
+		return ""
+	}
+	return best
+}
+
+// compressWriter - копит начало ответа, пока не станет ясно, стоит ли его сжимать
+type compressWriter struct {
+	http.ResponseWriter
+	encoding    string
+	minSize     int
+	ifNoneMatch string
+
+	status      int
+	wroteHeader bool
+	decided     bool
+	buf         []byte
+	encoder     io.WriteCloser
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+	cw.status = code
+	// Информационные ответы и ответы без тела отправляются сразу
+	if code < http.StatusOK || code == http.StatusNoContent || code == http.StatusNotModified {
+		cw.decide(false)
+	}
+}
+
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.decided {
+		if cw.encoder != nil {
+			return cw.encoder.Write(b)
+		}
+		return cw.ResponseWriter.Write(b)
+	}
+
+	if !cw.compressible() {
+		cw.decide(false)
+		return cw.ResponseWriter.Write(b)
+	}
+	cw.buf = append(cw.buf, b...)
+	if len(cw.buf) >= cw.minSize {
+		if err := cw.flushBuffer(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// Flush - поддержка потоковых ответов
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		cw.flushBuffer(cw.compressible() && len(cw.buf) > 0)
+	}
+	if f, ok := cw.encoder.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+	http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Close - дописывает буфер и завершает поток сжатия
+func (cw *compressWriter) Close() error {
+	if !cw.decided {
+		if !cw.wroteHeader {
+			// Обработчик ничего не записал
+			return nil
+		}
+		if err := cw.flushBuffer(false); err != nil {
+			return err
+		}
+	}
+	if cw.encoder == nil {
+		return nil
+	}
+	err := cw.encoder.Close()
+	switch enc := cw.encoder.(type) {
+	case *gzip.Writer:
+		gzipPool.Put(enc)
+	case *brotli.Writer:
+		brotliPool.Put(enc)
+	}
+	cw.encoder = nil
+	return err
+}
+
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+func (cw *compressWriter) compressible() bool {
+	h := cw.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	contentType := h.Get("Content-Type")
+	for _, t := range compressibleTypes {
+		if strings.HasPrefix(contentType, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (cw *compressWriter) flushBuffer(compress bool) error {
+	cw.decide(compress)
+	if len(cw.buf) == 0 {
+		return nil
+	}
+	var err error
+	if cw.encoder != nil {
+		_, err = cw.encoder.Write(cw.buf)
+	} else {
+		_, err = cw.ResponseWriter.Write(cw.buf)
+	}
+	cw.buf = nil
+	return err
+}
+
+// decide - отправляет заголовки ответа, с этого момента выбор сжатия окончателен.
+// Сильный ETag сжатого ответа получает суффикс кодировки (RFC 9110, 8.8.3); 304 повторяет
+// ETag, который клиент получил со сжатым ответом
+func (cw *compressWriter) decide(compress bool) {
+	cw.decided = true
+	h := cw.Header()
+	if etag := h.Get("ETag"); etag != "" {
+		encoded := httpcache.EncodedETag(etag, cw.encoding)
+		if compress || (cw.status == http.StatusNotModified && strings.Contains(cw.ifNoneMatch, encoded)) {
+			h.Set("ETag", encoded)
+		}
+	}
+	if compress {
+		h.Set("Content-Encoding", cw.encoding)
+		h.Del("Content-Length")
+		switch cw.encoding {
+		case encodingBrotli:
+			enc := brotliPool.Get().(*brotli.Writer)
+			enc.Reset(cw.ResponseWriter)
+			cw.encoder = enc
+		case encodingGzip:
+			enc := gzipPool.Get().(*gzip.Writer)
+			enc.Reset(cw.ResponseWriter)
+			cw.encoder = enc
+		}
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Deprecated - помечает ответы устаревшего маршрута заголовками Deprecation (RFC 9745),
+// Sunset (RFC 8594) и ссылкой на замену. successor возвращает путь нового маршрута,
+// пустая строка - без ссылки
+func Deprecated(since, sunset time.Time, successor func(r *http.Request) string) Middleware {
+	deprecation := "@" + strconv.FormatInt(since.Unix(), 10)
+	sunsetDate := sunset.UTC().Format(http.TimeFormat)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", deprecation)
+			w.Header().Set("Sunset", sunsetDate)
+			if successor != nil {
+				if link := successor(r); link != "" {
+					w.Header().Add("Link", "<"+link+`>; rel="successor-version"`)
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package middleware_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"marketplace-api/internal/httpcache"
+	"marketplace-api/internal/middleware"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChain(t *testing.T) {
+	t.Run("первый middleware выполняется первым", func(t *testing.T) {
+		var order []string
+		mw := func(name string) middleware.Middleware {
+			return func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					order = append(order, name)
+					next.ServeHTTP(w, r)
+				})
+			}
+		}
+		h := middleware.Chain(mw("a"), mw("b"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			order = append(order, "handler")
+		}))
+
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, []string{"a", "b", "handler"}, order)
+	})
+}
+
+func TestRecover(t *testing.T) {
+	t.Run("паника превращается в 500", func(t *testing.T) {
+		h := middleware.Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		}))
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/advertisement/", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, float64(http.StatusInternalServerError), body["status"])
+		assert.NotContains(t, w.Body.String(), "boom")
+	})
+
+	t.Run("ErrAbortHandler пробрасывается дальше", func(t *testing.T) {
+		h := middleware.Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		}))
+
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		})
+	})
+}
+
+func TestCORS(t *testing.T) {
+	cors := middleware.CORS(middleware.CORSConfig{
+		AllowedOrigins: []string{"https://shop.example"},
+		AllowedMethods: []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders: []string{"Authorization", "Content-Type"},
+		ExposedHeaders: []string{"X-Request-ID"},
+		MaxAge:         10 * time.Minute,
+	})
+	called := false
+	h := cors(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	t.Run("preflight разрешённого источника", func(t *testing.T) {
+		called = false
+		req := httptest.NewRequest(http.MethodOptions, "/advertisement", nil)
+		req.Header.Set("Origin", "https://shop.example")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.False(t, called)
+		assert.Equal(t, "https://shop.example", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "GET, POST", w.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "Authorization, Content-Type", w.Header().Get("Access-Control-Allow-Headers"))
+		assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("preflight чужого источника", func(t *testing.T) {
+		called = false
+		req := httptest.NewRequest(http.MethodOptions, "/advertisement", nil)
+		req.Header.Set("Origin", "https://evil.example")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.False(t, called)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("обычный запрос разрешённого источника", func(t *testing.T) {
+		called = false
+		req := httptest.NewRequest(http.MethodGet, "/advertisement/", nil)
+		req.Header.Set("Origin", "https://shop.example")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		assert.True(t, called)
+		assert.Equal(t, "https://shop.example", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "X-Request-ID", w.Header().Get("Access-Control-Expose-Headers"))
+	})
+
+	t.Run("запрос без Origin", func(t *testing.T) {
+		called = false
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/advertisement/", nil))
+
+		assert.True(t, called)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
+
+func TestBodyLimit(t *testing.T) {
+	h := middleware.BodyLimit(8)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Run("тело в пределах лимита", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("0123")))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Content-Length больше лимита", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("0123456789")))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	})
+
+	t.Run("тело без Content-Length больше лимита", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", io.NopCloser(strings.NewReader("0123456789")))
+		req.ContentLength = -1
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+}
+
+func TestCompress(t *testing.T) {
+	large := strings.Repeat(`{"title":"bike"},`, 200)
+	h := middleware.Compress(1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("small") != "" {
+			w.Write([]byte(`{}`))
+			return
+		}
+		w.Write([]byte(large))
+	}))
+
+	t.Run("gzip", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/advertisement/", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		assert.Contains(t, w.Header().Values("Vary"), "Accept-Encoding")
+		zr, err := gzip.NewReader(w.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		assert.Equal(t, large, string(body))
+	})
+
+	t.Run("brotli предпочтительнее gzip", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/advertisement/", nil)
+		req.Header.Set("Accept-Encoding", "gzip, br")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		assert.Equal(t, "br", w.Header().Get("Content-Encoding"))
+		body, err := io.ReadAll(brotli.NewReader(bytes.NewReader(w.Body.Bytes())))
+		require.NoError(t, err)
+		assert.Equal(t, large, string(body))
+	})
+
+	t.Run("кодировка с q=0 не используется", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/advertisement/", nil)
+		req.Header.Set("Accept-Encoding", "br;q=0, gzip")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	})
+
+	t.Run("маленький ответ не сжимается", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/advertisement/?small=1", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, `{}`, w.Body.String())
+	})
+
+	t.Run("клиент не поддерживает сжатие", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/advertisement/", nil))
+
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, large, w.Body.String())
+	})
+}
+
+func TestCompress_ETag(t *testing.T) {
+	titles := make([]string, 200)
+	for i := range titles {
+		titles[i] = "bike"
+	}
+	_, etag, err := httpcache.Encode(titles)
+	require.NoError(t, err)
+	h := middleware.Compress(1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httpcache.WriteJSON(w, r, http.StatusOK, titles, time.Time{})
+	}))
+	request := func(acceptEncoding, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/advertisement/", nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("у сжатых представлений разные ETag", func(t *testing.T) {
+		gzipped, brotlied, plain := request("gzip", ""), request("br", ""), request("", "")
+
+		assert.Equal(t, "gzip", gzipped.Header().Get("Content-Encoding"))
+		assert.Equal(t, httpcache.EncodedETag(etag, "gzip"), gzipped.Header().Get("ETag"))
+		assert.Equal(t, httpcache.EncodedETag(etag, "br"), brotlied.Header().Get("ETag"))
+		assert.Equal(t, etag, plain.Header().Get("ETag"))
+	})
+
+	t.Run("304 по ETag сжатого ответа", func(t *testing.T) {
+		first := request("gzip", "")
+		w := request("gzip", first.Header().Get("ETag"))
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Equal(t, first.Header().Get("ETag"), w.Header().Get("ETag"))
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+	})
+
+	t.Run("304 по ETag несжатого ответа", func(t *testing.T) {
+		w := request("gzip", etag)
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+	})
+}
+
+func TestDeprecated(t *testing.T) {
+	since := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, 4, 30, 0, 0, 0, 0, time.UTC)
+	h := middleware.Deprecated(since, sunset, func(r *http.Request) string {
+		return "/api/v1" + r.URL.Path
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/advertisement/42", nil))
+
+	assert.Equal(t, "@1792368000", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Fri, 30 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Equal(t, `</api/v1/advertisement/42>; rel="successor-version"`, w.Header().Get("Link"))
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"errors"
+	"marketplace-api/internal/logging"
+	"marketplace-api/internal/problem"
+	"net/http"
+	"runtime/debug"
+)
+
+// Recover - перехватывает панику обработчика, пишет её в лог со стеком и отвечает 500
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			// Штатный способ прервать ответ, сервер обработает его сам
+			if err, ok := rec.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(rec)
+			}
+
+			logging.FromContext(r.Context()).Error("panic in handler", "panic", rec, "stack", string(debug.Stack()))
+			problem.Write(w, r, http.StatusInternalServerError, "")
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
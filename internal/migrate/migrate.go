@@ -0,0 +1,131 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"marketplace-api/internal/logging"
+	"marketplace-api/migrations"
+
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+)
+
+var ErrSchemaBehind = errors.New("database schema is behind the application")
+
+// Migrator - применение встроенных миграций. Все команды выполняются под
+// advisory lock PostgreSQL, поэтому параллельные реплики не мигрируют одновременно
+type Migrator struct {
+	provider *goose.Provider
+}
+
+func New(db *sql.DB) (*Migrator, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, migrations.FS, goose.WithSessionLocker(locker))
+	if err != nil {
+		return nil, fmt.Errorf("loading migrations: %w", err)
+	}
+	return &Migrator{provider: provider}, nil
+}
+
+// Run - выполняет команду up, down, status или redo
+func (m *Migrator) Run(ctx context.Context, command string, w io.Writer) error {
+	switch command {
+	case "up":
+		return m.Up(ctx)
+	case "down":
+		return m.Down(ctx)
+	case "redo":
+		return m.Redo(ctx)
+	case "status":
+		return m.Status(ctx, w)
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected up, down, status or redo", command)
+	}
+}
+
+// Up - применяет все непримененные миграции
+func (m *Migrator) Up(ctx context.Context) error {
+	results, err := m.provider.Up(ctx)
+	for _, result := range results {
+		logResult(ctx, result)
+	}
+	return err
+}
+
+// Down - откатывает последнюю миграцию
+func (m *Migrator) Down(ctx context.Context) error {
+	result, err := m.provider.Down(ctx)
+	if result != nil {
+		logResult(ctx, result)
+	}
+	return err
+}
+
+// Redo - откатывает и заново применяет последнюю миграцию
+func (m *Migrator) Redo(ctx context.Context) error {
+	if err := m.Down(ctx); err != nil {
+		return err
+	}
+	result, err := m.provider.UpByOne(ctx)
+	if result != nil {
+		logResult(ctx, result)
+	}
+	return err
+}
+
+// Status - выводит состояние каждой миграции
+func (m *Migrator) Status(ctx context.Context, w io.Writer) error {
+	statuses, err := m.provider.Status(ctx)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		appliedAt := "-"
+		if !status.AppliedAt.IsZero() {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%-8s %-20s %s\n", status.State, appliedAt, status.Source.Path)
+	}
+	return nil
+}
+
+// Latest - версия последней встроенной миграции
+func (m *Migrator) Latest() int64 {
+	sources := m.provider.ListSources()
+	if len(sources) == 0 {
+		return 0
+	}
+	return sources[len(sources)-1].Version
+}
+
+// EnsureCurrent - ошибка ErrSchemaBehind, если в базе применены не все миграции
+func (m *Migrator) EnsureCurrent(ctx context.Context) error {
+	pending, err := m.provider.HasPending(ctx)
+	if err != nil {
+		return err
+	}
+	if pending {
+		current, err := m.provider.GetDBVersion(ctx)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: database version %d, expected %d", ErrSchemaBehind, current, m.Latest())
+	}
+	return nil
+}
+
+func logResult(ctx context.Context, result *goose.MigrationResult) {
+	logger := logging.FromContext(ctx).With("direction", result.Direction, "migration", result.Source.Path)
+	if result.Error != nil {
+		logger.Error("migration failed", "error", result.Error)
+		return
+	}
+	logger.Info("migration applied", "duration", result.Duration.String())
+}
@@ -0,0 +1,46 @@
+package migrate_test
+
+import (
+	"context"
+	"database/sql"
+	"marketplace-api/internal/migrate"
+	"marketplace-api/migrations"
+	"strconv"
+	"strings"
+	"testing"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrator_Embedded(t *testing.T) {
+	// Соединение не открывается, пока к базе нет запросов
+	db, err := sql.Open("pgx", "postgres://localhost:1/unused")
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := migrate.New(db)
+	require.NoError(t, err)
+
+	entries, err := migrations.FS.ReadDir(".")
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+
+	last := entries[len(entries)-1].Name()
+	version, err := strconv.ParseInt(strings.SplitN(last, "_", 2)[0], 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, version, migrator.Latest())
+}
+
+func TestMigrator_Run_UnknownCommand(t *testing.T) {
+	db, err := sql.Open("pgx", "postgres://localhost:1/unused")
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := migrate.New(db)
+	require.NoError(t, err)
+
+	err = migrator.Run(context.Background(), "sideways", nil)
+	assert.ErrorContains(t, err, "unknown migrate command")
+}
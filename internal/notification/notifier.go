@@ -0,0 +1,32 @@
+package notification
+
+import (
+	"context"
+	"marketplace-api/internal/logging"
+
+	"github.com/google/uuid"
+)
+
+type Notification struct {
+	UserID  uuid.UUID
+	Subject string
+	Body    string
+}
+
+// Notifier - отправка уведомлений пользователям
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// LogNotifier - пишет уведомления в лог, используется пока не подключён почтовый сервис
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+// Notify - записывает уведомление в лог
+func (LogNotifier) Notify(ctx context.Context, n Notification) error {
+	logging.FromContext(ctx).Info("notification", "user_id", n.UserID, "subject", n.Subject, "body", n.Body)
+	return nil
+}
@@ -0,0 +1,63 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Handler - подписчик на события. Доставка "хотя бы один раз": при ошибке любого
+// подписчика событие доставляется повторно всем подписчикам, поэтому обработка
+// должна быть идемпотентной (например, по Event.ID)
+type Handler func(ctx context.Context, e Event) error
+
+// Dispatcher - подписчики внутри процесса
+type Dispatcher struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler // по типу события
+	all      []Handler            // на все события
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{handlers: make(map[string][]Handler)}
+}
+
+// Subscribe - подписывает h на события eventTypes или, если типы не переданы, на все
+func (d *Dispatcher) Subscribe(h Handler, eventTypes ...string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(eventTypes) == 0 {
+		d.all = append(d.all, h)
+		return
+	}
+	for _, t := range eventTypes {
+		d.handlers[t] = append(d.handlers[t], h)
+	}
+}
+
+// Dispatch - передаёт событие всем его подписчикам и возвращает их ошибки
+func (d *Dispatcher) Dispatch(ctx context.Context, e Event) error {
+	d.mu.RLock()
+	handlers := append(append([]Handler(nil), d.handlers[e.Type]...), d.all...)
+	d.mu.RUnlock()
+
+	var errs []error
+	for _, h := range handlers {
+		if err := call(ctx, h, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// call - вызов подписчика, паника считается ошибкой доставки
+func call(ctx context.Context, h Handler, e Event) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("handler panicked: %v", p)
+		}
+	}()
+	return h(ctx, e)
+}
@@ -0,0 +1,41 @@
+// Package outbox - доменные события, сохраняемые в таблицу outbox_events в одной транзакции
+// с изменением и доставляемые подписчикам фоновой задачей (transactional outbox)
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event - доменное событие. Порядок доставки сохраняется в пределах агрегата (AggregateID)
+type Event struct {
+	ID            uuid.UUID       `json:"id"`
+	Type          string          `json:"type"`           // например advertisement.created
+	AggregateType string          `json:"aggregate_type"` // сущность, с которой произошло событие
+	AggregateID   uuid.UUID       `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+}
+
+// New - событие eventType агрегата aggregateType с данными payload в JSON
+func New(eventType, aggregateType string, aggregateID uuid.UUID, payload any) (Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{
+		ID:            uuid.New(),
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       data,
+		OccurredAt:    time.Now(),
+	}, nil
+}
+
+// Decode - разбор данных события в v
+func (e Event) Decode(v any) error {
+	return json.Unmarshal(e.Payload, v)
+}
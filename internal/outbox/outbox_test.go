@@ -0,0 +1,216 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"marketplace-api/internal/outbox"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	id := uuid.New()
+	e, err := outbox.New("advertisement.created", "advertisement", id, map[string]string{"title": "ad"})
+	require.NoError(t, err)
+
+	assert.NotEqual(t, uuid.Nil, e.ID)
+	assert.Equal(t, id, e.AggregateID)
+	assert.WithinDuration(t, time.Now(), e.OccurredAt, time.Second)
+
+	var payload map[string]string
+	require.NoError(t, e.Decode(&payload))
+	assert.Equal(t, "ad", payload["title"])
+}
+
+func TestDispatcher(t *testing.T) {
+	ctx := context.Background()
+	created := outbox.Event{Type: "advertisement.created"}
+
+	t.Run("подписчики по типу и на все события", func(t *testing.T) {
+		d := outbox.NewDispatcher()
+		var got []string
+		d.Subscribe(func(context.Context, outbox.Event) error { got = append(got, "created"); return nil }, "advertisement.created")
+		d.Subscribe(func(context.Context, outbox.Event) error { got = append(got, "sold"); return nil }, "advertisement.sold")
+		d.Subscribe(func(context.Context, outbox.Event) error { got = append(got, "all"); return nil })
+
+		assert.NoError(t, d.Dispatch(ctx, created))
+		assert.Equal(t, []string{"created", "all"}, got)
+	})
+
+	t.Run("ошибка одного подписчика не мешает остальным", func(t *testing.T) {
+		d := outbox.NewDispatcher()
+		errHandler := errors.New("handler failed")
+		called := false
+		d.Subscribe(func(context.Context, outbox.Event) error { return errHandler })
+		d.Subscribe(func(context.Context, outbox.Event) error { called = true; return nil })
+
+		assert.ErrorIs(t, d.Dispatch(ctx, created), errHandler)
+		assert.True(t, called)
+	})
+
+	t.Run("паника подписчика - ошибка доставки", func(t *testing.T) {
+		d := outbox.NewDispatcher()
+		d.Subscribe(func(context.Context, outbox.Event) error { panic("boom") })
+
+		assert.ErrorContains(t, d.Dispatch(ctx, created), "boom")
+	})
+}
+
+// fakeStore - события в памяти с сохранением итогов доставки
+type fakeStore struct {
+	mu       sync.Mutex
+	pending  []outbox.Event
+	attempts map[uuid.UUID]int
+	results  map[uuid.UUID]outbox.Result
+}
+
+func newFakeStore(events ...outbox.Event) *fakeStore {
+	return &fakeStore{pending: events, attempts: map[uuid.UUID]int{}, results: map[uuid.UUID]outbox.Result{}}
+}
+
+func (s *fakeStore) Record(_ context.Context, events ...outbox.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = append(s.pending, events...)
+	return nil
+}
+
+// Process - как PostgresStore: не больше одного события на агрегат за вызов
+func (s *fakeStore) Process(ctx context.Context, limit int, deliver func(ctx context.Context, e outbox.Event, attempts int) outbox.Result) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := map[uuid.UUID]bool{}
+	var batch []outbox.Event
+	for _, e := range s.pending {
+		if seen[e.AggregateID] || len(batch) == limit {
+			continue
+		}
+		seen[e.AggregateID] = true
+		if res, ok := s.results[e.ID]; ok && res.RetryAt.After(time.Now()) {
+			continue
+		}
+		batch = append(batch, e)
+	}
+
+	for _, e := range batch {
+		res := deliver(ctx, e, s.attempts[e.ID])
+		s.attempts[e.ID]++
+		s.results[e.ID] = res
+		if res.Err == nil || res.RetryAt.IsZero() {
+			s.remove(e.ID)
+		}
+	}
+	return len(batch), nil
+}
+
+func (s *fakeStore) remove(id uuid.UUID) {
+	for i, e := range s.pending {
+		if e.ID == id {
+			s.pending = append(s.pending[:i], s.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *fakeStore) DeletePublished(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func event(aggregateID uuid.UUID, eventType string) outbox.Event {
+	return outbox.Event{ID: uuid.New(), Type: eventType, AggregateID: aggregateID}
+}
+
+// runRelay - запускает Relay и останавливает его после одного прохода
+func runRelay(t *testing.T, store outbox.Store, d *outbox.Dispatcher, retry outbox.Retry) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		outbox.NewRelay(store, d, time.Hour, retry).Run(ctx)
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	<-done
+}
+
+func TestRelay(t *testing.T) {
+	retry := outbox.Retry{Min: time.Minute, Max: time.Hour, MaxAttempts: 3}
+
+	t.Run("события агрегата доставляются по порядку", func(t *testing.T) {
+		ad1, ad2 := uuid.New(), uuid.New()
+		store := newFakeStore(event(ad1, "created"), event(ad2, "created"), event(ad1, "updated"), event(ad1, "sold"))
+
+		d := outbox.NewDispatcher()
+		var mu sync.Mutex
+		got := map[uuid.UUID][]string{}
+		d.Subscribe(func(_ context.Context, e outbox.Event) error {
+			mu.Lock()
+			defer mu.Unlock()
+			got[e.AggregateID] = append(got[e.AggregateID], e.Type)
+			return nil
+		})
+
+		runRelay(t, store, d, retry)
+
+		assert.Equal(t, []string{"created", "updated", "sold"}, got[ad1])
+		assert.Equal(t, []string{"created"}, got[ad2])
+		assert.Empty(t, store.pending)
+	})
+
+	t.Run("ошибка - повтор позже, следующие события агрегата ждут", func(t *testing.T) {
+		ad := uuid.New()
+		first, second := event(ad, "created"), event(ad, "updated")
+		store := newFakeStore(first, second)
+
+		d := outbox.NewDispatcher()
+		var delivered []string
+		d.Subscribe(func(_ context.Context, e outbox.Event) error {
+			delivered = append(delivered, e.Type)
+			return errors.New("subscriber unavailable")
+		})
+
+		runRelay(t, store, d, retry)
+
+		assert.Equal(t, []string{"created"}, delivered)
+		res := store.results[first.ID]
+		assert.Error(t, res.Err)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), res.RetryAt, 5*time.Second)
+		assert.Len(t, store.pending, 2)
+	})
+
+	t.Run("после последней попытки событие отбрасывается", func(t *testing.T) {
+		e := event(uuid.New(), "created")
+		store := newFakeStore(e)
+		store.attempts[e.ID] = 2
+
+		d := outbox.NewDispatcher()
+		d.Subscribe(func(context.Context, outbox.Event) error { return errors.New("subscriber unavailable") })
+
+		runRelay(t, store, d, retry)
+
+		res := store.results[e.ID]
+		assert.Error(t, res.Err)
+		assert.True(t, res.RetryAt.IsZero())
+		assert.Empty(t, store.pending)
+	})
+
+	t.Run("пауза растёт до максимума", func(t *testing.T) {
+		e := event(uuid.New(), "created")
+		store := newFakeStore(e)
+		store.attempts[e.ID] = 8
+
+		d := outbox.NewDispatcher()
+		d.Subscribe(func(context.Context, outbox.Event) error { return errors.New("subscriber unavailable") })
+
+		runRelay(t, store, d, outbox.Retry{Min: time.Minute, Max: time.Hour, MaxAttempts: 20})
+
+		assert.WithinDuration(t, time.Now().Add(time.Hour), store.results[e.ID].RetryAt, 5*time.Second)
+	})
+}
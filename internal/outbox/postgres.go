@@ -0,0 +1,150 @@
+package outbox
+
+import (
+	"context"
+	"marketplace-api/internal/db"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresStore - события в таблице outbox_events
+type PostgresStore struct {
+	pool  *pgxpool.Pool
+	lease time.Duration // событие, взятое в работу, не выбирается повторно до истечения срока
+}
+
+func NewPostgresStore(pool *pgxpool.Pool, lease time.Duration) *PostgresStore {
+	return &PostgresStore{pool: pool, lease: lease}
+}
+
+func (s *PostgresStore) Record(ctx context.Context, events ...Event) error {
+	query := `
+		INSERT INTO outbox_events (id, event_type, aggregate_type, aggregate_id, payload, occurred_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	conn := db.Conn(ctx, s.pool)
+	for _, e := range events {
+		if _, err := conn.Exec(ctx, query, e.ID, e.Type, e.AggregateType, e.AggregateID, []byte(e.Payload), e.OccurredAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Process - события берутся в работу, откладываясь на lease, и транзакция выборки сразу
+// фиксируется, поэтому подписчики работают без открытой транзакции и блокировок.
+// Более поздние события агрегата не выбираются, пока не доставлено (или не отброшено)
+// предыдущее, поэтому порядок сохраняется и при нескольких экземплярах, а SKIP LOCKED
+// разводит их по агрегатам. Если результат не сохранится (например, экземпляр упадёт),
+// событие вернётся в очередь по истечении lease
+func (s *PostgresStore) Process(ctx context.Context, limit int, deliver func(ctx context.Context, e Event, attempts int) Result) (int, error) {
+	events, err := s.claim(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	results := make([]Result, len(events))
+	for i, p := range events {
+		results[i] = deliver(ctx, p.event, p.attempts)
+	}
+
+	if err := s.complete(ctx, events, results); err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
+
+type pendingEvent struct {
+	event    Event
+	attempts int
+}
+
+// claim - берёт в работу до limit готовых событий, по одному на агрегат, в порядке записи
+func (s *PostgresStore) claim(ctx context.Context, limit int) ([]pendingEvent, error) {
+	query := `
+		WITH claimed AS (
+			UPDATE outbox_events
+			SET next_attempt_at = now() + $2 * interval '1 millisecond'
+			WHERE id IN (
+				SELECT e.id
+				FROM outbox_events e
+				WHERE e.published_at IS NULL AND e.failed_at IS NULL AND e.next_attempt_at <= now()
+				  AND NOT EXISTS (
+					SELECT 1 FROM outbox_events p
+					WHERE p.aggregate_id = e.aggregate_id AND p.seq < e.seq
+					  AND p.published_at IS NULL AND p.failed_at IS NULL)
+				ORDER BY e.seq
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED)
+			RETURNING seq, id, event_type, aggregate_type, aggregate_id, payload, occurred_at, attempts
+		)
+		SELECT id, event_type, aggregate_type, aggregate_id, payload, occurred_at, attempts
+		FROM claimed
+		ORDER BY seq`
+
+	rows, err := s.pool.Query(ctx, query, limit, s.lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []pendingEvent
+	for rows.Next() {
+		var p pendingEvent
+		var payload []byte
+		err := rows.Scan(&p.event.ID, &p.event.Type, &p.event.AggregateType, &p.event.AggregateID, &payload,
+			&p.event.OccurredAt, &p.attempts)
+		if err != nil {
+			return nil, err
+		}
+		p.event.Payload = payload
+		events = append(events, p)
+	}
+	return events, rows.Err()
+}
+
+// complete - сохраняет результаты доставки одной короткой транзакцией
+func (s *PostgresStore) complete(ctx context.Context, events []pendingEvent, results []Result) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for i, p := range events {
+		res := results[i]
+
+		var err error
+		switch {
+		case res.Err == nil:
+			_, err = tx.Exec(ctx, `
+				UPDATE outbox_events SET published_at = now(), attempts = attempts + 1, last_error = NULL
+				WHERE id = $1`, p.event.ID)
+		case res.RetryAt.IsZero():
+			_, err = tx.Exec(ctx, `
+				UPDATE outbox_events SET failed_at = now(), attempts = attempts + 1, last_error = $2
+				WHERE id = $1`, p.event.ID, res.Err.Error())
+		default:
+			_, err = tx.Exec(ctx, `
+				UPDATE outbox_events SET next_attempt_at = $2, attempts = attempts + 1, last_error = $3
+				WHERE id = $1`, p.event.ID, res.RetryAt, res.Err.Error())
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (s *PostgresStore) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM outbox_events WHERE published_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
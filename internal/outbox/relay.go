@@ -0,0 +1,92 @@
+package outbox
+
+import (
+	"context"
+	"marketplace-api/internal/logging"
+	"marketplace-api/internal/metrics"
+	"time"
+)
+
+const relayBatchSize = 100
+
+// Retry - повторы доставки: пауза растёт вдвое от Min до Max, после MaxAttempts
+// неудачных попыток событие отбрасывается
+type Retry struct {
+	Min         time.Duration
+	Max         time.Duration
+	MaxAttempts int
+}
+
+// delay - пауза после failures неудачных попыток
+func (r Retry) delay(failures int) time.Duration {
+	d := r.Min
+	for i := 1; i < failures && d < r.Max; i++ {
+		d *= 2
+	}
+	return min(d, r.Max)
+}
+
+// Relay - фоновая задача, доставляющая сохранённые события подписчикам
+type Relay struct {
+	store      Store
+	dispatcher *Dispatcher
+	interval   time.Duration
+	retry      Retry
+}
+
+func NewRelay(store Store, dispatcher *Dispatcher, interval time.Duration, retry Retry) *Relay {
+	return &Relay{store: store, dispatcher: dispatcher, interval: interval, retry: retry}
+}
+
+// Run - запускает периодическую доставку событий до отмены ctx
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.relay(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// relay - доставляет события, пока есть готовые. За проход выбирается одно событие
+// на агрегат, поэтому проходы повторяются и при неполной пачке
+func (r *Relay) relay(ctx context.Context) {
+	for {
+		n, err := r.store.Process(ctx, relayBatchSize, r.deliver)
+		if err != nil {
+			if ctx.Err() == nil {
+				logging.FromContext(ctx).Error("error relaying outbox events", "error", err)
+			}
+			return
+		}
+		if n == 0 {
+			return
+		}
+	}
+}
+
+func (r *Relay) deliver(ctx context.Context, e Event, attempts int) Result {
+	err := r.dispatcher.Dispatch(ctx, e)
+	if err == nil {
+		metrics.OutboxDelivery("published")
+		return Result{}
+	}
+
+	failures := attempts + 1
+	log := logging.FromContext(ctx).With("event_id", e.ID, "event_type", e.Type, "attempt", failures, "error", err)
+	if failures >= r.retry.MaxAttempts {
+		metrics.OutboxDelivery("failed")
+		log.Error("outbox event dropped after retries")
+		return Result{Err: err}
+	}
+
+	metrics.OutboxDelivery("retry")
+	log.Warn("outbox event delivery failed, will retry")
+	return Result{Err: err, RetryAt: time.Now().Add(r.retry.delay(failures))}
+}
@@ -0,0 +1,55 @@
+package outbox
+
+import (
+	"context"
+	"marketplace-api/internal/logging"
+	"time"
+)
+
+// Result - итог попытки доставки: Err == nil - доставлено, иначе повтор в RetryAt
+// или, если RetryAt нулевое, событие больше не доставляется
+type Result struct {
+	Err     error
+	RetryAt time.Time
+}
+
+// Store - хранилище событий
+type Store interface {
+	// Record - сохраняет события; внутри db.TxManager.WithinTx - в той же транзакции
+	Record(ctx context.Context, events ...Event) error
+	// Process - выбирает до limit готовых к доставке событий, не больше одного на агрегат
+	// (самое раннее недоставленное), передаёт их в deliver вне транзакции выборки
+	// и сохраняет результат. Возвращает количество обработанных событий
+	Process(ctx context.Context, limit int, deliver func(ctx context.Context, e Event, attempts int) Result) (int, error)
+	// DeletePublished - удаляет доставленные до before события
+	DeletePublished(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Janitor - фоновая задача: периодически удаляет события, доставленные раньше чем retention назад
+type Janitor struct {
+	store     Store
+	interval  time.Duration
+	retention time.Duration
+}
+
+func NewJanitor(store Store, interval, retention time.Duration) *Janitor {
+	return &Janitor{store: store, interval: interval, retention: retention}
+}
+
+// Run - запускает периодическую очистку до отмены ctx
+func (j *Janitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := j.store.DeletePublished(ctx, time.Now().Add(-j.retention)); err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).Error("error deleting published events", "error", err)
+		}
+	}
+}
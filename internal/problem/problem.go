@@ -0,0 +1,36 @@
+// Package problem - ответы об ошибках в формате application/problem+json (RFC 9457)
+package problem
+
+import (
+	"encoding/json"
+	"marketplace-api/internal/logging"
+	"net/http"
+)
+
+const ContentType = "application/problem+json"
+
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// Write - отправляет ответ об ошибке, detail может быть пустым
+func Write(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	p := Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		RequestID: logging.RequestIDFromContext(r.Context()),
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(p)
+}
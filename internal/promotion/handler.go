@@ -0,0 +1,133 @@
+package promotion
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"marketplace-api/internal/auth"
+	"marketplace-api/internal/logging"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+type ServiceInterface interface {
+	Create(ctx context.Context, input *CreatePromotionInput) (*Promotion, error)
+	ConfirmPayment(ctx context.Context, callback *PaymentCallback) (*Promotion, error)
+}
+
+type Handler struct {
+	service  ServiceInterface
+	payments PaymentProvider
+}
+
+func NewPromotionHandler(service ServiceInterface, payments PaymentProvider) *Handler {
+	return &Handler{service: service, payments: payments}
+}
+
+// CreatePromotion godoc
+// @Summary Купить продвижение объявления
+// @Description Создаёт платёж за поднятие (bump) или выделение на N дней (highlight) объявления и возвращает ссылку на оплату
+// @Tags promotion
+// @Accept json
+// @Produce json
+// @Param input body CreatePromotionInput true "Параметры продвижения"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор с тем же ключом вернёт первый ответ"
+// @Success 201 {object} Promotion
+// @Failure 400 {string} string "Неверный ввод"
+// @Failure 401 {string} string "Пользователь не авторизован"
+// @Failure 403 {string} string "Объявление принадлежит другому пользователю"
+// @Failure 404 {string} string "Объявление не найдено"
+// @Failure 405 {string} string "Метод не разрешён"
+// @Failure 409 {string} string "Объявление не опубликовано"
+// @Security AuthToken
+// @Router /api/v1/promotion [post]
+func (h *Handler) CreatePromotion(w http.ResponseWriter, r *http.Request) {
+	//Получения ID авторизованного пользователя
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var input CreatePromotionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "invalid input", http.StatusBadRequest)
+		return
+	}
+	if input.AdvertisementID == uuid.Nil || input.Kind == "" {
+		http.Error(w, "all fields are required", http.StatusBadRequest)
+		return
+	}
+
+	//Вызов сервиса
+	input.UserID = userID
+	promotion, err := h.service.Create(r.Context(), &input)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(promotion)
+}
+
+// PaymentWebhook godoc
+// @Summary Уведомление платёжного провайдера
+// @Description Принимает подписанное уведомление о статусе платежа и применяет оплаченное продвижение
+// @Tags promotion
+// @Accept json
+// @Param input body PaymentCallback true "Статус платежа"
+// @Success 200 {string} string "OK"
+// @Failure 400 {string} string "Неверный ввод"
+// @Failure 401 {string} string "Неверная подпись"
+// @Failure 404 {string} string "Платёж не найден"
+// @Failure 405 {string} string "Метод не разрешён"
+// @Router /payments/webhook [post]
+func (h *Handler) PaymentWebhook(w http.ResponseWriter, r *http.Request) {
+	callback, err := h.payments.ParseCallback(r)
+	if errors.Is(err, ErrInvalidSignature) {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		http.Error(w, "invalid input", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.service.ConfirmPayment(r.Context(), callback); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Write([]byte("OK"))
+}
+
+// errorStatus сопоставляет ошибку сервиса с HTTP-статусом
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrAdNotFound), errors.Is(err, ErrPromotionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrNotOwner):
+		return http.StatusForbidden
+	case errors.Is(err, ErrAdNotActive):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidKind), errors.Is(err, ErrInvalidDays), errors.Is(err, ErrUnknownPaymentStatus):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeError - ответ с ошибкой сервиса. Непредвиденная ошибка пишется в лог, а клиент
+// получает общее сообщение без подробностей устройства сервиса
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status := errorStatus(err)
+	if status == http.StatusInternalServerError {
+		logging.FromContext(r.Context()).Error("request failed", "error", err)
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	http.Error(w, err.Error(), status)
+}
@@ -0,0 +1,117 @@
+package promotion_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"marketplace-api/internal/auth"
+	"marketplace-api/internal/promotion"
+	mockpromotion "marketplace-api/internal/promotion/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func setupHandlerTest(t *testing.T, payments promotion.PaymentProvider) (*gomock.Controller, *mockpromotion.MockServiceInterface, *promotion.Handler) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockService := mockpromotion.NewMockServiceInterface(ctrl)
+	handler := promotion.NewPromotionHandler(mockService, payments)
+	return ctrl, mockService, handler
+}
+
+func TestHandler_CreatePromotion(t *testing.T) {
+	userID := uuid.New()
+	input := promotion.CreatePromotionInput{AdvertisementID: uuid.New(), Kind: promotion.KindBump}
+
+	t.Run("успешное создание", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t, nil)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, in *promotion.CreatePromotionInput) (*promotion.Promotion, error) {
+				assert.Equal(t, userID, in.UserID)
+				return &promotion.Promotion{Status: promotion.StatusPending, ConfirmationURL: "http://pay"}, nil
+			})
+
+		body, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/promotion", bytes.NewReader(body))
+		req = req.WithContext(auth.WithUserID(req.Context(), userID))
+		w := httptest.NewRecorder()
+
+		handler.CreatePromotion(w, req)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), "http://pay")
+	})
+
+	t.Run("ошибка: чужое объявление", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t, nil)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, promotion.ErrNotOwner)
+
+		body, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/promotion", bytes.NewReader(body))
+		req = req.WithContext(auth.WithUserID(req.Context(), userID))
+		w := httptest.NewRecorder()
+
+		handler.CreatePromotion(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("ошибка: неавторизован", func(t *testing.T) {
+		_, _, handler := setupHandlerTest(t, nil)
+
+		body, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/promotion", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		handler.CreatePromotion(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestFakeProvider_PaymentFlow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := mockpromotion.NewMockServiceInterface(ctrl)
+
+	// Уведомления фиктивного провайдера принимает настоящий обработчик webhook
+	var provider *promotion.FakeProvider
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		promotion.NewPromotionHandler(mockService, provider).PaymentWebhook(w, r)
+	}))
+	defer webhook.Close()
+	provider = promotion.NewFakeProvider("secret", "http://localhost", webhook.URL)
+
+	payment, err := provider.CreatePayment(context.Background(), 9900, "bump")
+	assert.NoError(t, err)
+	assert.Contains(t, payment.ConfirmationURL, payment.ID)
+
+	mockService.EXPECT().
+		ConfirmPayment(gomock.Any(), &promotion.PaymentCallback{PaymentID: payment.ID, Status: promotion.PaymentSucceeded}).
+		Return(&promotion.Promotion{Status: promotion.StatusPaid}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/payments/fake/pay?payment_id="+payment.ID, nil)
+	w := httptest.NewRecorder()
+	provider.Pay(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Повторная оплата того же платежа невозможна
+	w = httptest.NewRecorder()
+	provider.Pay(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Уведомление без верной подписи отклоняется
+	forged := httptest.NewRequest(http.MethodPost, "/payments/webhook",
+		strings.NewReader(`{"payment_id":"`+payment.ID+`","status":"succeeded"}`))
+	forged.Header.Set("X-Payment-Signature", "deadbeef")
+	w = httptest.NewRecorder()
+	promotion.NewPromotionHandler(mockService, provider).PaymentWebhook(w, forged)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,73 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/promotion/payment.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/promotion/payment.go -destination=internal/promotion/mock/mock_payment_provider.go -package=mockpromotion
+//
+
+// Package mockpromotion is a generated GoMock package.
+package mockpromotion
+
+import (
+	context "context"
+	promotion "marketplace-api/internal/promotion"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPaymentProvider is a mock of PaymentProvider interface.
+type MockPaymentProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockPaymentProviderMockRecorder is the mock recorder for MockPaymentProvider.
+type MockPaymentProviderMockRecorder struct {
+	mock *MockPaymentProvider
+}
+
+// NewMockPaymentProvider creates a new mock instance.
+func NewMockPaymentProvider(ctrl *gomock.Controller) *MockPaymentProvider {
+	mock := &MockPaymentProvider{ctrl: ctrl}
+	mock.recorder = &MockPaymentProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentProvider) EXPECT() *MockPaymentProviderMockRecorder {
+	return m.recorder
+}
+
+// CreatePayment mocks base method.
+func (m *MockPaymentProvider) CreatePayment(ctx context.Context, amountKopecks int, description string) (*promotion.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePayment", ctx, amountKopecks, description)
+	ret0, _ := ret[0].(*promotion.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePayment indicates an expected call of CreatePayment.
+func (mr *MockPaymentProviderMockRecorder) CreatePayment(ctx, amountKopecks, description any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePayment", reflect.TypeOf((*MockPaymentProvider)(nil).CreatePayment), ctx, amountKopecks, description)
+}
+
+// ParseCallback mocks base method.
+func (m *MockPaymentProvider) ParseCallback(r *http.Request) (*promotion.PaymentCallback, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseCallback", r)
+	ret0, _ := ret[0].(*promotion.PaymentCallback)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParseCallback indicates an expected call of ParseCallback.
+func (mr *MockPaymentProviderMockRecorder) ParseCallback(r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseCallback", reflect.TypeOf((*MockPaymentProvider)(nil).ParseCallback), r)
+}
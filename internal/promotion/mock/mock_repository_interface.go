@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/promotion/service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/promotion/service.go -destination=internal/promotion/mock/mock_repository_interface.go -package=mockpromotion
+//
+
+// Package mockpromotion is a generated GoMock package.
+package mockpromotion
+
+import (
+	context "context"
+	promotion "marketplace-api/internal/promotion"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepositoryInterface is a mock of RepositoryInterface interface.
+type MockRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryInterfaceMockRecorder is the mock recorder for MockRepositoryInterface.
+type MockRepositoryInterfaceMockRecorder struct {
+	mock *MockRepositoryInterface
+}
+
+// NewMockRepositoryInterface creates a new mock instance.
+func NewMockRepositoryInterface(ctrl *gomock.Controller) *MockRepositoryInterface {
+	mock := &MockRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepositoryInterface) EXPECT() *MockRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ConfirmPayment mocks base method.
+func (m *MockRepositoryInterface) ConfirmPayment(ctx context.Context, paymentID string, succeeded bool) (*promotion.Promotion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmPayment", ctx, paymentID, succeeded)
+	ret0, _ := ret[0].(*promotion.Promotion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmPayment indicates an expected call of ConfirmPayment.
+func (mr *MockRepositoryInterfaceMockRecorder) ConfirmPayment(ctx, paymentID, succeeded any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmPayment", reflect.TypeOf((*MockRepositoryInterface)(nil).ConfirmPayment), ctx, paymentID, succeeded)
+}
+
+// Create mocks base method.
+func (m *MockRepositoryInterface) Create(ctx context.Context, p *promotion.Promotion) (*promotion.Promotion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, p)
+	ret0, _ := ret[0].(*promotion.Promotion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRepositoryInterfaceMockRecorder) Create(ctx, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepositoryInterface)(nil).Create), ctx, p)
+}
+
+// GetAdvertisementOwner mocks base method.
+func (m *MockRepositoryInterface) GetAdvertisementOwner(ctx context.Context, advertisementID uuid.UUID) (*promotion.AdvertisementOwner, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdvertisementOwner", ctx, advertisementID)
+	ret0, _ := ret[0].(*promotion.AdvertisementOwner)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAdvertisementOwner indicates an expected call of GetAdvertisementOwner.
+func (mr *MockRepositoryInterfaceMockRecorder) GetAdvertisementOwner(ctx, advertisementID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdvertisementOwner", reflect.TypeOf((*MockRepositoryInterface)(nil).GetAdvertisementOwner), ctx, advertisementID)
+}
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/promotion/handler.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/promotion/handler.go -destination=internal/promotion/mock/mock_service_interface.go -package=mockpromotion
+//
+
+// Package mockpromotion is a generated GoMock package.
+package mockpromotion
+
+import (
+	context "context"
+	promotion "marketplace-api/internal/promotion"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockServiceInterface is a mock of ServiceInterface interface.
+type MockServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockServiceInterfaceMockRecorder is the mock recorder for MockServiceInterface.
+type MockServiceInterfaceMockRecorder struct {
+	mock *MockServiceInterface
+}
+
+// NewMockServiceInterface creates a new mock instance.
+func NewMockServiceInterface(ctrl *gomock.Controller) *MockServiceInterface {
+	mock := &MockServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockServiceInterface) EXPECT() *MockServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ConfirmPayment mocks base method.
+func (m *MockServiceInterface) ConfirmPayment(ctx context.Context, callback *promotion.PaymentCallback) (*promotion.Promotion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmPayment", ctx, callback)
+	ret0, _ := ret[0].(*promotion.Promotion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmPayment indicates an expected call of ConfirmPayment.
+func (mr *MockServiceInterfaceMockRecorder) ConfirmPayment(ctx, callback any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmPayment", reflect.TypeOf((*MockServiceInterface)(nil).ConfirmPayment), ctx, callback)
+}
+
+// Create mocks base method.
+func (m *MockServiceInterface) Create(ctx context.Context, input *promotion.CreatePromotionInput) (*promotion.Promotion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, input)
+	ret0, _ := ret[0].(*promotion.Promotion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockServiceInterfaceMockRecorder) Create(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockServiceInterface)(nil).Create), ctx, input)
+}
@@ -0,0 +1,47 @@
+package promotion
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Виды продвижения
+const (
+	KindBump      = "bump"      // поднятие объявления в начало ленты
+	KindHighlight = "highlight" // выделение объявления на N дней
+)
+
+// Статусы продвижения
+const (
+	StatusPending = "pending" // ожидает оплаты
+	StatusPaid    = "paid"    // оплачено и применено к объявлению
+	StatusFailed  = "failed"  // оплата не прошла
+)
+
+type Promotion struct {
+	ID              uuid.UUID  `json:"id"`
+	AdvertisementID uuid.UUID  `json:"advertisement_id"`
+	UserID          uuid.UUID  `json:"user_id"`
+	Kind            string     `json:"kind"`
+	Days            int        `json:"days,omitempty"`
+	PriceKopecks    int        `json:"price_kopecks"`
+	Status          string     `json:"status"`
+	PaymentID       string     `json:"payment_id"`
+	ConfirmationURL string     `json:"confirmation_url,omitempty"` // страница оплаты у платёжного провайдера
+	CreatedAt       time.Time  `json:"created_at"`
+	PaidAt          *time.Time `json:"paid_at,omitempty"`
+}
+
+type CreatePromotionInput struct {
+	AdvertisementID uuid.UUID `json:"advertisement_id"`
+	Kind            string    `json:"kind"` // "bump" или "highlight"
+	Days            int       `json:"days"` // количество дней выделения, только для highlight
+	UserID          uuid.UUID `swaggerignore:"true"`
+}
+
+// AdvertisementOwner - сведения об объявлении, необходимые для проверки права на продвижение
+type AdvertisementOwner struct {
+	AuthorID uuid.UUID
+	Status   string
+}
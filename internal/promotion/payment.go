@@ -0,0 +1,155 @@
+package promotion
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Статусы платежа в уведомлении провайдера
+const (
+	PaymentSucceeded = "succeeded"
+	PaymentCanceled  = "canceled"
+)
+
+const signatureHeader = "X-Payment-Signature"
+
+var ErrInvalidSignature = errors.New("invalid payment callback signature")
+
+type Payment struct {
+	ID              string
+	ConfirmationURL string
+}
+
+// PaymentCallback - уведомление провайдера об изменении статуса платежа
+type PaymentCallback struct {
+	PaymentID string `json:"payment_id"`
+	Status    string `json:"status"`
+}
+
+// PaymentProvider - платёжный провайдер: создаёт платёж и проверяет уведомления о его статусе
+type PaymentProvider interface {
+	CreatePayment(ctx context.Context, amountKopecks int, description string) (*Payment, error)
+	ParseCallback(r *http.Request) (*PaymentCallback, error)
+}
+
+// FakeProvider - локальная замена платёжного провайдера: переход по ссылке оплаты
+// сразу отправляет подписанное уведомление об успешном платеже на webhookURL
+type FakeProvider struct {
+	secret     []byte
+	baseURL    string
+	webhookURL string
+	client     *http.Client
+
+	mu       sync.Mutex
+	payments map[string]int // ID платежа -> сумма
+}
+
+func NewFakeProvider(secret, baseURL, webhookURL string) *FakeProvider {
+	return &FakeProvider{
+		secret:     []byte(secret),
+		baseURL:    baseURL,
+		webhookURL: webhookURL,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		payments:   make(map[string]int),
+	}
+}
+
+// CreatePayment - регистрирует платёж и возвращает ссылку на фиктивную страницу оплаты
+func (p *FakeProvider) CreatePayment(_ context.Context, amountKopecks int, _ string) (*Payment, error) {
+	id := uuid.NewString()
+
+	p.mu.Lock()
+	p.payments[id] = amountKopecks
+	p.mu.Unlock()
+
+	return &Payment{
+		ID:              id,
+		ConfirmationURL: p.baseURL + "/payments/fake/pay?payment_id=" + id,
+	}, nil
+}
+
+// ParseCallback - проверяет подпись уведомления и разбирает его
+func (p *FakeProvider) ParseCallback(r *http.Request) (*PaymentCallback, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := hex.DecodeString(r.Header.Get(signatureHeader))
+	if err != nil || !hmac.Equal(signature, p.sign(body)) {
+		return nil, ErrInvalidSignature
+	}
+
+	var callback PaymentCallback
+	if err := json.Unmarshal(body, &callback); err != nil {
+		return nil, err
+	}
+	return &callback, nil
+}
+
+// Pay - фиктивная страница оплаты: ?payment_id=...&status=canceled позволяет проверить неуспешную оплату
+func (p *FakeProvider) Pay(w http.ResponseWriter, r *http.Request) {
+	paymentID := r.URL.Query().Get("payment_id")
+
+	p.mu.Lock()
+	_, ok := p.payments[paymentID]
+	delete(p.payments, paymentID)
+	p.mu.Unlock()
+	if !ok {
+		http.Error(w, "payment not found", http.StatusNotFound)
+		return
+	}
+
+	status := PaymentSucceeded
+	if r.URL.Query().Get("status") == PaymentCanceled {
+		status = PaymentCanceled
+	}
+
+	if err := p.sendCallback(r.Context(), PaymentCallback{PaymentID: paymentID, Status: status}); err != nil {
+		http.Error(w, "error sending payment callback: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.Write([]byte("payment " + status))
+}
+
+func (p *FakeProvider) sendCallback(ctx context.Context, callback PaymentCallback) error {
+	body, err := json.Marshal(callback)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(signatureHeader, hex.EncodeToString(p.sign(body)))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (p *FakeProvider) sign(body []byte) []byte {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
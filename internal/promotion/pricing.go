@@ -0,0 +1,43 @@
+package promotion
+
+import (
+	"errors"
+	"fmt"
+)
+
+const maxHighlightDays = 30
+
+var (
+	ErrInvalidKind = errors.New("invalid kind: must be bump or highlight")
+	ErrInvalidDays = errors.New("invalid days")
+)
+
+// Pricing - таблица цен на продвижение в копейках:
+// для bump - цена одного поднятия, для highlight - цена одного дня выделения
+type Pricing map[string]int
+
+var DefaultPricing = Pricing{
+	KindBump:      9900,
+	KindHighlight: 4900,
+}
+
+// Price - стоимость продвижения заданного вида и длительности
+func (p Pricing) Price(kind string, days int) (int, error) {
+	unit, ok := p[kind]
+	if !ok {
+		return 0, ErrInvalidKind
+	}
+
+	switch kind {
+	case KindHighlight:
+		if days < 1 || days > maxHighlightDays {
+			return 0, fmt.Errorf("%w: highlight must last 1-%d days", ErrInvalidDays, maxHighlightDays)
+		}
+		return unit * days, nil
+	default:
+		if days != 0 {
+			return 0, fmt.Errorf("%w: allowed only for highlight", ErrInvalidDays)
+		}
+		return unit, nil
+	}
+}
@@ -0,0 +1,108 @@
+package promotion
+
+import (
+	"context"
+	"errors"
+	"marketplace-api/internal/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPromotionRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+// GetAdvertisementOwner - автор и статус объявления (или nil, если не найдено)
+func (r *Repository) GetAdvertisementOwner(ctx context.Context, advertisementID uuid.UUID) (*AdvertisementOwner, error) {
+	var owner AdvertisementOwner
+	err := db.Conn(ctx, r.pool).QueryRow(ctx, `SELECT author_id, status FROM advertisements WHERE id = $1`, advertisementID).
+		Scan(&owner.AuthorID, &owner.Status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &owner, nil
+}
+
+// Create - сохраняет продвижение, ожидающее оплаты
+func (r *Repository) Create(ctx context.Context, p *Promotion) (*Promotion, error) {
+	query := `
+		INSERT INTO promotions (advertisement_id, user_id, kind, days, price_kopecks, status, payment_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+	err := db.Conn(ctx, r.pool).QueryRow(ctx, query, p.AdvertisementID, p.UserID, p.Kind, p.Days, p.PriceKopecks, p.Status, p.PaymentID).
+		Scan(&p.ID, &p.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ConfirmPayment - фиксирует результат оплаты и при успехе применяет продвижение к объявлению.
+// Повторное уведомление по уже обработанному платежу ничего не меняет
+func (r *Repository) ConfirmPayment(ctx context.Context, paymentID string, succeeded bool) (*Promotion, error) {
+	tx, err := db.Conn(ctx, r.pool).Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		SELECT id, advertisement_id, user_id, kind, days, price_kopecks, status, payment_id, created_at, paid_at
+		FROM promotions
+		WHERE payment_id = $1
+		FOR UPDATE`
+
+	var p Promotion
+	err = tx.QueryRow(ctx, query, paymentID).Scan(&p.ID, &p.AdvertisementID, &p.UserID, &p.Kind, &p.Days,
+		&p.PriceKopecks, &p.Status, &p.PaymentID, &p.CreatedAt, &p.PaidAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrPromotionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.Status != StatusPending {
+		return &p, nil
+	}
+
+	if !succeeded {
+		p.Status = StatusFailed
+		if _, err := tx.Exec(ctx, `UPDATE promotions SET status = $2 WHERE id = $1`, p.ID, p.Status); err != nil {
+			return nil, err
+		}
+		return &p, tx.Commit(ctx)
+	}
+
+	p.Status = StatusPaid
+	err = tx.QueryRow(ctx, `UPDATE promotions SET status = $2, paid_at = now() WHERE id = $1 RETURNING paid_at`, p.ID, p.Status).
+		Scan(&p.PaidAt)
+	if err != nil {
+		return nil, err
+	}
+
+	switch p.Kind {
+	case KindBump:
+		_, err = tx.Exec(ctx, `UPDATE advertisements SET bumped_at = now() WHERE id = $1`, p.AdvertisementID)
+	case KindHighlight:
+		// Оплаченное выделение добавляется к ещё не истёкшему
+		_, err = tx.Exec(ctx, `
+			UPDATE advertisements
+			SET highlighted_until = GREATEST(COALESCE(highlighted_until, now()), now()) + make_interval(days => $2)
+			WHERE id = $1`, p.AdvertisementID, p.Days)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, tx.Commit(ctx)
+}
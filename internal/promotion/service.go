@@ -0,0 +1,99 @@
+package promotion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"marketplace-api/internal/advertisement"
+	"marketplace-api/internal/logging"
+	"marketplace-api/internal/tracing"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrPromotionNotFound = errors.New("promotion not found")
+	ErrAdNotFound        = errors.New("advertisement not found")
+	ErrNotOwner          = errors.New("only the author can promote the advertisement")
+	ErrAdNotActive       = errors.New("only published advertisements can be promoted")
+	// ErrUnknownPaymentStatus - провайдер прислал статус, который сервис не умеет применять
+	ErrUnknownPaymentStatus = errors.New("unknown payment status")
+)
+
+type RepositoryInterface interface {
+	GetAdvertisementOwner(ctx context.Context, advertisementID uuid.UUID) (*AdvertisementOwner, error)
+	Create(ctx context.Context, p *Promotion) (*Promotion, error)
+	ConfirmPayment(ctx context.Context, paymentID string, succeeded bool) (*Promotion, error)
+}
+
+type Service struct {
+	repo     RepositoryInterface
+	payments PaymentProvider
+	pricing  Pricing
+}
+
+func NewPromotionService(repo RepositoryInterface, payments PaymentProvider, pricing Pricing) *Service {
+	return &Service{repo: repo, payments: payments, pricing: pricing}
+}
+
+// Create - оформление продвижения: расчёт цены и создание платежа
+func (s *Service) Create(ctx context.Context, input *CreatePromotionInput) (*Promotion, error) {
+	ctx, span := tracing.Start(ctx, "promotion.Service.Create")
+	defer span.End()
+
+	price, err := s.pricing.Price(input.Kind, input.Days)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := s.repo.GetAdvertisementOwner(ctx, input.AdvertisementID)
+	if err != nil {
+		return nil, err
+	}
+	if owner == nil {
+		return nil, ErrAdNotFound
+	}
+	if owner.AuthorID != input.UserID {
+		return nil, ErrNotOwner
+	}
+	if owner.Status != advertisement.StatusActive {
+		return nil, ErrAdNotActive
+	}
+
+	payment, err := s.payments.CreatePayment(ctx, price, fmt.Sprintf("Продвижение %s объявления %s", input.Kind, input.AdvertisementID))
+	if err != nil {
+		return nil, err
+	}
+
+	promotion, err := s.repo.Create(ctx, &Promotion{
+		AdvertisementID: input.AdvertisementID,
+		UserID:          input.UserID,
+		Kind:            input.Kind,
+		Days:            input.Days,
+		PriceKopecks:    price,
+		Status:          StatusPending,
+		PaymentID:       payment.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	promotion.ConfirmationURL = payment.ConfirmationURL
+	return promotion, nil
+}
+
+// ConfirmPayment - обработка уведомления платёжного провайдера
+func (s *Service) ConfirmPayment(ctx context.Context, callback *PaymentCallback) (*Promotion, error) {
+	ctx, span := tracing.Start(ctx, "promotion.Service.ConfirmPayment")
+	defer span.End()
+
+	logging.FromContext(ctx).Info("payment callback", "payment_id", callback.PaymentID, "status", callback.Status)
+	switch callback.Status {
+	case PaymentSucceeded:
+		return s.repo.ConfirmPayment(ctx, callback.PaymentID, true)
+	case PaymentCanceled:
+		return s.repo.ConfirmPayment(ctx, callback.PaymentID, false)
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownPaymentStatus, callback.Status)
+	}
+}
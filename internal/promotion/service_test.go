@@ -0,0 +1,128 @@
+package promotion_test
+
+import (
+	"context"
+	"errors"
+	"marketplace-api/internal/advertisement"
+	"marketplace-api/internal/promotion"
+	mockpromotion "marketplace-api/internal/promotion/mock"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func setupTest(t *testing.T) (*gomock.Controller, *mockpromotion.MockRepositoryInterface, *mockpromotion.MockPaymentProvider, *promotion.Service) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	mockRepo := mockpromotion.NewMockRepositoryInterface(ctrl)
+	mockPayments := mockpromotion.NewMockPaymentProvider(ctrl)
+	service := promotion.NewPromotionService(mockRepo, mockPayments, promotion.DefaultPricing)
+
+	return ctrl, mockRepo, mockPayments, service
+}
+
+func TestService_Create(t *testing.T) {
+	userID := uuid.New()
+	adID := uuid.New()
+	activeAd := &promotion.AdvertisementOwner{AuthorID: userID, Status: advertisement.StatusActive}
+
+	t.Run("успешное выделение на 3 дня", func(t *testing.T) {
+		ctrl, mockRepo, mockPayments, service := setupTest(t)
+		defer ctrl.Finish()
+
+		price := promotion.DefaultPricing[promotion.KindHighlight] * 3
+
+		mockRepo.EXPECT().GetAdvertisementOwner(gomock.Any(), adID).Return(activeAd, nil)
+		mockPayments.EXPECT().CreatePayment(gomock.Any(), price, gomock.Any()).
+			Return(&promotion.Payment{ID: "pay-1", ConfirmationURL: "http://pay/1"}, nil)
+		mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, p *promotion.Promotion) (*promotion.Promotion, error) {
+				assert.Equal(t, promotion.StatusPending, p.Status)
+				assert.Equal(t, "pay-1", p.PaymentID)
+				assert.Equal(t, price, p.PriceKopecks)
+				return p, nil
+			})
+
+		p, err := service.Create(context.Background(), &promotion.CreatePromotionInput{
+			AdvertisementID: adID, Kind: promotion.KindHighlight, Days: 3, UserID: userID,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "http://pay/1", p.ConfirmationURL)
+	})
+
+	t.Run("валидация: вид и длительность", func(t *testing.T) {
+		ctrl, _, _, service := setupTest(t)
+		defer ctrl.Finish()
+
+		_, err := service.Create(context.Background(), &promotion.CreatePromotionInput{AdvertisementID: adID, Kind: "top", UserID: userID})
+		assert.ErrorContains(t, err, "invalid kind")
+
+		_, err = service.Create(context.Background(), &promotion.CreatePromotionInput{AdvertisementID: adID, Kind: promotion.KindHighlight, UserID: userID})
+		assert.ErrorContains(t, err, "invalid days")
+
+		_, err = service.Create(context.Background(), &promotion.CreatePromotionInput{AdvertisementID: adID, Kind: promotion.KindBump, Days: 2, UserID: userID})
+		assert.ErrorContains(t, err, "invalid days")
+	})
+
+	t.Run("ошибка: чужое объявление", func(t *testing.T) {
+		ctrl, mockRepo, _, service := setupTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().GetAdvertisementOwner(gomock.Any(), adID).
+			Return(&promotion.AdvertisementOwner{AuthorID: uuid.New(), Status: advertisement.StatusActive}, nil)
+
+		_, err := service.Create(context.Background(), &promotion.CreatePromotionInput{AdvertisementID: adID, Kind: promotion.KindBump, UserID: userID})
+		assert.ErrorIs(t, err, promotion.ErrNotOwner)
+	})
+
+	t.Run("ошибка: объявление не опубликовано", func(t *testing.T) {
+		ctrl, mockRepo, _, service := setupTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().GetAdvertisementOwner(gomock.Any(), adID).
+			Return(&promotion.AdvertisementOwner{AuthorID: userID, Status: advertisement.StatusArchived}, nil)
+
+		_, err := service.Create(context.Background(), &promotion.CreatePromotionInput{AdvertisementID: adID, Kind: promotion.KindBump, UserID: userID})
+		assert.ErrorIs(t, err, promotion.ErrAdNotActive)
+	})
+
+	t.Run("ошибка платёжного провайдера", func(t *testing.T) {
+		ctrl, mockRepo, mockPayments, service := setupTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().GetAdvertisementOwner(gomock.Any(), adID).Return(activeAd, nil)
+		mockPayments.EXPECT().CreatePayment(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("provider unavailable"))
+
+		_, err := service.Create(context.Background(), &promotion.CreatePromotionInput{AdvertisementID: adID, Kind: promotion.KindBump, UserID: userID})
+		assert.EqualError(t, err, "provider unavailable")
+	})
+}
+
+func TestService_ConfirmPayment(t *testing.T) {
+	t.Run("успешная и отменённая оплата", func(t *testing.T) {
+		ctrl, mockRepo, _, service := setupTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().ConfirmPayment(gomock.Any(), "pay-1", true).Return(&promotion.Promotion{Status: promotion.StatusPaid}, nil)
+		mockRepo.EXPECT().ConfirmPayment(gomock.Any(), "pay-2", false).Return(&promotion.Promotion{Status: promotion.StatusFailed}, nil)
+
+		p, err := service.ConfirmPayment(context.Background(), &promotion.PaymentCallback{PaymentID: "pay-1", Status: promotion.PaymentSucceeded})
+		assert.NoError(t, err)
+		assert.Equal(t, promotion.StatusPaid, p.Status)
+
+		p, err = service.ConfirmPayment(context.Background(), &promotion.PaymentCallback{PaymentID: "pay-2", Status: promotion.PaymentCanceled})
+		assert.NoError(t, err)
+		assert.Equal(t, promotion.StatusFailed, p.Status)
+	})
+
+	t.Run("ошибка: неизвестный статус", func(t *testing.T) {
+		ctrl, _, _, service := setupTest(t)
+		defer ctrl.Finish()
+
+		_, err := service.ConfirmPayment(context.Background(), &promotion.PaymentCallback{PaymentID: "pay-1", Status: "pending"})
+		assert.ErrorContains(t, err, "unknown payment status")
+	})
+}
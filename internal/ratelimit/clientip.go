@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// IPResolver - определяет адрес клиента. X-Forwarded-For учитывается, только если запрос
+// пришёл от доверенного прокси, иначе заголовок мог подделать сам клиент
+type IPResolver struct {
+	trusted []netip.Prefix
+}
+
+// NewIPResolver - trusted содержит адреса или подсети (CIDR) доверенных прокси
+func NewIPResolver(trusted []string) (*IPResolver, error) {
+	res := &IPResolver{}
+	for _, t := range trusted {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		if strings.Contains(t, "/") {
+			prefix, err := netip.ParsePrefix(t)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", t, err)
+			}
+			res.trusted = append(res.trusted, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(t)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", t, err)
+		}
+		res.trusted = append(res.trusted, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+	return res, nil
+}
+
+// ClientIP - адрес соединения либо, за доверенными прокси, первый справа недоверенный адрес
+// из X-Forwarded-For
+func (res *IPResolver) ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	remote, err := netip.ParseAddr(host)
+	if err != nil || !res.isTrusted(remote) {
+		return host
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		if !res.isTrusted(addr) {
+			return addr.Unmap().String()
+		}
+		remote = addr
+	}
+	return remote.Unmap().String()
+}
+
+func (res *IPResolver) isTrusted(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, p := range res.trusted {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
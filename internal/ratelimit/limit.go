@@ -0,0 +1,99 @@
+// Package ratelimit - ограничение частоты запросов алгоритмом token bucket
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit - политика: Requests запросов за Per в среднем, всплеск до Burst запросов подряд
+type Limit struct {
+	Requests int
+	Per      time.Duration
+	Burst    int
+}
+
+// ParseLimit - разбирает политику вида "10/1m" или "100/1h:20" (с размером всплеска).
+// Пустая строка - без ограничения
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Limit{}, nil
+	}
+
+	rate, burst, hasBurst := strings.Cut(s, ":")
+	requests, per, ok := strings.Cut(rate, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: expected requests/period", s)
+	}
+
+	var l Limit
+	var err error
+	if l.Requests, err = strconv.Atoi(requests); err != nil || l.Requests <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: requests must be a positive integer", s)
+	}
+	if l.Per, err = time.ParseDuration(per); err != nil || l.Per <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: period must be a positive duration", s)
+	}
+	l.Burst = l.Requests
+	if hasBurst {
+		if l.Burst, err = strconv.Atoi(burst); err != nil || l.Burst <= 0 {
+			return Limit{}, fmt.Errorf("invalid rate limit %q: burst must be a positive integer", s)
+		}
+	}
+	return l, nil
+}
+
+// Unlimited - политика не задана
+func (l Limit) Unlimited() bool {
+	return l.Requests == 0
+}
+
+func (l Limit) String() string {
+	if l.Unlimited() {
+		return ""
+	}
+	s := strconv.Itoa(l.Requests) + "/" + l.Per.String()
+	if l.Burst != l.Requests {
+		s += ":" + strconv.Itoa(l.Burst)
+	}
+	return s
+}
+
+// perSecond - скорость пополнения корзины
+func (l Limit) perSecond() float64 {
+	return float64(l.Requests) / l.Per.Seconds()
+}
+
+// refill - число токенов через elapsed после состояния tokens
+func (l Limit) refill(tokens float64, elapsed time.Duration) float64 {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return math.Min(float64(l.Burst), tokens+elapsed.Seconds()*l.perSecond())
+}
+
+// result - итог попытки по оставшимся после неё токенам
+func (l Limit) result(tokens float64, allowed bool) Result {
+	res := Result{
+		Allowed:   allowed,
+		Limit:     l.Burst,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     l.after(float64(l.Burst) - tokens),
+	}
+	if !allowed {
+		res.RetryAfter = l.after(1 - tokens)
+	}
+	return res
+}
+
+// after - время до пополнения корзины на n токенов
+func (l Limit) after(n float64) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+	return time.Duration(n / l.perSecond() * float64(time.Second))
+}
@@ -0,0 +1,55 @@
+package ratelimit
+
+import (
+	"marketplace-api/internal/auth"
+	"marketplace-api/internal/logging"
+	"marketplace-api/internal/problem"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Middleware - ограничивает частоту запросов группы маршрутов name. Ключ - ID пользователя,
+// если запрос авторизован (middleware должен стоять после авторизации), иначе адрес клиента.
+// При недоступности хранилища запрос пропускается
+func Middleware(store Store, name string, limit Limit, ips *IPResolver) func(http.Handler) http.Handler {
+	policy := strconv.Itoa(limit.Burst) + ";w=" + strconv.Itoa(int(limit.Per.Seconds()))
+
+	return func(next http.Handler) http.Handler {
+		if limit.Unlimited() {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := name + ":ip:" + ips.ClientIP(r)
+			if userID, ok := auth.UserIDFromContext(r.Context()); ok {
+				key = name + ":user:" + userID.String()
+			}
+
+			res, err := store.Take(r.Context(), key, limit)
+			if err != nil {
+				logging.FromContext(r.Context()).Warn("rate limit store unavailable", "policy", name, "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("RateLimit-Policy", policy)
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			w.Header().Set("RateLimit-Reset", seconds(res.Reset))
+
+			if !res.Allowed {
+				w.Header().Set("Retry-After", seconds(res.RetryAfter))
+				logging.AddAttrs(r.Context(), "rate_limited", name)
+				problem.Write(w, r, http.StatusTooManyRequests, "rate limit exceeded, retry in "+seconds(res.RetryAfter)+"s")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// seconds - целые секунды с округлением вверх, как требуют Retry-After и RateLimit-Reset
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
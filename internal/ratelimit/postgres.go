@@ -0,0 +1,47 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresStore - корзины в таблице rate_limit_buckets, общие для всех экземпляров сервиса.
+// Пополнение и списание выполняются одним UPSERT под блокировкой строки
+type PostgresStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresStore(pool *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{pool: pool}
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	query := `
+		INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, updated_at)
+		VALUES ($1, $2::float8 - 1, true, clock_timestamp())
+		ON CONFLICT (key) DO UPDATE SET
+			tokens = CASE
+				WHEN LEAST($2, b.tokens + EXTRACT(EPOCH FROM clock_timestamp() - b.updated_at) * $3) >= 1
+				THEN LEAST($2, b.tokens + EXTRACT(EPOCH FROM clock_timestamp() - b.updated_at) * $3) - 1
+				ELSE LEAST($2, b.tokens + EXTRACT(EPOCH FROM clock_timestamp() - b.updated_at) * $3)
+			END,
+			allowed = LEAST($2, b.tokens + EXTRACT(EPOCH FROM clock_timestamp() - b.updated_at) * $3) >= 1,
+			updated_at = clock_timestamp()
+		RETURNING tokens, allowed`
+
+	var tokens float64
+	var allowed bool
+	err := s.pool.QueryRow(ctx, query, key, float64(limit.Burst), limit.perSecond()).Scan(&tokens, &allowed)
+	if err != nil {
+		return Result{}, err
+	}
+	return limit.result(tokens, allowed), nil
+}
+
+// Cleanup - удаляет корзины, не использовавшиеся дольше idle
+func (s *PostgresStore) Cleanup(ctx context.Context, idle time.Duration) error {
+	_, err := s.pool.Exec(ctx, `DELETE FROM rate_limit_buckets WHERE updated_at < now() - make_interval(secs => $1)`, idle.Seconds())
+	return err
+}
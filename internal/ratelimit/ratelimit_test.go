@@ -0,0 +1,211 @@
+package ratelimit_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"marketplace-api/internal/auth"
+	"marketplace-api/internal/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimit(t *testing.T) {
+	t.Run("запросы за период", func(t *testing.T) {
+		l, err := ratelimit.ParseLimit("10/1m")
+		require.NoError(t, err)
+		assert.Equal(t, ratelimit.Limit{Requests: 10, Per: time.Minute, Burst: 10}, l)
+		assert.Equal(t, "10/1m0s", l.String())
+	})
+
+	t.Run("со всплеском", func(t *testing.T) {
+		l, err := ratelimit.ParseLimit("300/1m:50")
+		require.NoError(t, err)
+		assert.Equal(t, ratelimit.Limit{Requests: 300, Per: time.Minute, Burst: 50}, l)
+	})
+
+	t.Run("пустая строка - без ограничения", func(t *testing.T) {
+		l, err := ratelimit.ParseLimit("")
+		require.NoError(t, err)
+		assert.True(t, l.Unlimited())
+	})
+
+	t.Run("ошибка формата", func(t *testing.T) {
+		for _, spec := range []string{"10", "0/1m", "ten/1m", "10/0s", "10/minute", "10/1m:0"} {
+			_, err := ratelimit.ParseLimit(spec)
+			assert.Error(t, err, spec)
+		}
+	})
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("всплеск исчерпывается, затем отказ", func(t *testing.T) {
+		store := ratelimit.NewMemoryStore()
+		limit := ratelimit.Limit{Requests: 2, Per: time.Hour, Burst: 2}
+
+		res, err := store.Take(ctx, "k", limit)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 1, res.Remaining)
+
+		res, _ = store.Take(ctx, "k", limit)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 0, res.Remaining)
+
+		res, _ = store.Take(ctx, "k", limit)
+		assert.False(t, res.Allowed)
+		assert.InDelta(t, 30*time.Minute, res.RetryAfter, float64(time.Second))
+		assert.InDelta(t, time.Hour, res.Reset, float64(time.Second))
+
+		res, _ = store.Take(ctx, "other", limit)
+		assert.True(t, res.Allowed)
+	})
+
+	t.Run("корзина пополняется со временем", func(t *testing.T) {
+		store := ratelimit.NewMemoryStore()
+		limit := ratelimit.Limit{Requests: 1, Per: 50 * time.Millisecond, Burst: 1}
+
+		res, _ := store.Take(ctx, "k", limit)
+		require.True(t, res.Allowed)
+		res, _ = store.Take(ctx, "k", limit)
+		require.False(t, res.Allowed)
+
+		require.Eventually(t, func() bool {
+			res, _ := store.Take(ctx, "k", limit)
+			return res.Allowed
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("очистка простаивающих корзин", func(t *testing.T) {
+		store := ratelimit.NewMemoryStore()
+		limit := ratelimit.Limit{Requests: 1, Per: time.Hour, Burst: 1}
+
+		store.Take(ctx, "k", limit)
+		require.NoError(t, store.Cleanup(ctx, 0))
+
+		res, _ := store.Take(ctx, "k", limit)
+		assert.True(t, res.Allowed)
+	})
+}
+
+func TestIPResolver(t *testing.T) {
+	ips, err := ratelimit.NewIPResolver([]string{"10.0.0.0/8", "192.168.1.1"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		remote   string
+		xff      string
+		expected string
+	}{
+		{"прямое соединение", "203.0.113.7:5000", "", "203.0.113.7"},
+		{"заголовок от недоверенного адреса игнорируется", "203.0.113.7:5000", "1.2.3.4", "203.0.113.7"},
+		{"клиент за доверенным прокси", "10.1.2.3:5000", "198.51.100.2", "198.51.100.2"},
+		{"цепочка доверенных прокси", "192.168.1.1:5000", "198.51.100.2, 203.0.113.9, 10.0.0.5", "203.0.113.9"},
+		{"все адреса доверенные", "10.1.2.3:5000", "10.0.0.5", "10.0.0.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remote
+			if tt.xff != "" {
+				req.Header.Set("X-Forwarded-For", tt.xff)
+			}
+			assert.Equal(t, tt.expected, ips.ClientIP(req))
+		})
+	}
+
+	t.Run("ошибка: некорректный адрес прокси", func(t *testing.T) {
+		_, err := ratelimit.NewIPResolver([]string{"proxy.local"})
+		assert.Error(t, err)
+	})
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("connection refused")
+}
+
+func TestMiddleware(t *testing.T) {
+	ips, err := ratelimit.NewIPResolver(nil)
+	require.NoError(t, err)
+	limit := ratelimit.Limit{Requests: 1, Per: time.Minute, Burst: 1}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	request := func(remote string, userID *uuid.UUID) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/advertisement", nil)
+		req.RemoteAddr = remote
+		if userID != nil {
+			req = req.WithContext(auth.WithUserID(req.Context(), *userID))
+		}
+		return req
+	}
+
+	t.Run("превышение лимита по адресу", func(t *testing.T) {
+		h := ratelimit.Middleware(ratelimit.NewMemoryStore(), "write", limit, ips)(ok)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, request("203.0.113.7:5000", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "1;w=60", w.Header().Get("RateLimit-Policy"))
+		assert.Equal(t, "1", w.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "60", w.Header().Get("RateLimit-Reset"))
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, request("203.0.113.7:6000", nil))
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "60", w.Header().Get("Retry-After"))
+		assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, float64(http.StatusTooManyRequests), body["status"])
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, request("198.51.100.2:5000", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("авторизованный пользователь считается по ID, а не по адресу", func(t *testing.T) {
+		h := ratelimit.Middleware(ratelimit.NewMemoryStore(), "write", limit, ips)(ok)
+		alice, bob := uuid.New(), uuid.New()
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, request("203.0.113.7:5000", &alice))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, request("203.0.113.7:5000", &bob))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, request("198.51.100.2:5000", &alice))
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	})
+
+	t.Run("без ограничения", func(t *testing.T) {
+		h := ratelimit.Middleware(failingStore{}, "read", ratelimit.Limit{}, ips)(ok)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, request("203.0.113.7:5000", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+	})
+
+	t.Run("хранилище недоступно - запрос пропускается", func(t *testing.T) {
+		h := ratelimit.Middleware(failingStore{}, "write", limit, ips)(ok)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, request("203.0.113.7:5000", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
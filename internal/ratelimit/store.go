@@ -0,0 +1,55 @@
+package ratelimit
+
+import (
+	"context"
+	"marketplace-api/internal/logging"
+	"time"
+)
+
+// Store - хранилище корзин. Take атомарно пополняет корзину key и забирает из неё токен, если он есть
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Result - итог попытки
+type Result struct {
+	Allowed    bool
+	Limit      int           // размер корзины
+	Remaining  int           // целых токенов осталось
+	Reset      time.Duration // через сколько корзина наполнится полностью
+	RetryAfter time.Duration // через сколько появится токен, если запрос отклонён
+}
+
+// Cleaner - хранилище, умеющее удалять неиспользуемые корзины
+type Cleaner interface {
+	Cleanup(ctx context.Context, idle time.Duration) error
+}
+
+// Janitor - фоновая задача: периодически удаляет корзины, простаивающие дольше idle
+type Janitor struct {
+	store    Cleaner
+	interval time.Duration
+	idle     time.Duration
+}
+
+func NewJanitor(store Cleaner, interval, idle time.Duration) *Janitor {
+	return &Janitor{store: store, interval: interval, idle: idle}
+}
+
+// Run - запускает периодическую очистку до отмены ctx
+func (j *Janitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := j.store.Cleanup(ctx, j.idle); err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).Error("error cleaning up rate limit buckets", "error", err)
+		}
+	}
+}
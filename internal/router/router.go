@@ -0,0 +1,93 @@
+// Package router - маршрутизация на шаблонах ServeMux (Go 1.22+) с группами middleware
+// и ответами 404/405 в формате problem+json
+package router
+
+import (
+	"marketplace-api/internal/middleware"
+	"marketplace-api/internal/problem"
+	"net/http"
+	"strings"
+)
+
+// Router - http.ServeMux с группами маршрутов. Маршруты задаются шаблонами вида
+// "GET /advertisement/{id}", параметры пути читаются через r.PathValue
+type Router struct {
+	mux         *http.ServeMux
+	prefix      string
+	middlewares []middleware.Middleware
+}
+
+func New() *Router {
+	return &Router{mux: http.NewServeMux()}
+}
+
+// Group - маршруты, зарегистрированные через группу, оборачиваются её middleware
+// (например, обязательной авторизацией). Группы вкладываются, mux общий
+func (rt *Router) Group(middlewares ...middleware.Middleware) *Router {
+	return &Router{
+		mux:         rt.mux,
+		prefix:      rt.prefix,
+		middlewares: append(append([]middleware.Middleware{}, rt.middlewares...), middlewares...),
+	}
+}
+
+// Mount - группа, пути маршрутов которой начинаются с prefix, например "/api/v1"
+func (rt *Router) Mount(prefix string, middlewares ...middleware.Middleware) *Router {
+	group := rt.Group(middlewares...)
+	group.prefix = rt.prefix + strings.TrimSuffix(prefix, "/")
+	return group
+}
+
+func (rt *Router) Handle(pattern string, handler http.Handler) {
+	rt.mux.Handle(rt.withPrefix(pattern), middleware.Chain(rt.middlewares...)(handler))
+}
+
+func (rt *Router) HandleFunc(pattern string, handler http.HandlerFunc) {
+	rt.Handle(pattern, handler)
+}
+
+// withPrefix - вставляет префикс перед путём шаблона, метод и хост сохраняются
+func (rt *Router) withPrefix(pattern string) string {
+	i := strings.IndexByte(pattern, '/')
+	if rt.prefix == "" || i < 0 {
+		return pattern
+	}
+	return pattern[:i] + rt.prefix + pattern[i:]
+}
+
+// ServeHTTP - ненайденный маршрут и неподходящий метод отдаются как problem+json,
+// для 405 заголовок Allow выставляет сам ServeMux
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, pattern := rt.mux.Handler(r); pattern != "" {
+		rt.mux.ServeHTTP(w, r)
+		return
+	}
+
+	rec := &statusCapture{header: w.Header(), status: http.StatusNotFound}
+	rt.mux.ServeHTTP(rec, r)
+	switch rec.status {
+	case http.StatusMethodNotAllowed:
+		problem.Write(w, r, http.StatusMethodNotAllowed, "method "+r.Method+" is not allowed for "+r.URL.Path)
+	default:
+		problem.Write(w, r, http.StatusNotFound, "no route for "+r.URL.Path)
+	}
+}
+
+// statusCapture - перехватывает текстовый ответ ServeMux об ошибке маршрутизации,
+// заголовки (Allow) пишутся в настоящий ResponseWriter
+type statusCapture struct {
+	header http.Header
+	status int
+}
+
+func (c *statusCapture) Header() http.Header {
+	return c.header
+}
+
+func (c *statusCapture) WriteHeader(code int) {
+	c.status = code
+}
+
+func (c *statusCapture) Write(b []byte) (int, error) {
+	return len(b), nil
+}
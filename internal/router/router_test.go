@@ -0,0 +1,114 @@
+package router_test
+
+import (
+	"encoding/json"
+	"marketplace-api/internal/router"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRouter(t *testing.T) *router.Router {
+	t.Helper()
+	rt := router.New()
+
+	denyAll := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+	}
+	authorized := rt.Group(denyAll)
+
+	rt.HandleFunc("GET /advertisement/{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("list"))
+	})
+	rt.HandleFunc("GET /advertisement/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.PathValue("id")))
+	})
+	authorized.HandleFunc("POST /advertisement", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	authorized.HandleFunc("POST /advertisement/renew", func(w http.ResponseWriter, r *http.Request) {})
+	return rt
+}
+
+func decodeProblem(t *testing.T, w *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return body
+}
+
+func TestRouter(t *testing.T) {
+	rt := setupRouter(t)
+
+	t.Run("параметр пути", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		rt.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/advertisement/42", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "42", w.Body.String())
+	})
+
+	t.Run("список по точному пути", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		rt.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/advertisement/", nil))
+
+		assert.Equal(t, "list", w.Body.String())
+	})
+
+	t.Run("middleware группы", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		rt.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/advertisement", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("неподходящий метод", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		rt.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/advertisement/42", nil))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		assert.Equal(t, "GET, HEAD", w.Header().Get("Allow"))
+		body := decodeProblem(t, w)
+		assert.Equal(t, float64(http.StatusMethodNotAllowed), body["status"])
+		assert.Equal(t, "/advertisement/42", body["instance"])
+	})
+
+	t.Run("маршрут не найден", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		rt.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Empty(t, w.Header().Get("Allow"))
+		body := decodeProblem(t, w)
+		assert.Equal(t, "Not Found", body["title"])
+	})
+}
+
+func TestRouter_Mount(t *testing.T) {
+	rt := router.New()
+	register := func(r *router.Router) {
+		r.HandleFunc("GET /advertisement/{id}", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(r.Pattern + " " + r.PathValue("id")))
+		})
+	}
+	register(rt.Mount("/api/v1/"))
+	register(rt.Group())
+
+	t.Run("маршрут под префиксом", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		rt.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/advertisement/42", nil))
+		assert.Equal(t, "GET /api/v1/advertisement/{id} 42", w.Body.String())
+	})
+
+	t.Run("тот же маршрут в корне", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		rt.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/advertisement/42", nil))
+		assert.Equal(t, "GET /advertisement/{id} 42", w.Body.String())
+	})
+}
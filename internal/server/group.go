@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Group - фоновые задачи с общим временем жизни, останавливаемые вместе с сервером
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu       sync.Mutex
+	stopping bool
+	exited   map[string]bool // задачи, завершившиеся раньше остановки группы
+}
+
+func NewGroup() *Group {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Group{ctx: ctx, cancel: cancel, exited: make(map[string]bool)}
+}
+
+// Go - запускает задачу, run должна завершиться после отмены переданного контекста
+func (g *Group) Go(name string, run func(ctx context.Context)) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		run(g.ctx)
+
+		g.mu.Lock()
+		if !g.stopping {
+			g.exited[name] = true
+		}
+		g.mu.Unlock()
+	}()
+}
+
+// Check - проверка для readiness: ошибка, если какая-то задача завершилась до остановки
+func (g *Group) Check(context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(g.exited) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(g.exited))
+	for name := range g.exited {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("background workers stopped: %s", strings.Join(names, ", "))
+}
+
+// Stop - отменяет задачи и ждёт их завершения до дедлайна ctx
+func (g *Group) Stop(ctx context.Context) error {
+	g.mu.Lock()
+	g.stopping = true
+	g.mu.Unlock()
+	g.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,40 @@
+package server
+
+import "net/http"
+
+// StatusRecorder - ResponseWriter, запоминающий код ответа, для middleware метрик, трассировки и логов
+type StatusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func NewStatusRecorder(w http.ResponseWriter) *StatusRecorder {
+	if rec, ok := w.(*StatusRecorder); ok {
+		return rec
+	}
+	return &StatusRecorder{ResponseWriter: w, status: http.StatusOK}
+}
+
+// Status - отправленный код ответа, 200 если обработчик не вызвал WriteHeader
+func (r *StatusRecorder) Status() int {
+	return r.status
+}
+
+func (r *StatusRecorder) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.status = code
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *StatusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap - для http.ResponseController
+func (r *StatusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,19 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Route - шаблон пути маршрута, по которому ServeMux выбрал обработчик, без метода и хоста:
+// "GET /advertisement/{id}" -> "/advertisement/{id}". Пустая строка, если маршрут не найден
+func Route(r *http.Request) string {
+	pattern := r.Pattern
+	if i := strings.IndexAny(pattern, " \t"); i >= 0 {
+		pattern = strings.TrimLeft(pattern[i:], " \t")
+	}
+	if i := strings.IndexByte(pattern, '/'); i > 0 {
+		pattern = pattern[i:]
+	}
+	return pattern
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"time"
+)
+
+type Config struct {
+	Addr              string
+	ReadTimeout       time.Duration // чтение запроса целиком, включая тело
+	ReadHeaderTimeout time.Duration // чтение заголовков, защита от slowloris
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration // keep-alive соединения
+	MaxHeaderBytes    int
+	TLSCertFile       string // TLS включается, если заданы сертификат и ключ
+	TLSKeyFile        string
+}
+
+// Server - HTTP-сервер с ограничениями по времени и размеру запросов
+type Server struct {
+	http  *http.Server
+	certs *CertReloader
+}
+
+func New(cfg Config, handler http.Handler) (*Server, error) {
+	s := &Server{
+		http: &http.Server{
+			Addr:              cfg.Addr,
+			Handler:           handler,
+			ReadTimeout:       cfg.ReadTimeout,
+			ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+			WriteTimeout:      cfg.WriteTimeout,
+			IdleTimeout:       cfg.IdleTimeout,
+			MaxHeaderBytes:    cfg.MaxHeaderBytes,
+		},
+	}
+
+	if cfg.TLSCertFile != "" || cfg.TLSKeyFile != "" {
+		certs, err := NewCertReloader(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		s.certs = certs
+		s.http.TLSConfig = &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: certs.GetCertificate,
+		}
+	}
+
+	return s, nil
+}
+
+// CertReloader - перезагрузчик TLS-сертификата (nil, если TLS выключен)
+func (s *Server) CertReloader() *CertReloader {
+	return s.certs
+}
+
+// TLS - включён ли TLS
+func (s *Server) TLS() bool {
+	return s.certs != nil
+}
+
+// ListenAndServe - принимает соединения до вызова Shutdown
+func (s *Server) ListenAndServe() error {
+	var err error
+	if s.certs != nil {
+		err = s.http.ListenAndServeTLS("", "")
+	} else {
+		err = s.http.ListenAndServe()
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Shutdown - прекращает приём новых соединений и ждёт завершения текущих запросов до дедлайна ctx
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.http.Shutdown(ctx)
+}
@@ -0,0 +1,130 @@
+package server_test
+
+import (
+	"context"
+	"io"
+	"marketplace-api/internal/server"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return l.Addr().String()
+}
+
+func TestServer_GracefulShutdown(t *testing.T) {
+	addr := freeAddr(t)
+	started := make(chan struct{})
+
+	srv, err := server.New(server.Config{Addr: addr, ReadHeaderTimeout: time.Second}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte("done"))
+	}))
+	require.NoError(t, err)
+
+	served := make(chan error, 1)
+	go func() { served <- srv.ListenAndServe() }()
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+		}
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	response := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr)
+		if err != nil {
+			response <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		response <- string(body)
+	}()
+
+	// Запрос, начатый до остановки, завершается, а новые соединения не принимаются
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, srv.Shutdown(ctx))
+	assert.Equal(t, "done", <-response)
+	assert.NoError(t, <-served)
+
+	_, err = http.Get("http://" + addr)
+	assert.Error(t, err)
+}
+
+func TestGroup_Stop(t *testing.T) {
+	t.Run("задачи завершаются после отмены", func(t *testing.T) {
+		group := server.NewGroup()
+		stopped := make(chan struct{})
+		group.Go("worker", func(ctx context.Context) {
+			<-ctx.Done()
+			close(stopped)
+		})
+
+		assert.NoError(t, group.Check(context.Background()))
+		assert.NoError(t, group.Stop(context.Background()))
+		<-stopped
+		assert.NoError(t, group.Check(context.Background()))
+	})
+
+	t.Run("ошибка: задача завершилась раньше остановки", func(t *testing.T) {
+		group := server.NewGroup()
+		group.Go("closer", func(ctx context.Context) {})
+
+		assert.Eventually(t, func() bool {
+			err := group.Check(context.Background())
+			return err != nil && strings.Contains(err.Error(), "closer")
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("ошибка: задача не уложилась в дедлайн", func(t *testing.T) {
+		group := server.NewGroup()
+		group.Go("slow", func(ctx context.Context) {
+			<-ctx.Done()
+			time.Sleep(time.Second)
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, group.Stop(ctx), context.DeadlineExceeded)
+	})
+}
+
+func TestRoute(t *testing.T) {
+	mux := http.NewServeMux()
+	var route string
+	handler := func(w http.ResponseWriter, r *http.Request) { route = server.Route(r) }
+	mux.HandleFunc("GET /advertisement/{id}", handler)
+	mux.HandleFunc("/auction", handler)
+	mux.HandleFunc("POST api.example/login", handler)
+
+	for path, expected := range map[string]string{
+		"/advertisement/42":        "/advertisement/{id}",
+		"/auction":                 "/auction",
+		"http://api.example/login": "/login",
+	} {
+		method := http.MethodGet
+		if path == "http://api.example/login" {
+			method = http.MethodPost
+		}
+		route = ""
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, path, nil))
+		assert.Equal(t, expected, route, path)
+	}
+}
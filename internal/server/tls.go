@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// CertReloader - отдаёт актуальный TLS-сертификат и перечитывает его с диска
+// при изменении файлов или по сигналу SIGHUP, без перезапуска сервера
+type CertReloader struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.RWMutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	r := &CertReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload - перечитывает сертификат и ключ, при ошибке остаётся прежний сертификат
+func (r *CertReloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	modTime := r.lastModified()
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.modTime = modTime
+	r.mu.Unlock()
+	return nil
+}
+
+// GetCertificate - для tls.Config.GetCertificate
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// Run - следит за изменением файлов сертификата и сигналом SIGHUP до отмены ctx
+func (r *CertReloader) Run(ctx context.Context, interval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			r.reload()
+		case <-ticker.C:
+			r.mu.RLock()
+			changed := r.lastModified().After(r.modTime)
+			r.mu.RUnlock()
+			if changed {
+				r.reload()
+			}
+		}
+	}
+}
+
+func (r *CertReloader) reload() {
+	if err := r.Reload(); err != nil {
+		slog.Error("error reloading TLS certificate", "error", err)
+		return
+	}
+	slog.Info("TLS certificate reloaded", "file", r.certFile)
+}
+
+// lastModified - время последнего изменения сертификата или ключа
+func (r *CertReloader) lastModified() time.Time {
+	var latest time.Time
+	for _, name := range []string{r.certFile, r.keyFile} {
+		if info, err := os.Stat(name); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest
+}
@@ -0,0 +1,56 @@
+package stats
+
+import (
+	"context"
+	"encoding/json"
+	"marketplace-api/internal/auth"
+	"net/http"
+)
+
+type ServiceInterface interface {
+	SellerStats(ctx context.Context, params *StatsParams) ([]AdStats, error)
+}
+
+type Handler struct {
+	service ServiceInterface
+}
+
+func NewStatsHandler(service ServiceInterface) *Handler {
+	return &Handler{service: service}
+}
+
+// SellerStats godoc
+// @Summary Статистика по объявлениям продавца
+// @Description Возвращает просмотры каждого объявления авторизованного пользователя с разбивкой по дням (30 дней), неделям (12 недель) или месяцам (12 месяцев). Избранного и показа контактов продавца в приложении нет, поэтому статистика содержит только просмотры
+// @Tags stats
+// @Produce json
+// @Param period query string false "Период группировки (day, week, month)" default(day)
+// @Success 200 {array} AdStats
+// @Failure 400 {string} string "Некорректные параметры запроса"
+// @Failure 401 {string} string "Пользователь не авторизован"
+// @Failure 405 {string} string "Метод не разрешён"
+// @Security AuthToken
+// @Router /api/v1/me/advertisements/stats [get]
+func (h *Handler) SellerStats(w http.ResponseWriter, r *http.Request) {
+	//Получения ID авторизованного пользователя
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	params := StatsParams{
+		UserID: userID,
+		Period: r.URL.Query().Get("period"),
+	}
+
+	result, err := h.service.SellerStats(r.Context(), &params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,68 @@
+package stats_test
+
+import (
+	"errors"
+	"marketplace-api/internal/auth"
+	"marketplace-api/internal/stats"
+	mockstats "marketplace-api/internal/stats/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func setupHandlerTest(t *testing.T) (*gomock.Controller, *mockstats.MockServiceInterface, *stats.Handler) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockService := mockstats.NewMockServiceInterface(ctrl)
+	handler := stats.NewStatsHandler(mockService)
+	return ctrl, mockService, handler
+}
+
+func TestHandler_SellerStats(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("успешный запрос", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().
+			SellerStats(gomock.Any(), &stats.StatsParams{UserID: userID, Period: stats.PeriodWeek}).
+			Return([]stats.AdStats{{Title: "ad", Views: 10}}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/me/advertisements/stats?period=week", nil)
+		req = req.WithContext(auth.WithUserID(req.Context(), userID))
+		w := httptest.NewRecorder()
+
+		handler.SellerStats(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"views":10`)
+	})
+
+	t.Run("ошибка: неавторизован", func(t *testing.T) {
+		_, _, handler := setupHandlerTest(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/me/advertisements/stats", nil)
+		w := httptest.NewRecorder()
+
+		handler.SellerStats(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("ошибка сервиса", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().SellerStats(gomock.Any(), gomock.Any()).Return(nil, errors.New("invalid period"))
+
+		req := httptest.NewRequest(http.MethodGet, "/me/advertisements/stats?period=year", nil)
+		req = req.WithContext(auth.WithUserID(req.Context(), userID))
+		w := httptest.NewRecorder()
+
+		handler.SellerStats(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
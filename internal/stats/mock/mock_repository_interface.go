@@ -0,0 +1,73 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/stats/service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/stats/service.go -destination=internal/stats/mock/mock_repository_interface.go -package=mockstats
+//
+
+// Package mockstats is a generated GoMock package.
+package mockstats
+
+import (
+	context "context"
+	stats "marketplace-api/internal/stats"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepositoryInterface is a mock of RepositoryInterface interface.
+type MockRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryInterfaceMockRecorder is the mock recorder for MockRepositoryInterface.
+type MockRepositoryInterfaceMockRecorder struct {
+	mock *MockRepositoryInterface
+}
+
+// NewMockRepositoryInterface creates a new mock instance.
+func NewMockRepositoryInterface(ctrl *gomock.Controller) *MockRepositoryInterface {
+	mock := &MockRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepositoryInterface) EXPECT() *MockRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetSellerStats mocks base method.
+func (m *MockRepositoryInterface) GetSellerStats(ctx context.Context, userID uuid.UUID, period string, from time.Time) ([]stats.StatsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSellerStats", ctx, userID, period, from)
+	ret0, _ := ret[0].([]stats.StatsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSellerStats indicates an expected call of GetSellerStats.
+func (mr *MockRepositoryInterfaceMockRecorder) GetSellerStats(ctx, userID, period, from any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSellerStats", reflect.TypeOf((*MockRepositoryInterface)(nil).GetSellerStats), ctx, userID, period, from)
+}
+
+// InsertEvents mocks base method.
+func (m *MockRepositoryInterface) InsertEvents(ctx context.Context, events []stats.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertEvents", ctx, events)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertEvents indicates an expected call of InsertEvents.
+func (mr *MockRepositoryInterfaceMockRecorder) InsertEvents(ctx, events any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertEvents", reflect.TypeOf((*MockRepositoryInterface)(nil).InsertEvents), ctx, events)
+}
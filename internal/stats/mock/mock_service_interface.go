@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/stats/handler.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/stats/handler.go -destination=internal/stats/mock/mock_service_interface.go -package=mockstats
+//
+
+// Package mockstats is a generated GoMock package.
+package mockstats
+
+import (
+	context "context"
+	stats "marketplace-api/internal/stats"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockServiceInterface is a mock of ServiceInterface interface.
+type MockServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockServiceInterfaceMockRecorder is the mock recorder for MockServiceInterface.
+type MockServiceInterfaceMockRecorder struct {
+	mock *MockServiceInterface
+}
+
+// NewMockServiceInterface creates a new mock instance.
+func NewMockServiceInterface(ctrl *gomock.Controller) *MockServiceInterface {
+	mock := &MockServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockServiceInterface) EXPECT() *MockServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// SellerStats mocks base method.
+func (m *MockServiceInterface) SellerStats(ctx context.Context, params *stats.StatsParams) ([]stats.AdStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SellerStats", ctx, params)
+	ret0, _ := ret[0].([]stats.AdStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SellerStats indicates an expected call of SellerStats.
+func (mr *MockServiceInterfaceMockRecorder) SellerStats(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SellerStats", reflect.TypeOf((*MockServiceInterface)(nil).SellerStats), ctx, params)
+}
@@ -0,0 +1,55 @@
+package stats
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы событий по объявлению. Избранного и показа контактов продавца в приложении нет,
+// поэтому учитываются только просмотры; новый тип добавляется вместе с местом его записи
+const (
+	EventView = "view"
+)
+
+// Периоды группировки статистики
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// Event - событие по объявлению, один зритель учитывается не чаще одного раза за окно дедупликации
+type Event struct {
+	AdvertisementID uuid.UUID
+	Kind            string
+	Viewer          string // хэш идентификатора пользователя или IP-адреса
+	BucketStart     time.Time
+	CreatedAt       time.Time
+}
+
+type StatsParams struct {
+	UserID uuid.UUID `swaggerignore:"true"`
+	Period string    `json:"period"` // "day", "week" или "month"
+}
+
+type Bucket struct {
+	Start time.Time `json:"start"`
+	Views int       `json:"views"`
+}
+
+// AdStats - статистика объявления. Только просмотры: событий избранного и контактов
+// в приложении нет (см. EventView)
+type AdStats struct {
+	AdvertisementID uuid.UUID `json:"advertisement_id"`
+	Title           string    `json:"title"`
+	Views           int       `json:"views"`
+	Buckets         []Bucket  `json:"buckets"`
+}
+
+// StatsRow - строка агрегированной статистики из БД (Bucket == nil, если событий за период нет)
+type StatsRow struct {
+	AdvertisementID uuid.UUID
+	Title           string
+	Bucket          *Bucket
+}
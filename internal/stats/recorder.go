@@ -0,0 +1,138 @@
+package stats
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"marketplace-api/internal/logging"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	recorderBufferSize = 4096
+	recorderBatchSize  = 500
+	recorderFlushEvery = 5 * time.Second
+	recorderFlushLimit = 5 * time.Second
+)
+
+// Recorder - асинхронный учёт просмотров: события дедуплицируются в памяти
+// и записываются в БД пачками, не замедляя чтение объявлений
+type Recorder struct {
+	repo   RepositoryInterface
+	window time.Duration
+	secret []byte
+	events chan Event
+
+	mu   sync.Mutex
+	seen map[string]time.Time // объявление+зритель -> начало окна, в котором просмотр уже учтён
+}
+
+// NewRecorder - secret - ключ, под которым хэшируются зрители
+func NewRecorder(repo RepositoryInterface, window time.Duration, secret []byte) *Recorder {
+	return &Recorder{
+		repo:   repo,
+		window: window,
+		secret: secret,
+		events: make(chan Event, recorderBufferSize),
+		seen:   make(map[string]time.Time),
+	}
+}
+
+// RecordView - учитывает просмотр объявления, повторные просмотры одного зрителя в пределах окна игнорируются.
+// ctx нужен только для логгера запроса, запись в БД от него не зависит
+func (r *Recorder) RecordView(ctx context.Context, advertisementID uuid.UUID, viewer string) {
+	now := time.Now()
+	bucket := now.Truncate(r.window)
+	hashed := r.hashViewer(viewer)
+	key := advertisementID.String() + "|" + hashed
+
+	r.mu.Lock()
+	if seen, ok := r.seen[key]; ok && seen.Equal(bucket) {
+		r.mu.Unlock()
+		return
+	}
+	r.seen[key] = bucket
+	r.mu.Unlock()
+
+	select {
+	case r.events <- Event{
+		AdvertisementID: advertisementID,
+		Kind:            EventView,
+		Viewer:          hashed,
+		BucketStart:     bucket,
+		CreatedAt:       now,
+	}:
+	default:
+		logging.FromContext(ctx).Warn("view recorder buffer is full, view dropped", "advertisement_id", advertisementID)
+	}
+}
+
+// Run - записывает накопленные события пачками до отмены ctx, после чего сбрасывает остаток
+func (r *Recorder) Run(ctx context.Context) {
+	ticker := time.NewTicker(recorderFlushEvery)
+	defer ticker.Stop()
+
+	batch := make([]Event, 0, recorderBatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			for {
+				select {
+				case e := <-r.events:
+					batch = append(batch, e)
+				default:
+					r.flush(batch)
+					return
+				}
+			}
+		case e := <-r.events:
+			batch = append(batch, e)
+			if len(batch) >= recorderBatchSize {
+				r.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			r.flush(batch)
+			batch = batch[:0]
+			r.forgetExpired()
+		}
+	}
+}
+
+func (r *Recorder) flush(batch []Event) {
+	if len(batch) == 0 {
+		return
+	}
+	// Запись не привязана к контексту Run, чтобы остаток сохранился и при остановке
+	ctx, cancel := context.WithTimeout(context.Background(), recorderFlushLimit)
+	defer cancel()
+
+	if err := r.repo.InsertEvents(ctx, batch); err != nil {
+		logging.FromContext(ctx).Error("error saving advertisement views", "count", len(batch), "error", err)
+	}
+}
+
+// forgetExpired - удаляет отметки о просмотрах из прошедших окон
+func (r *Recorder) forgetExpired() {
+	current := time.Now().Truncate(r.window)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, bucket := range r.seen {
+		if bucket.Before(current) {
+			delete(r.seen, key)
+		}
+	}
+}
+
+// hashViewer - IP-адреса и ID пользователей хранятся только в виде HMAC: адресов IPv4 мало,
+// и хэш без секрета обращается перебором
+func (r *Recorder) hashViewer(viewer string) string {
+	mac := hmac.New(sha256.New, r.secret)
+	mac.Write([]byte(viewer))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
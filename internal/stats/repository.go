@@ -0,0 +1,85 @@
+package stats
+
+import (
+	"context"
+	"marketplace-api/internal/db"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func NewStatsRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+// InsertEvents - сохраняет пачку событий одним запросом, повторы в пределах окна дедупликации игнорируются
+func (r *Repository) InsertEvents(ctx context.Context, events []Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(events))
+	kinds := make([]string, len(events))
+	viewers := make([]string, len(events))
+	buckets := make([]time.Time, len(events))
+	createdAt := make([]time.Time, len(events))
+	for i, e := range events {
+		ids[i] = e.AdvertisementID
+		kinds[i] = e.Kind
+		viewers[i] = e.Viewer
+		buckets[i] = e.BucketStart
+		createdAt[i] = e.CreatedAt
+	}
+
+	query := `
+		INSERT INTO advertisement_events (advertisement_id, kind, viewer, bucket_start, created_at)
+		SELECT * FROM unnest($1::uuid[], $2::text[], $3::text[], $4::timestamptz[], $5::timestamptz[])
+		ON CONFLICT (advertisement_id, kind, viewer, bucket_start) DO NOTHING
+	`
+	_, err := db.Conn(ctx, r.pool).Exec(ctx, query, ids, kinds, viewers, buckets, createdAt)
+	return err
+}
+
+// GetSellerStats - статистика по объявлениям автора, сгруппированная по периоду начиная с from
+func (r *Repository) GetSellerStats(ctx context.Context, userID uuid.UUID, period string, from time.Time) ([]StatsRow, error) {
+	query := `
+		SELECT
+			a.id,
+			a.title,
+			date_trunc($2, e.created_at) AS bucket,
+			count(*) FILTER (WHERE e.kind = 'view')
+		FROM advertisements a
+		LEFT JOIN advertisement_events e ON e.advertisement_id = a.id AND e.created_at >= $3
+		WHERE a.author_id = $1
+		GROUP BY a.id, a.title, bucket
+		ORDER BY a.created_at DESC, a.id, bucket`
+
+	rows, err := db.Conn(ctx, r.pool).Query(ctx, query, userID, period, from)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []StatsRow
+	for rows.Next() {
+		var (
+			row   StatsRow
+			start *time.Time
+			views int
+		)
+		if err := rows.Scan(&row.AdvertisementID, &row.Title, &start, &views); err != nil {
+			return nil, err
+		}
+		if start != nil {
+			row.Bucket = &Bucket{Start: *start, Views: views}
+		}
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
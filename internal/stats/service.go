@@ -0,0 +1,77 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"marketplace-api/internal/tracing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RepositoryInterface interface {
+	InsertEvents(ctx context.Context, events []Event) error
+	GetSellerStats(ctx context.Context, userID uuid.UUID, period string, from time.Time) ([]StatsRow, error)
+}
+
+type Service struct {
+	repo RepositoryInterface
+}
+
+func NewStatsService(repo RepositoryInterface) *Service {
+	return &Service{repo: repo}
+}
+
+// SellerStats - статистика просмотров объявлений продавца
+func (s *Service) SellerStats(ctx context.Context, params *StatsParams) ([]AdStats, error) {
+	ctx, span := tracing.Start(ctx, "stats.Service.SellerStats")
+	defer span.End()
+
+	if params.Period == "" {
+		params.Period = PeriodDay
+	}
+	from, err := periodFrom(params.Period, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repo.GetSellerStats(ctx, params.UserID, params.Period, from)
+	if err != nil {
+		return nil, err
+	}
+
+	// Строки упорядочены по объявлению - собираем их в статистику по каждому объявлению
+	result := []AdStats{}
+	for _, row := range rows {
+		if len(result) == 0 || result[len(result)-1].AdvertisementID != row.AdvertisementID {
+			result = append(result, AdStats{
+				AdvertisementID: row.AdvertisementID,
+				Title:           row.Title,
+				Buckets:         []Bucket{},
+			})
+		}
+		if row.Bucket == nil {
+			continue
+		}
+		ad := &result[len(result)-1]
+		ad.Views += row.Bucket.Views
+		ad.Buckets = append(ad.Buckets, *row.Bucket)
+	}
+
+	return result, nil
+}
+
+// periodFrom - начало интервала статистики: 30 дней, 12 недель или 12 месяцев
+func periodFrom(period string, now time.Time) (time.Time, error) {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch period {
+	case PeriodDay:
+		return day.AddDate(0, 0, -29), nil
+	case PeriodWeek:
+		return day.AddDate(0, 0, -7*11-int(day.Weekday()+6)%7), nil
+	case PeriodMonth:
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, -11, 0), nil
+	default:
+		return time.Time{}, errors.New("invalid period: must be day, week or month")
+	}
+}
@@ -0,0 +1,131 @@
+package stats_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"marketplace-api/internal/stats"
+	mockstats "marketplace-api/internal/stats/mock"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func setupTest(t *testing.T) (*gomock.Controller, *mockstats.MockRepositoryInterface, *stats.Service) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	mockRepo := mockstats.NewMockRepositoryInterface(ctrl)
+	service := stats.NewStatsService(mockRepo)
+
+	return ctrl, mockRepo, service
+}
+
+func TestService_SellerStats(t *testing.T) {
+	userID := uuid.New()
+	firstAd, secondAd := uuid.New(), uuid.New()
+	today := time.Now().Truncate(24 * time.Hour)
+
+	t.Run("группировка по объявлениям", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().
+			GetSellerStats(gomock.Any(), userID, stats.PeriodDay, gomock.Any()).
+			Return([]stats.StatsRow{
+				{AdvertisementID: firstAd, Title: "first", Bucket: &stats.Bucket{Start: today.AddDate(0, 0, -1), Views: 3}},
+				{AdvertisementID: firstAd, Title: "first", Bucket: &stats.Bucket{Start: today, Views: 2}},
+				{AdvertisementID: secondAd, Title: "second"},
+			}, nil)
+
+		result, err := service.SellerStats(context.Background(), &stats.StatsParams{UserID: userID})
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+
+		assert.Equal(t, 5, result[0].Views)
+		assert.Len(t, result[0].Buckets, 2)
+
+		assert.Equal(t, secondAd, result[1].AdvertisementID)
+		assert.Equal(t, 0, result[1].Views)
+		assert.Empty(t, result[1].Buckets)
+	})
+
+	t.Run("ошибка: неизвестный период", func(t *testing.T) {
+		ctrl, _, service := setupTest(t)
+		defer ctrl.Finish()
+
+		_, err := service.SellerStats(context.Background(), &stats.StatsParams{UserID: userID, Period: "year"})
+		assert.ErrorContains(t, err, "invalid period")
+	})
+
+	t.Run("тест ошибки из репозитория", func(t *testing.T) {
+		ctrl, mockRepo, service := setupTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().GetSellerStats(gomock.Any(), gomock.Any(), stats.PeriodMonth, gomock.Any()).
+			Return(nil, errors.New("db error"))
+
+		_, err := service.SellerStats(context.Background(), &stats.StatsParams{UserID: userID, Period: stats.PeriodMonth})
+		assert.EqualError(t, err, "db error")
+	})
+}
+
+func TestRecorder(t *testing.T) {
+	t.Run("повторные просмотры в окне не учитываются", func(t *testing.T) {
+		ctrl, mockRepo, _ := setupTest(t)
+		defer ctrl.Finish()
+
+		adID := uuid.New()
+		recorder := stats.NewRecorder(mockRepo, time.Hour, []byte("viewersecret-0123456789"))
+
+		mockRepo.EXPECT().
+			InsertEvents(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, events []stats.Event) error {
+				assert.Len(t, events, 2)
+				for _, e := range events {
+					assert.Equal(t, stats.EventView, e.Kind)
+					assert.NotContains(t, e.Viewer, "192.0.2.1")
+				}
+				return nil
+			})
+
+		recorder.RecordView(context.Background(), adID, "ip:192.0.2.1")
+		recorder.RecordView(context.Background(), adID, "ip:192.0.2.1")
+		recorder.RecordView(context.Background(), adID, "ip:192.0.2.2")
+
+		// Остановка записывает накопленные события
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		recorder.Run(ctx)
+	})
+
+	t.Run("хэш зрителя зависит от секрета", func(t *testing.T) {
+		ctrl, mockRepo, _ := setupTest(t)
+		defer ctrl.Finish()
+
+		adID := uuid.New()
+		var viewers []string
+		mockRepo.EXPECT().
+			InsertEvents(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, events []stats.Event) error {
+				viewers = append(viewers, events[0].Viewer)
+				return nil
+			}).Times(2)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		for _, secret := range []string{"viewersecret-0123456789", "othersecret-0123456789"} {
+			recorder := stats.NewRecorder(mockRepo, time.Hour, []byte(secret))
+			recorder.RecordView(context.Background(), adID, "ip:192.0.2.1")
+			recorder.Run(ctx)
+		}
+
+		sum := sha256.Sum256([]byte("ip:192.0.2.1"))
+		assert.NotEqual(t, viewers[0], viewers[1])
+		assert.NotEqual(t, hex.EncodeToString(sum[:16]), viewers[0], "хэш без секрета подбирается перебором адресов")
+	})
+}
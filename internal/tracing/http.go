@@ -0,0 +1,40 @@
+package tracing
+
+import (
+	"marketplace-api/internal/server"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware - спан на каждый HTTP-запрос с продолжением трассы из заголовка traceparent.
+// Должен стоять снаружи остальных middleware: шаблон маршрута читается из запроса,
+// переданного дальше по цепочке
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := Start(ctx, r.Method, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+			attribute.String("http.request.method", r.Method),
+			attribute.String("url.path", r.URL.Path),
+			attribute.String("user_agent.original", r.UserAgent()),
+		))
+		defer span.End()
+
+		rec := server.NewStatusRecorder(w)
+		req := r.WithContext(ctx)
+		next.ServeHTTP(rec, req)
+
+		if route := server.Route(req); route != "" {
+			span.SetName(r.Method + " " + route)
+			span.SetAttributes(attribute.String("http.route", route))
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", rec.Status()))
+		if rec.Status() >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(rec.Status()))
+		}
+	})
+}
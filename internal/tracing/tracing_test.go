@@ -0,0 +1,58 @@
+package tracing_test
+
+import (
+	"context"
+	"marketplace-api/internal/tracing"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func setupRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	_, err := tracing.Setup(context.Background(), tracing.Config{Exporter: tracing.ExporterNone})
+	require.NoError(t, err)
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	return recorder
+}
+
+func TestMiddleware(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	var inner trace.SpanContext
+	mux := http.NewServeMux()
+	mux.HandleFunc("/advertisement/", func(w http.ResponseWriter, r *http.Request) {
+		_, span := tracing.Start(r.Context(), "advertisement.Service.GetAd")
+		inner = span.SpanContext()
+		span.End()
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/advertisement/42", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	tracing.Middleware(mux).ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	server := spans[1]
+	assert.Equal(t, "GET /advertisement/", server.Name())
+	// Трасса продолжает входящий контекст, вложенный спан - дочерний
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+	assert.Equal(t, server.SpanContext().TraceID(), inner.TraceID())
+	assert.Equal(t, server.SpanContext().SpanID(), spans[0].Parent().SpanID())
+}
+
+func TestSetup_UnknownExporter(t *testing.T) {
+	_, err := tracing.Setup(context.Background(), tracing.Config{Exporter: "zipkin"})
+	assert.ErrorContains(t, err, "unknown trace exporter")
+}
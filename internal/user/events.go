@@ -0,0 +1,31 @@
+package user
+
+import (
+	"context"
+	"marketplace-api/internal/outbox"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AggregateType - агрегат событий пользователя
+const AggregateType = "user"
+
+// EventRegistered - пользователь зарегистрирован
+const EventRegistered = "user.registered"
+
+// RegisteredEvent - данные события EventRegistered
+type RegisteredEvent struct {
+	ID        uuid.UUID `json:"id"`
+	Login     string    `json:"login"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// recordRegistered - сохраняет событие о регистрации u
+func (s *Service) recordRegistered(ctx context.Context, u *User) error {
+	event, err := outbox.New(EventRegistered, AggregateType, u.ID, RegisteredEvent{ID: u.ID, Login: u.Login, CreatedAt: u.CreatedAt})
+	if err != nil {
+		return err
+	}
+	return s.events.Record(ctx, event)
+}
@@ -30,13 +30,8 @@ func NewUserHandler(service ServiceInterface) *Handler {
 // @Failure 400 {string} string "Неверный ввод"
 // @Failure 401 {string} string "Неавторизован"
 // @Failure 405 {string} string "Метод не разрешён"
-// @Router /login [post]
+// @Router /api/v1/login [post]
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	var input LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		http.Error(w, "invalid input", http.StatusBadRequest)
@@ -73,13 +68,8 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 // @Success 201 {object} RegisterResponse
 // @Failure 400 {string} string "Неверный ввод"
 // @Failure 405 {string} string "Метод не разрешён"
-// @Router /register [post]
+// @Router /api/v1/register [post]
 func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	var input RegisterRequest
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		http.Error(w, "invalid input", http.StatusBadRequest)
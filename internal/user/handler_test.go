@@ -61,16 +61,6 @@ func TestHandler_Register(t *testing.T) {
 		assert.Equal(t, expectedUser.Login, response.Login)
 	})
 
-	t.Run("ошибка: метод не разрешён", func(t *testing.T) {
-		_, _, handler := setupHandlerTest(t)
-		req := httptest.NewRequest(http.MethodGet, "/register", nil)
-		rec := httptest.NewRecorder()
-
-		handler.Register(rec, req)
-		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
-		assert.Contains(t, rec.Body.String(), "method not allowed")
-	})
-
 	t.Run("ошибка: пустые поля", func(t *testing.T) {
 		_, _, handler := setupHandlerTest(t)
 
@@ -145,17 +135,6 @@ func TestHandler_Login(t *testing.T) {
 		assert.Equal(t, "mocked.jwt.token", resp.Token)
 	})
 
-	t.Run("ошибка: метод не разрешён", func(t *testing.T) {
-		_, _, handler := setupHandlerTest(t)
-		req := httptest.NewRequest(http.MethodGet, "/login", nil)
-		rec := httptest.NewRecorder()
-
-		handler.Login(rec, req)
-
-		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
-		assert.Contains(t, rec.Body.String(), "method not allowed")
-	})
-
 	t.Run("ошибка: пустые поля", func(t *testing.T) {
 		_, _, handler := setupHandlerTest(t)
 
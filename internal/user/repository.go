@@ -3,6 +3,7 @@ package user
 import (
 	"context"
 	"errors"
+	"marketplace-api/internal/db"
 	"strings"
 
 	"github.com/jackc/pgx/v5"
@@ -24,7 +25,7 @@ func (r *Repository) Create(ctx context.Context, u *User) (*User, error) {
 		VALUES ($1, $2, $3)
 		RETURNING id, created_at
 	`
-	err := r.pool.QueryRow(ctx, query, u.Login, strings.ToLower(u.Login), u.PasswordHash).Scan(&u.ID, &u.CreatedAt)
+	err := db.Conn(ctx, r.pool).QueryRow(ctx, query, u.Login, strings.ToLower(u.Login), u.PasswordHash).Scan(&u.ID, &u.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -38,7 +39,7 @@ func (r *Repository) GetByLogin(ctx context.Context, login string) (*User, error
 		FROM users
 		WHERE login_lower = $1
 	`
-	row := r.pool.QueryRow(ctx, query, strings.ToLower(login))
+	row := db.Conn(ctx, r.pool).QueryRow(ctx, query, strings.ToLower(login))
 
 	var u User
 	err := row.Scan(&u.ID, &u.Login, &u.PasswordHash, &u.CreatedAt)
@@ -4,6 +4,10 @@ import (
 	"context"
 	"errors"
 	"marketplace-api/internal/auth"
+	"marketplace-api/internal/logging"
+	"marketplace-api/internal/metrics"
+	"marketplace-api/internal/outbox"
+	"marketplace-api/internal/tracing"
 	"regexp"
 	"strings"
 	"unicode"
@@ -21,17 +25,32 @@ type RepositoryInterface interface {
 	GetByLogin(ctx context.Context, login string) (*User, error)
 }
 
+// Transactor - выполнение нескольких обращений к репозиториям в одной транзакции
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// EventRecorder - сохранение событий в одной транзакции с изменением
+type EventRecorder interface {
+	Record(ctx context.Context, events ...outbox.Event) error
+}
+
 type Service struct {
 	repo       RepositoryInterface
+	tx         Transactor
+	events     EventRecorder
 	jwtManager *auth.JWTManager
 }
 
-func NewUserService(repo RepositoryInterface, jwtManager *auth.JWTManager) *Service {
-	return &Service{repo: repo, jwtManager: jwtManager}
+func NewUserService(repo RepositoryInterface, tx Transactor, events EventRecorder, jwtManager *auth.JWTManager) *Service {
+	return &Service{repo: repo, tx: tx, events: events, jwtManager: jwtManager}
 }
 
 // Register - регистрация пользователя
 func (s *Service) Register(ctx context.Context, input *RegisterRequest) (*User, error) {
+	ctx, span := tracing.Start(ctx, "user.Service.Register")
+	defer span.End()
+
 	if err := s.validateRegisterInput(ctx, input); err != nil {
 		return nil, err
 	}
@@ -47,10 +66,20 @@ func (s *Service) Register(ctx context.Context, input *RegisterRequest) (*User,
 		PasswordHash: string(hashed),
 	}
 
-	user, err = s.repo.Create(ctx, user)
+	// Пользователь и событие о регистрации сохраняются вместе
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		created, err := s.repo.Create(ctx, user)
+		if err != nil {
+			return err
+		}
+		user = created
+		return s.recordRegistered(ctx, user)
+	})
 	if err != nil {
 		return nil, err
 	}
+	metrics.RegistrationCompleted()
+	logging.FromContext(ctx).Info("user registered", "user_id", user.ID)
 
 	return user, nil
 }
@@ -113,8 +142,13 @@ func (s *Service) validateRegisterInput(ctx context.Context, input *RegisterRequ
 
 // Authenticate - аутентификация пользователя
 func (s *Service) Authenticate(ctx context.Context, input *LoginRequest) (string, error) {
+	ctx, span := tracing.Start(ctx, "user.Service.Authenticate")
+	defer span.End()
+
 	user, err := s.validateAuthenticateInput(ctx, input)
 	if err != nil {
+		metrics.LoginAttempt(false)
+		logging.FromContext(ctx).Warn("login failed", "login", input.Login, "error", err)
 		return "", err
 	}
 
@@ -122,6 +156,7 @@ func (s *Service) Authenticate(ctx context.Context, input *LoginRequest) (string
 	if err != nil {
 		return "", errors.New("token error")
 	}
+	metrics.LoginAttempt(true)
 
 	return token, nil
 }
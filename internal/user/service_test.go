@@ -3,23 +3,49 @@ package user_test
 import (
 	"context"
 	"marketplace-api/internal/auth"
+	"marketplace-api/internal/outbox"
 	"marketplace-api/internal/user"
 	mockuser "marketplace-api/internal/user/mock"
 	"regexp"
 	"testing"
+	"time"
 
 	uuid "github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	gomock "go.uber.org/mock/gomock"
 )
 
+// noTx - выполняет функцию без транзакции
+type noTx struct{}
+
+func (noTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// eventLog - сохранённые сервисом события
+type eventLog struct {
+	events []outbox.Event
+}
+
+func (l *eventLog) Record(_ context.Context, events ...outbox.Event) error {
+	l.events = append(l.events, events...)
+	return nil
+}
+
 func setupTest(t *testing.T) (*gomock.Controller, *mockuser.MockRepositoryInterface, *user.Service) {
+	t.Helper()
+	ctrl, mockRepo, _, service := setupEventsTest(t)
+	return ctrl, mockRepo, service
+}
+
+func setupEventsTest(t *testing.T) (*gomock.Controller, *mockuser.MockRepositoryInterface, *eventLog, *user.Service) {
 	t.Helper()
 	ctrl := gomock.NewController(t)
 	mockRepo := mockuser.NewMockRepositoryInterface(ctrl)
-	jwtManager := auth.NewJWTManager("secret")
-	service := user.NewUserService(mockRepo, jwtManager)
-	return ctrl, mockRepo, service
+	jwtManager := auth.NewJWTManager("secret", time.Hour)
+	events := &eventLog{}
+	service := user.NewUserService(mockRepo, noTx{}, events, jwtManager)
+	return ctrl, mockRepo, events, service
 }
 
 func TestService_Register(t *testing.T) {
@@ -30,7 +56,7 @@ func TestService_Register(t *testing.T) {
 	hashedRegex := regexp.MustCompile(`^\$2[aby]\$`)
 
 	t.Run("успешная регистрация", func(t *testing.T) {
-		ctrl, mockRepo, service := setupTest(t)
+		ctrl, mockRepo, events, service := setupEventsTest(t)
 		defer ctrl.Finish()
 
 		mockRepo.EXPECT().GetByLogin(gomock.Any(), validInput.Login).Return(nil, nil)
@@ -45,6 +71,10 @@ func TestService_Register(t *testing.T) {
 		createdUser, err := service.Register(context.Background(), validInput)
 		assert.NoError(t, err)
 		assert.Equal(t, validInput.Login, createdUser.Login)
+
+		assert.Len(t, events.events, 1)
+		assert.Equal(t, user.EventRegistered, events.events[0].Type)
+		assert.Equal(t, createdUser.ID, events.events[0].AggregateID)
 	})
 
 	t.Run("ошибка: логин занят", func(t *testing.T) {
@@ -178,7 +208,7 @@ func TestService_Authenticate(t *testing.T) {
 		defer ctrl.Finish()
 
 		// Передаём nil jwtManager
-		service := user.NewUserService(mockRepo, nil)
+		service := user.NewUserService(mockRepo, noTx{}, &eventLog{}, nil)
 
 		mockRepo.EXPECT().GetByLogin(gomock.Any(), validUser.Login).Return(validUser, nil)
 
@@ -0,0 +1,250 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"marketplace-api/internal/auth"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultDeliveriesLimit = 50
+	maxDeliveriesLimit     = 200
+)
+
+type ServiceInterface interface {
+	CreateEndpoint(ctx context.Context, input *CreateEndpointInput) (*Endpoint, error)
+	ListEndpoints(ctx context.Context, userID uuid.UUID) ([]Endpoint, error)
+	DeleteEndpoint(ctx context.Context, userID, id uuid.UUID) error
+	EnableEndpoint(ctx context.Context, userID, id uuid.UUID) (*Endpoint, error)
+	ListDeliveries(ctx context.Context, userID, endpointID uuid.UUID, limit int) ([]Delivery, error)
+	Redeliver(ctx context.Context, userID, endpointID, deliveryID uuid.UUID) (*Delivery, error)
+}
+
+type Handler struct {
+	service ServiceInterface
+}
+
+func NewWebhookHandler(service ServiceInterface) *Handler {
+	return &Handler{service: service}
+}
+
+// CreateEndpoint godoc
+// @Summary Зарегистрировать адрес для уведомлений
+// @Description Партнёр получает POST-запросы о событиях своих объявлений. Запрос подписан: заголовок Webhook-Signature содержит v1=<hex HMAC-SHA256> от строки "<Webhook-Timestamp>.<тело>" на ключе secret. Ключ возвращается только в этом ответе. Типы событий: advertisement.created, advertisement.updated, advertisement.published, advertisement.renewed, advertisement.expired, advertisement.sold
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Param input body CreateEndpointInput true "Адрес и типы событий"
+// @Success 201 {object} Endpoint
+// @Failure 400 {string} string "Неверный ввод"
+// @Failure 401 {string} string "Пользователь не авторизован"
+// @Security AuthToken
+// @Router /api/v1/webhooks [post]
+func (h *Handler) CreateEndpoint(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var input CreateEndpointInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "invalid input", http.StatusBadRequest)
+		return
+	}
+
+	input.UserID = userID
+	endpoint, err := h.service.CreateEndpoint(r.Context(), &input)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, endpoint)
+}
+
+// ListEndpoints godoc
+// @Summary Адреса для уведомлений
+// @Description Возвращает адреса пользователя без ключей подписи
+// @Tags webhook
+// @Produce json
+// @Success 200 {array} Endpoint
+// @Failure 401 {string} string "Пользователь не авторизован"
+// @Security AuthToken
+// @Router /api/v1/webhooks [get]
+func (h *Handler) ListEndpoints(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	endpoints, err := h.service.ListEndpoints(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, endpoints)
+}
+
+// DeleteEndpoint godoc
+// @Summary Удалить адрес для уведомлений
+// @Description Удаляет адрес вместе с журналом доставок
+// @Tags webhook
+// @Param id path string true "ID адреса"
+// @Success 204
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 401 {string} string "Пользователь не авторизован"
+// @Failure 404 {string} string "Адрес не найден"
+// @Security AuthToken
+// @Router /api/v1/webhooks/{id} [delete]
+func (h *Handler) DeleteEndpoint(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := endpointRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteEndpoint(r.Context(), userID, id); err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// EnableEndpoint godoc
+// @Summary Включить адрес для уведомлений
+// @Description Включает адрес, отключённый после серии неудачных доставок. Накопившиеся доставки будут отправлены
+// @Tags webhook
+// @Produce json
+// @Param id path string true "ID адреса"
+// @Success 200 {object} Endpoint
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 401 {string} string "Пользователь не авторизован"
+// @Failure 404 {string} string "Адрес не найден"
+// @Security AuthToken
+// @Router /api/v1/webhooks/{id}/enable [post]
+func (h *Handler) EnableEndpoint(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := endpointRequest(w, r)
+	if !ok {
+		return
+	}
+
+	endpoint, err := h.service.EnableEndpoint(r.Context(), userID, id)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, endpoint)
+}
+
+// ListDeliveries godoc
+// @Summary Журнал доставок
+// @Description Возвращает последние доставки на адрес: статус, число попыток, ответ получателя и время следующей попытки
+// @Tags webhook
+// @Produce json
+// @Param id path string true "ID адреса"
+// @Param limit query int false "Количество записей (по умолчанию 50, максимум 200)"
+// @Success 200 {array} Delivery
+// @Failure 400 {string} string "Неверный ввод"
+// @Failure 401 {string} string "Пользователь не авторизован"
+// @Failure 404 {string} string "Адрес не найден"
+// @Security AuthToken
+// @Router /api/v1/webhooks/{id}/deliveries [get]
+func (h *Handler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := endpointRequest(w, r)
+	if !ok {
+		return
+	}
+
+	limit := defaultDeliveriesLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxDeliveriesLimit {
+			http.Error(w, "limit must be between 1 and 200", http.StatusBadRequest)
+			return
+		}
+	}
+
+	deliveries, err := h.service.ListDeliveries(r.Context(), userID, id, limit)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, deliveries)
+}
+
+// Redeliver godoc
+// @Summary Повторить доставку
+// @Description Ставит доставку в очередь заново с полным запасом попыток. Тело запроса и ID события не меняются
+// @Tags webhook
+// @Produce json
+// @Param id path string true "ID адреса"
+// @Param deliveryID path string true "ID доставки"
+// @Success 202 {object} Delivery
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 401 {string} string "Пользователь не авторизован"
+// @Failure 404 {string} string "Адрес или доставка не найдены"
+// @Security AuthToken
+// @Router /api/v1/webhooks/{id}/deliveries/{deliveryID}/redeliver [post]
+func (h *Handler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := endpointRequest(w, r)
+	if !ok {
+		return
+	}
+	deliveryID, err := uuid.Parse(r.PathValue("deliveryID"))
+	if err != nil {
+		http.Error(w, "deliveryID must be a valid UUID", http.StatusBadRequest)
+		return
+	}
+
+	delivery, err := h.service.Redeliver(r.Context(), userID, id, deliveryID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, delivery)
+}
+
+// endpointRequest - пользователь и ID адреса из пути. При ошибке ответ уже записан
+func endpointRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return uuid.Nil, uuid.Nil, false
+	}
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "id must be a valid UUID", http.StatusBadRequest)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, id, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// errorStatus сопоставляет ошибку сервиса с HTTP-статусом
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrEndpointNotFound), errors.Is(err, ErrDeliveryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrInvalidURL), errors.Is(err, ErrUnknownEventType), errors.Is(err, ErrNoEventTypes):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
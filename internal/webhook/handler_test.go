@@ -0,0 +1,155 @@
+package webhook_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"marketplace-api/internal/auth"
+	"marketplace-api/internal/webhook"
+	mockwebhook "marketplace-api/internal/webhook/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func setupHandlerTest(t *testing.T) (*gomock.Controller, *mockwebhook.MockServiceInterface, *webhook.Handler) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	mockService := mockwebhook.NewMockServiceInterface(ctrl)
+	handler := webhook.NewWebhookHandler(mockService)
+	return ctrl, mockService, handler
+}
+
+func TestHandler_CreateEndpoint(t *testing.T) {
+	userID := uuid.New()
+	input := webhook.CreateEndpointInput{URL: "https://partner.example.com", EventTypes: []string{"advertisement.created"}}
+
+	t.Run("успешная регистрация", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().CreateEndpoint(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, in *webhook.CreateEndpointInput) (*webhook.Endpoint, error) {
+				assert.Equal(t, userID, in.UserID)
+				return &webhook.Endpoint{ID: uuid.New(), URL: in.URL, Secret: "whsec_1"}, nil
+			})
+
+		body, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(body))
+		req = req.WithContext(auth.WithUserID(req.Context(), userID))
+		w := httptest.NewRecorder()
+
+		handler.CreateEndpoint(w, req)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), "whsec_1")
+		assert.NotContains(t, w.Body.String(), userID.String())
+	})
+
+	t.Run("ошибка: неавторизован", func(t *testing.T) {
+		_, _, handler := setupHandlerTest(t)
+
+		body, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		handler.CreateEndpoint(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("ошибка: недопустимый адрес", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().CreateEndpoint(gomock.Any(), gomock.Any()).Return(nil, webhook.ErrInvalidURL)
+
+		body, _ := json.Marshal(input)
+		req := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(body))
+		req = req.WithContext(auth.WithUserID(req.Context(), userID))
+		w := httptest.NewRecorder()
+
+		handler.CreateEndpoint(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestHandler_Endpoint(t *testing.T) {
+	userID := uuid.New()
+	id := uuid.New()
+
+	request := func(method, target string, pathValues map[string]string) *http.Request {
+		req := httptest.NewRequest(method, target, nil)
+		for k, v := range pathValues {
+			req.SetPathValue(k, v)
+		}
+		return req.WithContext(auth.WithUserID(req.Context(), userID))
+	}
+
+	t.Run("удаление", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().DeleteEndpoint(gomock.Any(), userID, id).Return(nil)
+
+		w := httptest.NewRecorder()
+		handler.DeleteEndpoint(w, request(http.MethodDelete, "/webhooks/"+id.String(), map[string]string{"id": id.String()}))
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("ошибка: адрес не найден", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().EnableEndpoint(gomock.Any(), userID, id).Return(nil, webhook.ErrEndpointNotFound)
+
+		w := httptest.NewRecorder()
+		handler.EnableEndpoint(w, request(http.MethodPost, "/webhooks/"+id.String()+"/enable", map[string]string{"id": id.String()}))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("ошибка: некорректный ID", func(t *testing.T) {
+		_, _, handler := setupHandlerTest(t)
+
+		w := httptest.NewRecorder()
+		handler.DeleteEndpoint(w, request(http.MethodDelete, "/webhooks/abc", map[string]string{"id": "abc"}))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("журнал доставок", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		mockService.EXPECT().ListDeliveries(gomock.Any(), userID, id, 10).
+			Return([]webhook.Delivery{{ID: uuid.New(), Status: webhook.DeliveryFailed, Payload: []byte(`{}`)}}, nil)
+
+		w := httptest.NewRecorder()
+		handler.ListDeliveries(w, request(http.MethodGet, "/webhooks/"+id.String()+"/deliveries?limit=10", map[string]string{"id": id.String()}))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"failed"`)
+	})
+
+	t.Run("ошибка: limit вне диапазона", func(t *testing.T) {
+		_, _, handler := setupHandlerTest(t)
+
+		w := httptest.NewRecorder()
+		handler.ListDeliveries(w, request(http.MethodGet, "/webhooks/"+id.String()+"/deliveries?limit=1000", map[string]string{"id": id.String()}))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("повтор доставки", func(t *testing.T) {
+		ctrl, mockService, handler := setupHandlerTest(t)
+		defer ctrl.Finish()
+
+		deliveryID := uuid.New()
+		mockService.EXPECT().Redeliver(gomock.Any(), userID, id, deliveryID).
+			Return(&webhook.Delivery{ID: deliveryID, Status: webhook.DeliveryPending, Payload: []byte(`{}`)}, nil)
+
+		w := httptest.NewRecorder()
+		handler.Redeliver(w, request(http.MethodPost, "/webhooks/"+id.String()+"/deliveries/"+deliveryID.String()+"/redeliver",
+			map[string]string{"id": id.String(), "deliveryID": deliveryID.String()}))
+		assert.Equal(t, http.StatusAccepted, w.Code)
+	})
+}
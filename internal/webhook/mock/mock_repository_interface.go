@@ -0,0 +1,261 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/webhook/service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/webhook/service.go -destination=internal/webhook/mock/mock_repository_interface.go -package=mockwebhook
+//
+
+// Package mockwebhook is a generated GoMock package.
+package mockwebhook
+
+import (
+	context "context"
+	webhook "marketplace-api/internal/webhook"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepositoryInterface is a mock of RepositoryInterface interface.
+type MockRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryInterfaceMockRecorder is the mock recorder for MockRepositoryInterface.
+type MockRepositoryInterfaceMockRecorder struct {
+	mock *MockRepositoryInterface
+}
+
+// NewMockRepositoryInterface creates a new mock instance.
+func NewMockRepositoryInterface(ctrl *gomock.Controller) *MockRepositoryInterface {
+	mock := &MockRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepositoryInterface) EXPECT() *MockRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ClaimDue mocks base method.
+func (m *MockRepositoryInterface) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]webhook.Outgoing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDue", ctx, limit, lease)
+	ret0, _ := ret[0].([]webhook.Outgoing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDue indicates an expected call of ClaimDue.
+func (mr *MockRepositoryInterfaceMockRecorder) ClaimDue(ctx, limit, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDue", reflect.TypeOf((*MockRepositoryInterface)(nil).ClaimDue), ctx, limit, lease)
+}
+
+// CreateDeliveries mocks base method.
+func (m *MockRepositoryInterface) CreateDeliveries(ctx context.Context, deliveries []webhook.Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDeliveries", ctx, deliveries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDeliveries indicates an expected call of CreateDeliveries.
+func (mr *MockRepositoryInterfaceMockRecorder) CreateDeliveries(ctx, deliveries any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeliveries", reflect.TypeOf((*MockRepositoryInterface)(nil).CreateDeliveries), ctx, deliveries)
+}
+
+// CreateEndpoint mocks base method.
+func (m *MockRepositoryInterface) CreateEndpoint(ctx context.Context, e *webhook.Endpoint) (*webhook.Endpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEndpoint", ctx, e)
+	ret0, _ := ret[0].(*webhook.Endpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEndpoint indicates an expected call of CreateEndpoint.
+func (mr *MockRepositoryInterfaceMockRecorder) CreateEndpoint(ctx, e any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEndpoint", reflect.TypeOf((*MockRepositoryInterface)(nil).CreateEndpoint), ctx, e)
+}
+
+// DeleteEndpoint mocks base method.
+func (m *MockRepositoryInterface) DeleteEndpoint(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEndpoint", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEndpoint indicates an expected call of DeleteEndpoint.
+func (mr *MockRepositoryInterfaceMockRecorder) DeleteEndpoint(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEndpoint", reflect.TypeOf((*MockRepositoryInterface)(nil).DeleteEndpoint), ctx, id)
+}
+
+// EnableEndpoint mocks base method.
+func (m *MockRepositoryInterface) EnableEndpoint(ctx context.Context, id uuid.UUID) (*webhook.Endpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableEndpoint", ctx, id)
+	ret0, _ := ret[0].(*webhook.Endpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableEndpoint indicates an expected call of EnableEndpoint.
+func (mr *MockRepositoryInterfaceMockRecorder) EnableEndpoint(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableEndpoint", reflect.TypeOf((*MockRepositoryInterface)(nil).EnableEndpoint), ctx, id)
+}
+
+// GetDelivery mocks base method.
+func (m *MockRepositoryInterface) GetDelivery(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDelivery", ctx, id)
+	ret0, _ := ret[0].(*webhook.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDelivery indicates an expected call of GetDelivery.
+func (mr *MockRepositoryInterfaceMockRecorder) GetDelivery(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDelivery", reflect.TypeOf((*MockRepositoryInterface)(nil).GetDelivery), ctx, id)
+}
+
+// GetEndpoint mocks base method.
+func (m *MockRepositoryInterface) GetEndpoint(ctx context.Context, id uuid.UUID) (*webhook.Endpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEndpoint", ctx, id)
+	ret0, _ := ret[0].(*webhook.Endpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEndpoint indicates an expected call of GetEndpoint.
+func (mr *MockRepositoryInterfaceMockRecorder) GetEndpoint(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEndpoint", reflect.TypeOf((*MockRepositoryInterface)(nil).GetEndpoint), ctx, id)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockRepositoryInterface) ListDeliveries(ctx context.Context, endpointID uuid.UUID, limit int) ([]webhook.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", ctx, endpointID, limit)
+	ret0, _ := ret[0].([]webhook.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockRepositoryInterfaceMockRecorder) ListDeliveries(ctx, endpointID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockRepositoryInterface)(nil).ListDeliveries), ctx, endpointID, limit)
+}
+
+// ListEndpoints mocks base method.
+func (m *MockRepositoryInterface) ListEndpoints(ctx context.Context, userID uuid.UUID) ([]webhook.Endpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEndpoints", ctx, userID)
+	ret0, _ := ret[0].([]webhook.Endpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEndpoints indicates an expected call of ListEndpoints.
+func (mr *MockRepositoryInterfaceMockRecorder) ListEndpoints(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEndpoints", reflect.TypeOf((*MockRepositoryInterface)(nil).ListEndpoints), ctx, userID)
+}
+
+// ListSubscribed mocks base method.
+func (m *MockRepositoryInterface) ListSubscribed(ctx context.Context, userID uuid.UUID, eventType string) ([]webhook.Endpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscribed", ctx, userID, eventType)
+	ret0, _ := ret[0].([]webhook.Endpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscribed indicates an expected call of ListSubscribed.
+func (mr *MockRepositoryInterfaceMockRecorder) ListSubscribed(ctx, userID, eventType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscribed", reflect.TypeOf((*MockRepositoryInterface)(nil).ListSubscribed), ctx, userID, eventType)
+}
+
+// Redeliver mocks base method.
+func (m *MockRepositoryInterface) Redeliver(ctx context.Context, id uuid.UUID) (*webhook.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeliver", ctx, id)
+	ret0, _ := ret[0].(*webhook.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Redeliver indicates an expected call of Redeliver.
+func (mr *MockRepositoryInterfaceMockRecorder) Redeliver(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeliver", reflect.TypeOf((*MockRepositoryInterface)(nil).Redeliver), ctx, id)
+}
+
+// SaveAttempt mocks base method.
+func (m *MockRepositoryInterface) SaveAttempt(ctx context.Context, d *webhook.Delivery, disableAfter int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAttempt", ctx, d, disableAfter)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveAttempt indicates an expected call of SaveAttempt.
+func (mr *MockRepositoryInterfaceMockRecorder) SaveAttempt(ctx, d, disableAfter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAttempt", reflect.TypeOf((*MockRepositoryInterface)(nil).SaveAttempt), ctx, d, disableAfter)
+}
+
+// MockSender is a mock of Sender interface.
+type MockSender struct {
+	ctrl     *gomock.Controller
+	recorder *MockSenderMockRecorder
+	isgomock struct{}
+}
+
+// MockSenderMockRecorder is the mock recorder for MockSender.
+type MockSenderMockRecorder struct {
+	mock *MockSender
+}
+
+// NewMockSender creates a new mock instance.
+func NewMockSender(ctrl *gomock.Controller) *MockSender {
+	mock := &MockSender{ctrl: ctrl}
+	mock.recorder = &MockSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSender) EXPECT() *MockSenderMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method.
+func (m *MockSender) Send(ctx context.Context, o *webhook.Outgoing) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, o)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockSenderMockRecorder) Send(ctx, o any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockSender)(nil).Send), ctx, o)
+}
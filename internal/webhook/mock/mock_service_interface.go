@@ -0,0 +1,132 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/webhook/handler.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/webhook/handler.go -destination=internal/webhook/mock/mock_service_interface.go -package=mockwebhook
+//
+
+// Package mockwebhook is a generated GoMock package.
+package mockwebhook
+
+import (
+	context "context"
+	webhook "marketplace-api/internal/webhook"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockServiceInterface is a mock of ServiceInterface interface.
+type MockServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockServiceInterfaceMockRecorder is the mock recorder for MockServiceInterface.
+type MockServiceInterfaceMockRecorder struct {
+	mock *MockServiceInterface
+}
+
+// NewMockServiceInterface creates a new mock instance.
+func NewMockServiceInterface(ctrl *gomock.Controller) *MockServiceInterface {
+	mock := &MockServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockServiceInterface) EXPECT() *MockServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateEndpoint mocks base method.
+func (m *MockServiceInterface) CreateEndpoint(ctx context.Context, input *webhook.CreateEndpointInput) (*webhook.Endpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEndpoint", ctx, input)
+	ret0, _ := ret[0].(*webhook.Endpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEndpoint indicates an expected call of CreateEndpoint.
+func (mr *MockServiceInterfaceMockRecorder) CreateEndpoint(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEndpoint", reflect.TypeOf((*MockServiceInterface)(nil).CreateEndpoint), ctx, input)
+}
+
+// DeleteEndpoint mocks base method.
+func (m *MockServiceInterface) DeleteEndpoint(ctx context.Context, userID, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEndpoint", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEndpoint indicates an expected call of DeleteEndpoint.
+func (mr *MockServiceInterfaceMockRecorder) DeleteEndpoint(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEndpoint", reflect.TypeOf((*MockServiceInterface)(nil).DeleteEndpoint), ctx, userID, id)
+}
+
+// EnableEndpoint mocks base method.
+func (m *MockServiceInterface) EnableEndpoint(ctx context.Context, userID, id uuid.UUID) (*webhook.Endpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableEndpoint", ctx, userID, id)
+	ret0, _ := ret[0].(*webhook.Endpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableEndpoint indicates an expected call of EnableEndpoint.
+func (mr *MockServiceInterfaceMockRecorder) EnableEndpoint(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableEndpoint", reflect.TypeOf((*MockServiceInterface)(nil).EnableEndpoint), ctx, userID, id)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockServiceInterface) ListDeliveries(ctx context.Context, userID, endpointID uuid.UUID, limit int) ([]webhook.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", ctx, userID, endpointID, limit)
+	ret0, _ := ret[0].([]webhook.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockServiceInterfaceMockRecorder) ListDeliveries(ctx, userID, endpointID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockServiceInterface)(nil).ListDeliveries), ctx, userID, endpointID, limit)
+}
+
+// ListEndpoints mocks base method.
+func (m *MockServiceInterface) ListEndpoints(ctx context.Context, userID uuid.UUID) ([]webhook.Endpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEndpoints", ctx, userID)
+	ret0, _ := ret[0].([]webhook.Endpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEndpoints indicates an expected call of ListEndpoints.
+func (mr *MockServiceInterfaceMockRecorder) ListEndpoints(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEndpoints", reflect.TypeOf((*MockServiceInterface)(nil).ListEndpoints), ctx, userID)
+}
+
+// Redeliver mocks base method.
+func (m *MockServiceInterface) Redeliver(ctx context.Context, userID, endpointID, deliveryID uuid.UUID) (*webhook.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeliver", ctx, userID, endpointID, deliveryID)
+	ret0, _ := ret[0].(*webhook.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Redeliver indicates an expected call of Redeliver.
+func (mr *MockServiceInterfaceMockRecorder) Redeliver(ctx, userID, endpointID, deliveryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeliver", reflect.TypeOf((*MockServiceInterface)(nil).Redeliver), ctx, userID, endpointID, deliveryID)
+}
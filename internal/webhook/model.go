@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"encoding/json"
+	"marketplace-api/internal/advertisement"
+	"marketplace-api/internal/auction"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventTypes - события, на которые можно подписаться. Партнёр получает события
+// только о своих объявлениях. Переписки между пользователями в приложении нет, поэтому
+// событий о новых сообщениях тоже нет
+var EventTypes = []string{
+	advertisement.EventCreated,
+	advertisement.EventUpdated,
+	advertisement.EventPublished,
+	advertisement.EventRenewed,
+	advertisement.EventExpired,
+	auction.EventSold,
+}
+
+// Статусы доставки
+const (
+	DeliveryPending   = "pending"   // ожидает отправки или повтора
+	DeliverySucceeded = "succeeded" // получатель ответил 2xx
+	DeliveryFailed    = "failed"    // попытки исчерпаны
+)
+
+// Endpoint - адрес партнёра для уведомлений
+type Endpoint struct {
+	ID                  uuid.UUID  `json:"id"`
+	UserID              uuid.UUID  `json:"-"`
+	URL                 string     `json:"url"`
+	EventTypes          []string   `json:"event_types"`
+	Secret              string     `json:"secret,omitempty"`      // ключ подписи, возвращается только при создании
+	ConsecutiveFailures int        `json:"consecutive_failures"`  // неудачных доставок подряд
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"` // отключён после серии неудачных доставок
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+type CreateEndpointInput struct {
+	UserID     uuid.UUID `swaggerignore:"true"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+}
+
+// Delivery - доставка события на адрес и её журнал
+type Delivery struct {
+	ID             uuid.UUID       `json:"id"`
+	EndpointID     uuid.UUID       `json:"endpoint_id"`
+	EventID        uuid.UUID       `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"` // тело запроса к получателю
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus *int            `json:"response_status,omitempty"` // HTTP-статус последней попытки
+	LastError      string          `json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+// Outgoing - доставка, выбранная для отправки, с адресом и ключом подписи
+type Outgoing struct {
+	Delivery
+	URL    string
+	Secret string
+}
+
+// Envelope - тело запроса к получателю
+type Envelope struct {
+	ID         uuid.UUID       `json:"id"` // ID события, одинаков при повторах
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
@@ -0,0 +1,270 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"marketplace-api/internal/db"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+func NewWebhookRepository(pool *pgxpool.Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+const endpointColumns = `id, user_id, url, event_types, consecutive_failures, disabled_at, created_at`
+
+const deliveryColumns = `d.id, d.endpoint_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+	d.response_status, d.last_error, d.next_attempt_at, d.created_at, d.delivered_at`
+
+func scanEndpoint(row pgx.Row, e *Endpoint) error {
+	return row.Scan(&e.ID, &e.UserID, &e.URL, &e.EventTypes, &e.ConsecutiveFailures, &e.DisabledAt, &e.CreatedAt)
+}
+
+func scanDelivery(row pgx.Row, d *Delivery, extra ...any) error {
+	var payload []byte
+	dest := append([]any{&d.ID, &d.EndpointID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts,
+		&d.ResponseStatus, &d.LastError, &d.NextAttemptAt, &d.CreatedAt, &d.DeliveredAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return err
+	}
+	d.Payload = payload
+	return nil
+}
+
+func (r *Repository) CreateEndpoint(ctx context.Context, e *Endpoint) (*Endpoint, error) {
+	query := `
+		INSERT INTO webhook_endpoints (user_id, url, event_types, secret)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`
+	err := db.Conn(ctx, r.pool).QueryRow(ctx, query, e.UserID, e.URL, e.EventTypes, e.Secret).Scan(&e.ID, &e.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (r *Repository) ListEndpoints(ctx context.Context, userID uuid.UUID) ([]Endpoint, error) {
+	query := `SELECT ` + endpointColumns + ` FROM webhook_endpoints WHERE user_id = $1 ORDER BY created_at`
+	return r.queryEndpoints(ctx, query, userID)
+}
+
+// GetEndpoint - адрес по ID (или nil, если не найден)
+func (r *Repository) GetEndpoint(ctx context.Context, id uuid.UUID) (*Endpoint, error) {
+	var e Endpoint
+	err := scanEndpoint(db.Conn(ctx, r.pool).QueryRow(ctx, `SELECT `+endpointColumns+` FROM webhook_endpoints WHERE id = $1`, id), &e)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// DeleteEndpoint - доставки удаляются каскадно
+func (r *Repository) DeleteEndpoint(ctx context.Context, id uuid.UUID) error {
+	tag, err := db.Conn(ctx, r.pool).Exec(ctx, `DELETE FROM webhook_endpoints WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrEndpointNotFound
+	}
+	return nil
+}
+
+func (r *Repository) EnableEndpoint(ctx context.Context, id uuid.UUID) (*Endpoint, error) {
+	query := `
+		UPDATE webhook_endpoints SET disabled_at = NULL, consecutive_failures = 0
+		WHERE id = $1
+		RETURNING ` + endpointColumns
+	var e Endpoint
+	err := scanEndpoint(db.Conn(ctx, r.pool).QueryRow(ctx, query, id), &e)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrEndpointNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// ListSubscribed - включённые адреса пользователя, подписанные на eventType
+func (r *Repository) ListSubscribed(ctx context.Context, userID uuid.UUID, eventType string) ([]Endpoint, error) {
+	query := `
+		SELECT ` + endpointColumns + ` FROM webhook_endpoints
+		WHERE user_id = $1 AND $2 = ANY(event_types) AND disabled_at IS NULL`
+	return r.queryEndpoints(ctx, query, userID, eventType)
+}
+
+func (r *Repository) queryEndpoints(ctx context.Context, query string, args ...any) ([]Endpoint, error) {
+	rows, err := db.Conn(ctx, r.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	endpoints := []Endpoint{}
+	for rows.Next() {
+		var e Endpoint
+		if err := scanEndpoint(rows, &e); err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, e)
+	}
+	return endpoints, rows.Err()
+}
+
+// CreateDeliveries - событие ставится на адрес не более одного раза
+func (r *Repository) CreateDeliveries(ctx context.Context, deliveries []Delivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (endpoint_id, event_id, event_type, payload, status)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (endpoint_id, event_id) DO NOTHING`
+
+	conn := db.Conn(ctx, r.pool)
+	for _, d := range deliveries {
+		if _, err := conn.Exec(ctx, query, d.EndpointID, d.EventID, d.EventType, []byte(d.Payload), d.Status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Repository) ListDeliveries(ctx context.Context, endpointID uuid.UUID, limit int) ([]Delivery, error) {
+	query := `
+		SELECT ` + deliveryColumns + ` FROM webhook_deliveries d
+		WHERE d.endpoint_id = $1
+		ORDER BY d.created_at DESC
+		LIMIT $2`
+	rows, err := db.Conn(ctx, r.pool).Query(ctx, query, endpointID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []Delivery{}
+	for rows.Next() {
+		var d Delivery
+		if err := scanDelivery(rows, &d); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// GetDelivery - доставка по ID (или nil, если не найдена)
+func (r *Repository) GetDelivery(ctx context.Context, id uuid.UUID) (*Delivery, error) {
+	var d Delivery
+	err := scanDelivery(db.Conn(ctx, r.pool).QueryRow(ctx, `SELECT `+deliveryColumns+` FROM webhook_deliveries d WHERE d.id = $1`, id), &d)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Redeliver - возвращает доставку в очередь с обнулённым счётчиком попыток
+func (r *Repository) Redeliver(ctx context.Context, id uuid.UUID) (*Delivery, error) {
+	query := `
+		UPDATE webhook_deliveries d
+		SET status = 'pending', attempts = 0, next_attempt_at = now()
+		WHERE d.id = $1
+		RETURNING ` + deliveryColumns
+	var d Delivery
+	err := scanDelivery(db.Conn(ctx, r.pool).QueryRow(ctx, query, id), &d)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDeliveryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// ClaimDue - берёт в работу готовые доставки на включённые адреса, откладывая их на lease.
+// Если отправитель не сохранит результат (например, упадёт), доставка вернётся в очередь
+// по истечении lease, а SKIP LOCKED разводит экземпляры приложения по разным доставкам
+func (r *Repository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]Outgoing, error) {
+	query := `
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = now() + $2 * interval '1 millisecond'
+		FROM webhook_endpoints e
+		WHERE e.id = d.endpoint_id AND d.id IN (
+			SELECT q.id FROM webhook_deliveries q
+			JOIN webhook_endpoints qe ON qe.id = q.endpoint_id
+			WHERE q.status = 'pending' AND q.next_attempt_at <= now() AND qe.disabled_at IS NULL
+			ORDER BY q.next_attempt_at
+			LIMIT $1
+			FOR UPDATE OF q SKIP LOCKED)
+		RETURNING ` + deliveryColumns + `, e.url, e.secret`
+
+	rows, err := db.Conn(ctx, r.pool).Query(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var due []Outgoing
+	for rows.Next() {
+		var o Outgoing
+		if err := scanDelivery(rows, &o.Delivery, &o.URL, &o.Secret); err != nil {
+			return nil, err
+		}
+		due = append(due, o)
+	}
+	return due, rows.Err()
+}
+
+// SaveAttempt - сохраняет результат попытки и счётчик неудач адреса. Возвращает true,
+// если адрес отключён этой попыткой после disableAfter неудач подряд
+func (r *Repository) SaveAttempt(ctx context.Context, d *Delivery, disableAfter int) (bool, error) {
+	tx, err := db.Conn(ctx, r.pool).Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, response_status = $4, last_error = $5, next_attempt_at = $6, delivered_at = $7
+		WHERE id = $1`
+	if _, err := tx.Exec(ctx, query, d.ID, d.Status, d.Attempts, d.ResponseStatus, d.LastError, d.NextAttemptAt, d.DeliveredAt); err != nil {
+		return false, err
+	}
+
+	if d.Status == DeliverySucceeded {
+		if _, err := tx.Exec(ctx, `UPDATE webhook_endpoints SET consecutive_failures = 0 WHERE id = $1`, d.EndpointID); err != nil {
+			return false, err
+		}
+		return false, tx.Commit(ctx)
+	}
+
+	var disabled bool
+	query = `
+		UPDATE webhook_endpoints e
+		SET consecutive_failures = e.consecutive_failures + 1,
+		    disabled_at = CASE WHEN e.disabled_at IS NULL AND e.consecutive_failures + 1 >= $2 THEN now() ELSE e.disabled_at END
+		WHERE e.id = $1
+		RETURNING e.disabled_at IS NOT NULL AND e.consecutive_failures = $2`
+	err = tx.QueryRow(ctx, query, d.EndpointID, disableAfter).Scan(&disabled)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Адрес удалён во время отправки
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return disabled, tx.Commit(ctx)
+}
@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// maxResponseBytes - сколько ответа получателя читается, остальное отбрасывается
+const maxResponseBytes = 64 << 10
+
+var ErrPrivateAddress = errors.New("webhook endpoint resolves to a private address")
+
+// HTTPSender - отправка подписанных уведомлений по HTTP
+type HTTPSender struct {
+	client *http.Client
+}
+
+// NewHTTPSender - отправитель с ограничением времени запроса timeout. Без allowPrivate
+// соединения с внутренними адресами запрещены: проверяется уже разрешённый IP, поэтому
+// DNS-имя партнёра не может указывать внутрь сети. Перенаправления не выполняются
+func NewHTTPSender(timeout time.Duration, allowPrivate bool) *HTTPSender {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isPrivate(ip) {
+				return ErrPrivateAddress
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &HTTPSender{client: &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+// Send - отправляет доставку и возвращает HTTP-статус ответа (0, если ответа нет).
+// Успехом считается только ответ 2xx
+func (s *HTTPSender) Send(ctx context.Context, o *Outgoing) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.URL, bytes.NewReader(o.Payload))
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "marketplace-api-webhooks/1")
+	req.Header.Set(HeaderID, o.ID.String())
+	req.Header.Set(HeaderEvent, o.EventType)
+	req.Header.Set(HeaderTimestamp, fmt.Sprint(now.Unix()))
+	req.Header.Set(HeaderSignature, Sign(o.Secret, now, o.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// isPrivate - адрес недоступен из интернета: локальный, частной сети или служебный
+func isPrivate(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast()
+}
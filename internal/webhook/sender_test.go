@@ -0,0 +1,95 @@
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"marketplace-api/internal/webhook"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func outgoing(url string) *webhook.Outgoing {
+	return &webhook.Outgoing{
+		Delivery: webhook.Delivery{
+			ID:        uuid.New(),
+			EventType: "advertisement.created",
+			Payload:   []byte(`{"id":"1"}`),
+		},
+		URL:    url,
+		Secret: "whsec_test",
+	}
+}
+
+func TestHTTPSender_Send(t *testing.T) {
+	t.Run("получатель проверяет подпись", func(t *testing.T) {
+		var verified error
+		var header http.Header
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			header = r.Header
+			verified = webhook.Verify("whsec_test", r.Header, body, time.Minute, time.Now())
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer receiver.Close()
+
+		o := outgoing(receiver.URL)
+		status, err := webhook.NewHTTPSender(time.Second, true).Send(context.Background(), o)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, status)
+		assert.NoError(t, verified)
+		assert.Equal(t, o.ID.String(), header.Get(webhook.HeaderID))
+		assert.Equal(t, "advertisement.created", header.Get(webhook.HeaderEvent))
+		assert.Equal(t, "application/json", header.Get("Content-Type"))
+	})
+
+	t.Run("ошибка: ответ не 2xx", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer receiver.Close()
+
+		status, err := webhook.NewHTTPSender(time.Second, true).Send(context.Background(), outgoing(receiver.URL))
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, status)
+	})
+
+	t.Run("ошибка: перенаправление не выполняется", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+		}))
+		defer receiver.Close()
+
+		status, err := webhook.NewHTTPSender(time.Second, true).Send(context.Background(), outgoing(receiver.URL))
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusFound, status)
+	})
+
+	t.Run("ошибка: истекло время ответа", func(t *testing.T) {
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+		}))
+		defer receiver.Close()
+
+		status, err := webhook.NewHTTPSender(50*time.Millisecond, true).Send(context.Background(), outgoing(receiver.URL))
+		assert.Error(t, err)
+		assert.Zero(t, status)
+	})
+
+	t.Run("ошибка: внутренний адрес запрещён", func(t *testing.T) {
+		called := false
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer receiver.Close()
+
+		_, err := webhook.NewHTTPSender(time.Second, false).Send(context.Background(), outgoing(receiver.URL))
+		assert.ErrorIs(t, err, webhook.ErrPrivateAddress)
+		assert.False(t, called)
+	})
+}
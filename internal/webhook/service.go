@@ -0,0 +1,318 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"marketplace-api/internal/logging"
+	"marketplace-api/internal/metrics"
+	"marketplace-api/internal/outbox"
+	"marketplace-api/internal/tracing"
+	"net"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrEndpointNotFound = errors.New("webhook endpoint not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrInvalidURL       = errors.New("webhook url must be an absolute https url of a public host")
+	ErrUnknownEventType = errors.New("unknown webhook event type")
+	ErrNoEventTypes     = errors.New("at least one event type is required")
+)
+
+type RepositoryInterface interface {
+	CreateEndpoint(ctx context.Context, e *Endpoint) (*Endpoint, error)
+	ListEndpoints(ctx context.Context, userID uuid.UUID) ([]Endpoint, error)
+	GetEndpoint(ctx context.Context, id uuid.UUID) (*Endpoint, error)
+	DeleteEndpoint(ctx context.Context, id uuid.UUID) error
+	EnableEndpoint(ctx context.Context, id uuid.UUID) (*Endpoint, error)
+	ListSubscribed(ctx context.Context, userID uuid.UUID, eventType string) ([]Endpoint, error)
+
+	CreateDeliveries(ctx context.Context, deliveries []Delivery) error
+	ListDeliveries(ctx context.Context, endpointID uuid.UUID, limit int) ([]Delivery, error)
+	GetDelivery(ctx context.Context, id uuid.UUID) (*Delivery, error)
+	Redeliver(ctx context.Context, id uuid.UUID) (*Delivery, error)
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]Outgoing, error)
+	SaveAttempt(ctx context.Context, d *Delivery, disableAfter int) (bool, error)
+}
+
+// Sender - отправка доставки получателю, возвращает HTTP-статус ответа
+type Sender interface {
+	Send(ctx context.Context, o *Outgoing) (int, error)
+}
+
+// Policy - правила регистрации адресов и повторов доставки
+type Policy struct {
+	AllowInsecure bool          // разрешить http и внутренние адреса (для разработки)
+	MaxAttempts   int           // попыток на одну доставку
+	DisableAfter  int           // адрес отключается после стольких неудачных попыток подряд
+	RetryMin      time.Duration // пауза после первой неудачи, дальше растёт вдвое
+	RetryMax      time.Duration
+	Lease         time.Duration // доставка, взятая в работу, не выбирается повторно до истечения срока
+}
+
+// delay - пауза после failures неудачных попыток
+func (p Policy) delay(failures int) time.Duration {
+	d := p.RetryMin
+	for i := 1; i < failures && d < p.RetryMax; i++ {
+		d *= 2
+	}
+	return min(d, p.RetryMax)
+}
+
+type Service struct {
+	repo   RepositoryInterface
+	sender Sender
+	policy Policy
+}
+
+func NewWebhookService(repo RepositoryInterface, sender Sender, policy Policy) *Service {
+	return &Service{repo: repo, sender: sender, policy: policy}
+}
+
+// CreateEndpoint - регистрация адреса. Ключ подписи возвращается только в ответе на создание
+func (s *Service) CreateEndpoint(ctx context.Context, input *CreateEndpointInput) (*Endpoint, error) {
+	ctx, span := tracing.Start(ctx, "webhook.Service.CreateEndpoint")
+	defer span.End()
+
+	if err := s.validateURL(input.URL); err != nil {
+		return nil, err
+	}
+	if len(input.EventTypes) == 0 {
+		return nil, ErrNoEventTypes
+	}
+	eventTypes := make([]string, 0, len(input.EventTypes))
+	for _, t := range input.EventTypes {
+		if !slices.Contains(EventTypes, t) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownEventType, t)
+		}
+		if !slices.Contains(eventTypes, t) {
+			eventTypes = append(eventTypes, t)
+		}
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.CreateEndpoint(ctx, &Endpoint{
+		UserID:     input.UserID,
+		URL:        input.URL,
+		EventTypes: eventTypes,
+		Secret:     secret,
+	})
+}
+
+// ListEndpoints - адреса пользователя
+func (s *Service) ListEndpoints(ctx context.Context, userID uuid.UUID) ([]Endpoint, error) {
+	ctx, span := tracing.Start(ctx, "webhook.Service.ListEndpoints")
+	defer span.End()
+
+	return s.repo.ListEndpoints(ctx, userID)
+}
+
+// DeleteEndpoint - удаление адреса вместе с журналом доставок
+func (s *Service) DeleteEndpoint(ctx context.Context, userID, id uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "webhook.Service.DeleteEndpoint")
+	defer span.End()
+
+	if _, err := s.ownEndpoint(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.repo.DeleteEndpoint(ctx, id)
+}
+
+// EnableEndpoint - включение отключённого адреса: ожидающие доставки возобновляются
+func (s *Service) EnableEndpoint(ctx context.Context, userID, id uuid.UUID) (*Endpoint, error) {
+	ctx, span := tracing.Start(ctx, "webhook.Service.EnableEndpoint")
+	defer span.End()
+
+	if _, err := s.ownEndpoint(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	return s.repo.EnableEndpoint(ctx, id)
+}
+
+// ListDeliveries - журнал последних доставок на адрес, новые первыми
+func (s *Service) ListDeliveries(ctx context.Context, userID, endpointID uuid.UUID, limit int) ([]Delivery, error) {
+	ctx, span := tracing.Start(ctx, "webhook.Service.ListDeliveries")
+	defer span.End()
+
+	if _, err := s.ownEndpoint(ctx, userID, endpointID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(ctx, endpointID, limit)
+}
+
+// Redeliver - повторная отправка доставки с новым запасом попыток. Получатель
+// узнаёт повтор по тому же ID события в теле запроса
+func (s *Service) Redeliver(ctx context.Context, userID, endpointID, deliveryID uuid.UUID) (*Delivery, error) {
+	ctx, span := tracing.Start(ctx, "webhook.Service.Redeliver")
+	defer span.End()
+
+	if _, err := s.ownEndpoint(ctx, userID, endpointID); err != nil {
+		return nil, err
+	}
+	d, err := s.repo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil || d.EndpointID != endpointID {
+		return nil, ErrDeliveryNotFound
+	}
+	return s.repo.Redeliver(ctx, deliveryID)
+}
+
+// HandleEvent - подписчик outbox: ставит событие в очередь на адреса владельца объявления,
+// подписанные на его тип. Повторная обработка того же события доставок не дублирует
+func (s *Service) HandleEvent(ctx context.Context, e outbox.Event) error {
+	var owner struct {
+		AuthorID uuid.UUID `json:"author_id"`
+		SellerID uuid.UUID `json:"seller_id"`
+	}
+	if err := e.Decode(&owner); err != nil {
+		return err
+	}
+	userID := owner.AuthorID
+	if userID == uuid.Nil {
+		userID = owner.SellerID
+	}
+	if userID == uuid.Nil {
+		return nil
+	}
+
+	endpoints, err := s.repo.ListSubscribed(ctx, userID, e.Type)
+	if err != nil || len(endpoints) == 0 {
+		return err
+	}
+
+	payload, err := json.Marshal(Envelope{ID: e.ID, Type: e.Type, OccurredAt: e.OccurredAt, Data: e.Payload})
+	if err != nil {
+		return err
+	}
+	deliveries := make([]Delivery, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		deliveries = append(deliveries, Delivery{
+			EndpointID: endpoint.ID,
+			EventID:    e.ID,
+			EventType:  e.Type,
+			Payload:    payload,
+			Status:     DeliveryPending,
+		})
+	}
+	return s.repo.CreateDeliveries(ctx, deliveries)
+}
+
+// DeliverDue - отправляет до limit готовых доставок параллельно и возвращает их число.
+// Неудачная попытка откладывается с растущей паузой, после MaxAttempts доставка
+// считается проваленной
+func (s *Service) DeliverDue(ctx context.Context, limit int) (int, error) {
+	due, err := s.repo.ClaimDue(ctx, limit, s.policy.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for i := range due {
+		wg.Add(1)
+		go func(o *Outgoing) {
+			defer wg.Done()
+			s.deliver(ctx, o)
+		}(&due[i])
+	}
+	wg.Wait()
+	return len(due), nil
+}
+
+func (s *Service) deliver(ctx context.Context, o *Outgoing) {
+	status, err := s.sender.Send(ctx, o)
+	if ctx.Err() != nil {
+		// Остановка приложения: доставка вернётся в очередь по истечении аренды
+		return
+	}
+
+	d := &o.Delivery
+	d.Attempts++
+	d.ResponseStatus = nil
+	if status != 0 {
+		d.ResponseStatus = &status
+	}
+	log := logging.FromContext(ctx).With("delivery_id", d.ID, "endpoint_id", d.EndpointID, "event_type", d.EventType, "attempt", d.Attempts)
+
+	now := time.Now()
+	switch {
+	case err == nil:
+		metrics.WebhookDelivery("succeeded")
+		d.Status, d.LastError, d.NextAttemptAt, d.DeliveredAt = DeliverySucceeded, "", nil, &now
+	case d.Attempts >= s.policy.MaxAttempts:
+		metrics.WebhookDelivery("failed")
+		log.Warn("webhook delivery failed", "error", err)
+		d.Status, d.LastError, d.NextAttemptAt = DeliveryFailed, err.Error(), nil
+	default:
+		metrics.WebhookDelivery("retry")
+		log.Info("webhook delivery failed, will retry", "error", err)
+		next := now.Add(s.policy.delay(d.Attempts))
+		d.Status, d.LastError, d.NextAttemptAt = DeliveryPending, err.Error(), &next
+	}
+
+	disabled, err := s.repo.SaveAttempt(ctx, d, s.policy.DisableAfter)
+	if err != nil {
+		log.Error("error saving webhook delivery attempt", "error", err)
+		return
+	}
+	if disabled {
+		log.Warn("webhook endpoint disabled after repeated failures")
+	}
+}
+
+// ownEndpoint - адрес id, если он принадлежит пользователю. Чужой адрес неотличим от
+// несуществующего
+func (s *Service) ownEndpoint(ctx context.Context, userID, id uuid.UUID) (*Endpoint, error) {
+	endpoint, err := s.repo.GetEndpoint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint == nil || endpoint.UserID != userID {
+		return nil, ErrEndpointNotFound
+	}
+	return endpoint, nil
+}
+
+// validateURL - адрес должен быть абсолютным https-адресом публичного хоста. Имена
+// хостов окончательно проверяет отправитель при соединении
+func (s *Service) validateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || u.User != nil {
+		return ErrInvalidURL
+	}
+	if s.policy.AllowInsecure {
+		if u.Scheme != "https" && u.Scheme != "http" {
+			return ErrInvalidURL
+		}
+		return nil
+	}
+	if u.Scheme != "https" || u.Hostname() == "localhost" {
+		return ErrInvalidURL
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && isPrivate(ip) {
+		return ErrInvalidURL
+	}
+	return nil
+}
+
+// newSecret - случайный ключ подписи
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,56 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Заголовки запроса к получателю
+const (
+	HeaderID        = "Webhook-Id"        // ID доставки
+	HeaderEvent     = "Webhook-Event"     // тип события
+	HeaderTimestamp = "Webhook-Timestamp" // время отправки, unix-секунды
+	HeaderSignature = "Webhook-Signature" // v1=<hex HMAC-SHA256 от "timestamp.body">
+)
+
+const signatureVersion = "v1="
+
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Sign - подпись тела body, отправленного в timestamp. Время входит в подпись,
+// чтобы получатель мог отклонять повторно отправленные старые запросы
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signatureVersion + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify - проверка подписи на стороне получателя: подпись верна и отправлена
+// не раньше чем за tolerance до now
+func Verify(secret string, header http.Header, body []byte, tolerance time.Duration, now time.Time) error {
+	unix, err := strconv.ParseInt(header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	timestamp := time.Unix(unix, 0)
+	if now.Sub(timestamp) > tolerance || timestamp.Sub(now) > tolerance {
+		return ErrInvalidSignature
+	}
+
+	expected := Sign(secret, timestamp, body)
+	// Несколько подписей через запятую - на время смены ключа
+	for _, signature := range strings.Split(header.Get(HeaderSignature), ",") {
+		if hmac.Equal([]byte(strings.TrimSpace(signature)), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
@@ -0,0 +1,58 @@
+package webhook_test
+
+import (
+	"marketplace-api/internal/webhook"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	const secret = "whsec_test"
+	body := []byte(`{"id":"1"}`)
+	now := time.Unix(1_800_000_000, 0)
+
+	signed := func(ts time.Time, signature string) http.Header {
+		h := http.Header{}
+		h.Set(webhook.HeaderTimestamp, strconv.FormatInt(ts.Unix(), 10))
+		h.Set(webhook.HeaderSignature, signature)
+		return h
+	}
+
+	t.Run("верная подпись", func(t *testing.T) {
+		header := signed(now, webhook.Sign(secret, now, body))
+		assert.NoError(t, webhook.Verify(secret, header, body, 5*time.Minute, now.Add(time.Minute)))
+	})
+
+	t.Run("одна из нескольких подписей верна", func(t *testing.T) {
+		header := signed(now, "v1=00, "+webhook.Sign(secret, now, body))
+		assert.NoError(t, webhook.Verify(secret, header, body, 5*time.Minute, now))
+	})
+
+	t.Run("ошибка: тело изменено", func(t *testing.T) {
+		header := signed(now, webhook.Sign(secret, now, body))
+		assert.ErrorIs(t, webhook.Verify(secret, header, []byte(`{"id":"2"}`), 5*time.Minute, now), webhook.ErrInvalidSignature)
+	})
+
+	t.Run("ошибка: другой ключ", func(t *testing.T) {
+		header := signed(now, webhook.Sign("whsec_other", now, body))
+		assert.ErrorIs(t, webhook.Verify(secret, header, body, 5*time.Minute, now), webhook.ErrInvalidSignature)
+	})
+
+	t.Run("ошибка: подпись устарела", func(t *testing.T) {
+		header := signed(now, webhook.Sign(secret, now, body))
+		assert.ErrorIs(t, webhook.Verify(secret, header, body, 5*time.Minute, now.Add(10*time.Minute)), webhook.ErrInvalidSignature)
+	})
+
+	t.Run("ошибка: время подменено", func(t *testing.T) {
+		header := signed(now.Add(time.Minute), webhook.Sign(secret, now, body))
+		assert.ErrorIs(t, webhook.Verify(secret, header, body, 5*time.Minute, now), webhook.ErrInvalidSignature)
+	})
+
+	t.Run("ошибка: нет заголовков", func(t *testing.T) {
+		assert.ErrorIs(t, webhook.Verify(secret, http.Header{}, body, 5*time.Minute, now), webhook.ErrInvalidSignature)
+	})
+}
@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"context"
+	"marketplace-api/internal/logging"
+	"time"
+)
+
+const deliverBatchSize = 20
+
+// Deliverer - фоновая задача, отправляющая уведомления партнёрам
+type Deliverer struct {
+	service  *Service
+	interval time.Duration
+}
+
+func NewDeliverer(service *Service, interval time.Duration) *Deliverer {
+	return &Deliverer{service: service, interval: interval}
+}
+
+// Run - запускает периодическую отправку до отмены ctx
+func (d *Deliverer) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		d.deliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Deliverer) deliverDue(ctx context.Context) {
+	for {
+		n, err := d.service.DeliverDue(ctx, deliverBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				logging.FromContext(ctx).Error("error delivering webhooks", "error", err)
+			}
+			return
+		}
+		if n < deliverBatchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE advertisements ADD COLUMN IF NOT EXISTS listing_type TEXT NOT NULL DEFAULT 'fixed';
+
+CREATE TABLE IF NOT EXISTS auctions (
+    advertisement_id UUID PRIMARY KEY REFERENCES advertisements(id) ON DELETE CASCADE,
+    ends_at TIMESTAMPTZ NOT NULL,
+    min_bid_increment_kopecks INTEGER NOT NULL,
+    buy_now_price_kopecks INTEGER,
+    reserve_price_kopecks INTEGER,
+    current_price_kopecks INTEGER NOT NULL,
+    highest_bidder_id UUID REFERENCES users(id),
+    bids_count INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'open',
+    winner_id UUID REFERENCES users(id),
+    closed_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS bids (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    advertisement_id UUID NOT NULL REFERENCES auctions(advertisement_id) ON DELETE CASCADE,
+    bidder_id UUID NOT NULL REFERENCES users(id),
+    amount_kopecks INTEGER NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_auctions_open_ends_at ON auctions(ends_at) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_bids_advertisement ON bids(advertisement_id, amount_kopecks DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS bids;
+DROP TABLE IF EXISTS auctions;
+ALTER TABLE advertisements DROP COLUMN IF EXISTS listing_type;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS advertisement_events (
+    id BIGSERIAL PRIMARY KEY,
+    advertisement_id UUID NOT NULL REFERENCES advertisements(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    viewer TEXT NOT NULL,
+    bucket_start TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (advertisement_id, kind, viewer, bucket_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_advertisement_events_created ON advertisement_events(advertisement_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS advertisement_events;
+-- +goose StatementEnd
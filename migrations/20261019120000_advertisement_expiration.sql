@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE advertisements
+    ADD COLUMN IF NOT EXISTS category TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'active',
+    ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS expiry_reminder_sent_at TIMESTAMPTZ;
+
+-- Уже размещённым объявлениям даётся полный срок с момента миграции
+UPDATE advertisements SET expires_at = now() + interval '30 days' WHERE expires_at IS NULL;
+ALTER TABLE advertisements ALTER COLUMN expires_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_advertisements_active_expires ON advertisements(expires_at) WHERE status = 'active';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_advertisements_active_expires;
+ALTER TABLE advertisements
+    DROP COLUMN IF EXISTS expiry_reminder_sent_at,
+    DROP COLUMN IF EXISTS expires_at,
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS category;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE advertisements
+    ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS published_at TIMESTAMPTZ;
+
+UPDATE advertisements SET published_at = created_at WHERE published_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_advertisements_published ON advertisements(published_at);
+CREATE INDEX IF NOT EXISTS idx_advertisements_scheduled ON advertisements(publish_at) WHERE status = 'scheduled';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_advertisements_scheduled;
+DROP INDEX IF EXISTS idx_advertisements_published;
+ALTER TABLE advertisements
+    DROP COLUMN IF EXISTS published_at,
+    DROP COLUMN IF EXISTS publish_at;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE advertisements
+    ADD COLUMN IF NOT EXISTS bumped_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS highlighted_until TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS promotions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    advertisement_id UUID NOT NULL REFERENCES advertisements(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    kind TEXT NOT NULL,
+    days INTEGER NOT NULL DEFAULT 0,
+    price_kopecks INTEGER NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    payment_id TEXT UNIQUE NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    paid_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotions_advertisement ON promotions(advertisement_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS promotions;
+ALTER TABLE advertisements
+    DROP COLUMN IF EXISTS highlighted_until,
+    DROP COLUMN IF EXISTS bumped_at;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE advertisements ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+UPDATE advertisements SET updated_at = COALESCE(created_at, now()) WHERE updated_at IS NULL;
+ALTER TABLE advertisements
+    ALTER COLUMN updated_at SET DEFAULT now(),
+    ALTER COLUMN updated_at SET NOT NULL;
+
+-- Любое изменение объявления сдвигает updated_at: по нему строятся Last-Modified и проверка If-Match
+CREATE OR REPLACE FUNCTION advertisements_touch_updated_at() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at = clock_timestamp();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS advertisements_updated_at ON advertisements;
+CREATE TRIGGER advertisements_updated_at
+    BEFORE UPDATE ON advertisements
+    FOR EACH ROW EXECUTE FUNCTION advertisements_touch_updated_at();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS advertisements_updated_at ON advertisements;
+DROP FUNCTION IF EXISTS advertisements_touch_updated_at();
+ALTER TABLE advertisements DROP COLUMN IF EXISTS updated_at;
+-- +goose StatementEnd
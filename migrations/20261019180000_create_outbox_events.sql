@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id UUID PRIMARY KEY,
+    seq BIGSERIAL NOT NULL,
+    event_type TEXT NOT NULL,
+    aggregate_type TEXT NOT NULL,
+    aggregate_id UUID NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_error TEXT,
+    published_at TIMESTAMPTZ,
+    failed_at TIMESTAMPTZ
+);
+
+-- Недоставленные события: очередь по порядку записи и проверка предыдущих событий агрегата
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events(seq)
+    WHERE published_at IS NULL AND failed_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending_aggregate ON outbox_events(aggregate_id, seq)
+    WHERE published_at IS NULL AND failed_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_published_at ON outbox_events(published_at)
+    WHERE published_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS outbox_events;
+-- +goose StatementEnd
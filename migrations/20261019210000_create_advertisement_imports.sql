@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE advertisements ADD COLUMN IF NOT EXISTS external_sku TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_advertisements_author_external_sku
+    ON advertisements(author_id, external_sku) WHERE external_sku IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS advertisement_imports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    format TEXT NOT NULL,
+    dry_run BOOLEAN NOT NULL DEFAULT false,
+    status TEXT NOT NULL,
+    total_rows INT NOT NULL,
+    processed INT NOT NULL DEFAULT 0,
+    created INT NOT NULL DEFAULT 0,
+    updated INT NOT NULL DEFAULT 0,
+    failed INT NOT NULL DEFAULT 0,
+    errors JSONB NOT NULL DEFAULT '[]',
+    error TEXT NOT NULL DEFAULT '',
+    payload BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    started_at TIMESTAMPTZ,
+    heartbeat_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_advertisement_imports_queue
+    ON advertisement_imports(created_at) WHERE status IN ('pending', 'running');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS advertisement_imports;
+DROP INDEX IF EXISTS idx_advertisements_author_external_sku;
+ALTER TABLE advertisements DROP COLUMN IF EXISTS external_sku;
+-- +goose StatementEnd